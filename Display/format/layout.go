@@ -0,0 +1,166 @@
+package format
+
+import (
+	"fmt"
+	"slices"
+	"sync"
+
+	dlo "github.com/PlayerR9/display/layout"
+	dtb "github.com/PlayerR9/display/table"
+	gcers "github.com/PlayerR9/go-commons/errors"
+)
+
+// Layout is a type that represents a format whose elements are sized by constraints
+// instead of being stacked with a fixed gap.
+type Layout[T gcers.Enumer] struct {
+	// m is the map of elements.
+	m map[T]dtb.Displayer
+
+	// constraints is the map of constraints of the elements.
+	constraints map[T]dlo.Constraint
+
+	// order is the order of the elements.
+	order []T
+
+	// direction is the axis along which the elements are placed.
+	direction dlo.Direction
+
+	// spacing is the space between two elements.
+	spacing int
+
+	// mu is the mutex of the layout.
+	mu sync.RWMutex
+}
+
+// Draw implements the drawtable.Displayer interface.
+//
+// The elements are drawn in the area that goes from the given coordinates to the
+// bottom-right corner of the table and each element is clipped to its own area. At
+// the end, the coordinates point right after the area of the last element.
+func (l *Layout[T]) Draw(table *dtb.Table, x, y *int) error {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	constraints := make([]dlo.Constraint, 0, len(l.order))
+
+	for _, key := range l.order {
+		constraints = append(constraints, l.constraints[key])
+	}
+
+	area := dlo.TableArea(table, *x, *y)
+
+	areas := dlo.SplitArea(area, l.direction, l.spacing, constraints)
+
+	for i, key := range l.order {
+		a := areas[i]
+
+		err := dtb.DrawClipped(table, l.m[key], a.X, a.Y, a.Width, a.Height)
+		if err != nil {
+			return fmt.Errorf("error drawing element %s: %w", key.String(), err)
+		}
+	}
+
+	if len(areas) == 0 {
+		return nil
+	}
+
+	last := areas[len(areas)-1]
+
+	if l.direction == dlo.Horizontal {
+		*x = last.X + last.Width
+	} else {
+		*y = last.Y + last.Height
+	}
+
+	return nil
+}
+
+// NewLayout returns a new layout.
+//
+// Parameters:
+//   - direction: The axis along which the elements are placed.
+//   - spacing: The space between two elements. Negative values are treated as 0.
+//
+// Returns:
+//   - *Layout: The new layout.
+func NewLayout[T gcers.Enumer](direction dlo.Direction, spacing int) *Layout[T] {
+	return &Layout[T]{
+		m:           make(map[T]dtb.Displayer),
+		constraints: make(map[T]dlo.Constraint),
+		direction:   direction,
+		spacing:     max(spacing, 0),
+	}
+}
+
+// AddElement adds an element to the layout. If an element with the same key already
+// exists, it is replaced but keeps its position.
+//
+// Parameters:
+//   - key: The key of the element.
+//   - elem: The element to add.
+//   - constraint: The constraint on the size of the element.
+func (l *Layout[T]) AddElement(key T, elem dtb.Displayer, constraint dlo.Constraint) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, ok := l.m[key]
+	if !ok {
+		l.order = append(l.order, key)
+	}
+
+	l.m[key] = elem
+	l.constraints[key] = constraint
+}
+
+// SetConstraint changes the constraint of an element.
+//
+// Parameters:
+//   - key: The key of the element.
+//   - constraint: The new constraint.
+//
+// Returns:
+//   - bool: True if the element exists, false otherwise.
+func (l *Layout[T]) SetConstraint(key T, constraint dlo.Constraint) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	_, ok := l.m[key]
+	if ok {
+		l.constraints[key] = constraint
+	}
+
+	return ok
+}
+
+// GetElement returns an element from the layout.
+//
+// Parameters:
+//   - key: The key of the element.
+//
+// Returns:
+//   - Displayer: The element.
+//   - bool: True if the element exists, false otherwise.
+func (l *Layout[T]) GetElement(key T) (dtb.Displayer, bool) {
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	elem, ok := l.m[key]
+	return elem, ok
+}
+
+// RemoveElement removes an element from the layout.
+//
+// Parameters:
+//   - key: The key of the element.
+func (l *Layout[T]) RemoveElement(key T) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	delete(l.m, key)
+	delete(l.constraints, key)
+
+	idx := slices.Index(l.order, key)
+	if idx != -1 {
+		l.order = slices.Delete(l.order, idx, idx+1)
+	}
+}
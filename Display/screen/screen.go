@@ -7,6 +7,8 @@ import (
 	"sync"
 	"time"
 
+	"github.com/PlayerR9/display/ansi"
+	"github.com/PlayerR9/display/colors"
 	dtb "github.com/PlayerR9/display/table"
 	gda "github.com/PlayerR9/go-debug/assert"
 	rws "github.com/PlayerR9/safe/rw_safe"
@@ -47,9 +49,28 @@ type Display struct {
 
 	// bgStyle is the background style of the display.
 	bgStyle tcell.Style
+
+	// depth is the colour depth every style is degraded to when drawn.
+	depth colors.Depth
+
+	// cursor is the position of the terminal cursor. Negative coordinates hide it.
+	cursor *rws.Safe[[2]int]
+
+	// reading is true while ListenForNumber reads a number.
+	reading bool
+
+	// digits are the digits typed so far while ListenForNumber reads a number. They
+	// are drawn on the last row.
+	digits string
+
+	// mu guards the table, the size, reading and digits, so that drawing, resizing
+	// and ListenForNumber do not race.
+	mu sync.Mutex
 }
 
-// NewDisplay creates a new display with the given background style.
+// NewDisplay creates a new display with the given background style. If the standard
+// output is not a terminal, the display falls back to line mode (see
+// ansi.LineScreen).
 //
 // Parameters:
 //   - bgStyle: The background style of the display.
@@ -58,7 +79,7 @@ type Display struct {
 //   - *Display: The new display.
 //   - error: An error if the display could not be created.
 func NewDisplay(bgStyle tcell.Style) (*Display, error) {
-	screen, err := tcell.NewScreen()
+	screen, err := ansi.NewScreen()
 	if err != nil {
 		return nil, err
 	}
@@ -68,7 +89,9 @@ func NewDisplay(bgStyle tcell.Style) (*Display, error) {
 		return nil, err
 	}
 
-	screen.SetStyle(bgStyle)
+	depth := colors.Detect(screen)
+
+	screen.SetStyle(colors.Degrade(bgStyle, depth))
 	screen.Clear()
 
 	width, height := screen.Size()
@@ -82,6 +105,8 @@ func NewDisplay(bgStyle tcell.Style) (*Display, error) {
 		height:  height,
 		table:   table,
 		bgStyle: bgStyle,
+		depth:   depth,
+		cursor:  rws.NewSafe([2]int{-1, -1}),
 	}, nil
 }
 
@@ -203,6 +228,9 @@ func (d *Display) ListenForKey() (rune, bool) {
 
 // resizeEvent is a helper method that handles a resize event.
 func (d *Display) resizeEvent() {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
 	d.width, d.height = d.screen.Size()
 
 	tmp, err := dtb.NewTable(d.width, d.height)
@@ -213,6 +241,8 @@ func (d *Display) resizeEvent() {
 
 // drawScreen is a helper method that draws the screen.
 func (d *Display) drawScreen() {
+	d.mu.Lock()
+
 	d.screen.Clear()
 
 	elem := d.element.Get()
@@ -223,7 +253,12 @@ func (d *Display) drawScreen() {
 
 		err := elem.Draw(d.table, &xCoord, &yCoord)
 		if err != nil {
-			d.errChan <- fmt.Errorf("error drawing element: %w", err)
+			// Never block while holding the lock; if an error is already pending, the
+			// new one is dropped.
+			select {
+			case d.errChan <- fmt.Errorf("error drawing element: %w", err):
+			default:
+			}
 		}
 
 		for row := range d.table.Row() {
@@ -234,18 +269,62 @@ func (d *Display) drawScreen() {
 					continue
 				}
 
-				d.screen.SetContent(j, yCoord, cell.Char, nil, cell.Style)
+				d.screen.SetContent(j, yCoord, cell.Char, nil, colors.Degrade(cell.Style, d.depth))
 			}
 
 			yCoord++
 		}
 	}
 
+	pos := d.cursor.Get()
+
+	if d.reading {
+		for i, r := range d.digits {
+			d.screen.SetContent(i, d.height-1, r, nil, colors.Degrade(d.bgStyle, d.depth))
+		}
+
+		pos = [2]int{len(d.digits), d.height - 1}
+	}
+
+	d.screen.ShowCursor(pos[0], pos[1])
+
 	d.screen.Show()
+
+	d.mu.Unlock()
+
 	time.Sleep(time.Millisecond * 100)
 }
 
-// ListenForNumber listens for a number.
+// ShowCursor shows the terminal cursor at the given position on every frame; for
+// instance, where the digits of ListenForNumber are typed.
+//
+// Parameters:
+//   - x: The x coordinate of the cursor.
+//   - y: The y coordinate of the cursor.
+func (d *Display) ShowCursor(x, y int) {
+	d.cursor.Set([2]int{x, y})
+}
+
+// HideCursor hides the terminal cursor.
+func (d *Display) HideCursor() {
+	d.cursor.Set([2]int{-1, -1})
+}
+
+// set_digits is a helper method that changes the digits drawn on the last row
+// while ListenForNumber reads a number. They are drawn by the next Draw.
+//
+// Parameters:
+//   - reading: True while a number is read.
+//   - digits: The digits typed so far.
+func (d *Display) set_digits(reading bool, digits string) {
+	d.mu.Lock()
+	d.reading = reading
+	d.digits = digits
+	d.mu.Unlock()
+}
+
+// ListenForNumber listens for a number. Until Enter is pressed, every Draw echoes
+// the digits typed so far on the last row, with the terminal cursor after them.
 //
 // Returns:
 //   - int: The number.
@@ -253,6 +332,9 @@ func (d *Display) drawScreen() {
 func (d *Display) ListenForNumber() (int, error) {
 	var builder strings.Builder
 
+	d.set_digits(true, "")
+	defer d.set_digits(false, "")
+
 	for {
 		key, ok := <-d.keyChan
 		if !ok {
@@ -271,6 +353,8 @@ func (d *Display) ListenForNumber() (int, error) {
 				builder.Reset()
 
 				builder.WriteString(str[:len(str)-1])
+
+				d.set_digits(true, builder.String())
 			}
 
 			continue
@@ -283,6 +367,8 @@ func (d *Display) ListenForNumber() (int, error) {
 		}
 
 		builder.WriteRune(r)
+
+		d.set_digits(true, builder.String())
 	}
 
 	num, err := strconv.Atoi(builder.String())
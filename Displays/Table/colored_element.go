@@ -2,6 +2,7 @@ package Table
 
 import (
 	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
 	tg "github.com/PlayerR9/table"
 	"github.com/gdamore/tcell"
 )
@@ -13,6 +14,19 @@ type ColoredElement[T Colorer] struct {
 
 	// style is the style of the color.
 	style tcell.Style
+
+	// role is the role of the style in the current theme. If empty, style is used
+	// instead.
+	role theme.Role
+}
+
+// get_style returns the style of the element.
+func (ce *ColoredElement[T]) get_style() tcell.Style {
+	if ce.role != "" {
+		return ce.role.Style()
+	}
+
+	return ce.style
 }
 
 // Draw is a method of cdd.TableDrawer that draws the unit to the table at the given x and y
@@ -43,6 +57,8 @@ func (ce *ColoredElement[T]) Draw(table *dtb.Table, x, y *int) error {
 		return nil
 	}
 
+	style := ce.get_style()
+
 	var offsetX int
 
 	for i, row := range runeTable {
@@ -58,7 +74,7 @@ func (ce *ColoredElement[T]) Draw(table *dtb.Table, x, y *int) error {
 			if r == EmptyRuneCell {
 				sequence = append(sequence, nil)
 			} else {
-				sequence = append(sequence, dtb.NewCell(r, ce.style))
+				sequence = append(sequence, dtb.NewCell(r, style))
 			}
 		}
 
@@ -88,6 +104,23 @@ func NewColoredElement[T Colorer](elem T, style tcell.Style) *ColoredElement[T]
 	}
 }
 
+// NewRoleElement creates a new ColoredElement whose style follows the given role of
+// the current theme.
+//
+// Parameters:
+//   - elem: The element of the color.
+//   - role: The role of the style.
+//
+// Returns:
+//   - *ColoredElement: The new ColoredElement.
+func NewRoleElement[T Colorer](elem T, role theme.Role) *ColoredElement[T] {
+	return &ColoredElement[T]{
+		elem:  elem,
+		style: tcell.StyleDefault,
+		role:  role,
+	}
+}
+
 // Apply applies the color to the element.
 //
 // Parameters:
@@ -110,13 +143,15 @@ func (ce *ColoredElement[T]) Apply(width, height int) ([][]*dtb.Cell, error) {
 		return nil, err
 	}
 
+	style := ce.get_style()
+
 	colorTable := make([][]*dtb.Cell, len(runeTable))
 
 	for _, row := range runeTable {
 		var colorRow []*dtb.Cell
 
 		for _, r := range row {
-			colorRow = append(colorRow, dtb.NewCell(r, ce.style))
+			colorRow = append(colorRow, dtb.NewCell(r, style))
 		}
 
 		colorTable = append(colorTable, colorRow)
@@ -0,0 +1,343 @@
+package anim
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+const (
+	// DefaultFrameRate is the default duration between two frames of an animator.
+	DefaultFrameRate time.Duration = time.Second / 30
+)
+
+// Animator drives animations and timers from a single (UI) goroutine.
+//
+// The animator does not run anything by itself: whenever something needs to happen
+// it calls its wake function, which is expected to eventually make the owner call
+// Step from the goroutine that owns the UI. While animations are running, the
+// animator wakes its owner once per frame; otherwise, it only wakes it when a timer
+// is due.
+type Animator struct {
+	// clock is the clock of the animator.
+	clock Clock
+
+	// frame_rate is the duration between two frames.
+	frame_rate time.Duration
+
+	// animations are the running animations.
+	animations []Animation
+
+	// due are the timer callbacks that are due.
+	due []func()
+
+	// wake is called whenever Step should be called.
+	wake func()
+
+	// frame is the scheduled wake-up of the next frame. Nil if none is scheduled.
+	frame Stopper
+
+	// mu is the mutex of the animator.
+	mu sync.Mutex
+}
+
+// NewAnimator creates a new animator.
+//
+// Parameters:
+//   - clock: The clock of the animator. If nil, RealClock is used.
+//   - frame_rate: The duration between two frames. If not positive,
+//     DefaultFrameRate is used.
+//
+// Returns:
+//   - *Animator: The new animator. Never returns nil.
+func NewAnimator(clock Clock, frame_rate time.Duration) *Animator {
+	if clock == nil {
+		clock = RealClock{}
+	}
+
+	if frame_rate <= 0 {
+		frame_rate = DefaultFrameRate
+	}
+
+	return &Animator{
+		clock:      clock,
+		frame_rate: frame_rate,
+	}
+}
+
+// Clock returns the clock of the animator.
+//
+// Returns:
+//   - Clock: The clock. Nil only if the receiver is nil.
+func (a *Animator) Clock() Clock {
+	if a == nil {
+		return nil
+	}
+
+	return a.clock
+}
+
+// SetWake sets the function called whenever Step should be called. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - fn: The function to call. It must not call Step itself and must not block.
+func (a *Animator) SetWake(fn func()) {
+	if a == nil {
+		return
+	}
+
+	a.mu.Lock()
+	a.wake = fn
+	a.mu.Unlock()
+}
+
+// Add starts running the given animation. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - animation: The animation to run. Nil animations are ignored.
+func (a *Animator) Add(animation Animation) {
+	if a == nil || animation == nil {
+		return
+	}
+
+	a.mu.Lock()
+	a.animations = append(a.animations, animation)
+	wake := a.wake
+	a.mu.Unlock()
+
+	if wake != nil {
+		wake()
+	}
+}
+
+// Remove stops running the given animation.
+//
+// Parameters:
+//   - animation: The animation to stop.
+//
+// Returns:
+//   - bool: True if the animation was running, false otherwise.
+func (a *Animator) Remove(animation Animation) bool {
+	if a == nil || animation == nil {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	idx := slices.Index(a.animations, animation)
+	if idx == -1 {
+		return false
+	}
+
+	a.animations = slices.Delete(a.animations, idx, idx+1)
+
+	return true
+}
+
+// IsActive checks whether there are running animations.
+//
+// Returns:
+//   - bool: True if there are running animations, false otherwise.
+func (a *Animator) IsActive() bool {
+	if a == nil {
+		return false
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	return len(a.animations) > 0
+}
+
+// After calls the function from Step once the duration elapsed.
+//
+// Parameters:
+//   - d: The duration to wait.
+//   - fn: The function to call. If nil, nothing is scheduled.
+//
+// Returns:
+//   - Stopper: The scheduled call. Nil if nothing was scheduled.
+func (a *Animator) After(d time.Duration, fn func()) Stopper {
+	if a == nil || fn == nil {
+		return nil
+	}
+
+	return a.clock.AfterFunc(d, func() {
+		a.push_due(fn)
+	})
+}
+
+// Every calls the function from Step every time the duration elapses, until the
+// returned call is stopped.
+//
+// Parameters:
+//   - d: The duration between two calls. Values less than 1 are treated as 1.
+//   - fn: The function to call. If nil, nothing is scheduled.
+//
+// Returns:
+//   - Stopper: The scheduled calls. Nil if nothing was scheduled.
+func (a *Animator) Every(d time.Duration, fn func()) Stopper {
+	if a == nil || fn == nil {
+		return nil
+	}
+
+	r := &repeat{
+		animator: a,
+		interval: max(d, 1),
+		fn:       fn,
+	}
+
+	r.schedule()
+
+	return r
+}
+
+// push_due is a helper method that queues a due callback and wakes the owner.
+//
+// Parameters:
+//   - fn: The callback.
+func (a *Animator) push_due(fn func()) {
+	a.mu.Lock()
+	a.due = append(a.due, fn)
+	wake := a.wake
+	a.mu.Unlock()
+
+	if wake != nil {
+		wake()
+	}
+}
+
+// Step calls the due timer callbacks and advances the running animations to the
+// current time of the clock. Finished animations are removed. If animations are
+// still running, the next frame is scheduled. Must be called from the goroutine that
+// owns the UI.
+//
+// Returns:
+//   - bool: True if anything happened and, thus, the UI should be redrawn.
+func (a *Animator) Step() bool {
+	if a == nil {
+		return false
+	}
+
+	a.mu.Lock()
+
+	due := a.due
+	a.due = nil
+
+	animations := slices.Clone(a.animations)
+
+	if a.frame != nil {
+		a.frame.Stop()
+		a.frame = nil
+	}
+
+	a.mu.Unlock()
+
+	for _, fn := range due {
+		fn()
+	}
+
+	now := a.clock.Now()
+
+	var finished []Animation
+
+	for _, animation := range animations {
+		if !animation.Step(now) {
+			finished = append(finished, animation)
+		}
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	if len(finished) > 0 {
+		a.animations = slices.DeleteFunc(a.animations, func(animation Animation) bool {
+			return slices.Contains(finished, animation)
+		})
+	}
+
+	if len(a.animations) > 0 && a.frame == nil {
+		a.frame = a.clock.AfterFunc(a.frame_rate, a.wake_frame)
+	}
+
+	return len(due) > 0 || len(animations) > 0
+}
+
+// wake_frame is a helper method that wakes the owner for the next frame.
+func (a *Animator) wake_frame() {
+	a.mu.Lock()
+	a.frame = nil
+	wake := a.wake
+	a.mu.Unlock()
+
+	if wake != nil {
+		wake()
+	}
+}
+
+// repeat is a call that repeats at a fixed interval.
+type repeat struct {
+	// animator is the animator the call is scheduled on.
+	animator *Animator
+
+	// interval is the duration between two calls.
+	interval time.Duration
+
+	// fn is the function to call.
+	fn func()
+
+	// next is the next scheduled call.
+	next Stopper
+
+	// stopped is true if the call was stopped.
+	stopped bool
+
+	// mu is the mutex of the call.
+	mu sync.Mutex
+}
+
+// schedule is a helper method that schedules the next call.
+func (r *repeat) schedule() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		return
+	}
+
+	r.next = r.animator.clock.AfterFunc(r.interval, func() {
+		r.animator.push_due(r.call)
+		r.schedule()
+	})
+}
+
+// call is a helper method that calls the function unless the call was stopped.
+func (r *repeat) call() {
+	r.mu.Lock()
+	stopped := r.stopped
+	r.mu.Unlock()
+
+	if !stopped {
+		r.fn()
+	}
+}
+
+// Stop implements the Stopper interface.
+func (r *repeat) Stop() bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.stopped {
+		return false
+	}
+
+	r.stopped = true
+
+	if r.next != nil {
+		r.next.Stop()
+	}
+
+	return true
+}
@@ -0,0 +1,124 @@
+package anim
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTween(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(0, 0))
+
+	animator := NewAnimator(clock, 10*time.Millisecond)
+
+	var wakes int
+
+	animator.SetWake(func() {
+		wakes++
+	})
+
+	var value int
+
+	animator.Add(NewTween(0, 100, 100*time.Millisecond, Linear, func(v int) {
+		value = v
+	}))
+
+	if wakes != 1 {
+		t.Fatalf("Expected adding an animation to wake the owner, but got %d wakes", wakes)
+	}
+
+	if !animator.Step() || value != 0 {
+		t.Fatalf("Expected value to be 0 at the start, but got %d", value)
+	}
+
+	clock.Advance(50 * time.Millisecond)
+
+	if wakes != 2 {
+		t.Fatalf("Expected one wake per elapsed frame, but got %d wakes", wakes)
+	}
+
+	animator.Step()
+
+	if value != 50 {
+		t.Fatalf("Expected value to be 50 halfway through, but got %d", value)
+	}
+
+	clock.Advance(60 * time.Millisecond)
+	animator.Step()
+
+	if value != 100 {
+		t.Fatalf("Expected value to be 100 at the end, but got %d", value)
+	}
+
+	if animator.IsActive() {
+		t.Fatalf("Expected animator to be idle once the tween finished")
+	}
+
+	clock.Advance(time.Second)
+
+	if wakes != 3 || animator.Step() {
+		t.Fatalf("Expected no frames while idle, but got %d wakes", wakes)
+	}
+}
+
+func TestTimers(t *testing.T) {
+	clock := NewVirtualClock(time.Unix(0, 0))
+
+	animator := NewAnimator(clock, 0)
+
+	var once, repeated int
+
+	animator.After(time.Second, func() {
+		once++
+	})
+
+	stopper := animator.Every(300*time.Millisecond, func() {
+		repeated++
+	})
+
+	clock.Advance(999 * time.Millisecond)
+	animator.Step()
+
+	if once != 0 || repeated != 3 {
+		t.Fatalf("Expected (0, 3) calls, but got (%d, %d)", once, repeated)
+	}
+
+	clock.Advance(time.Millisecond)
+	animator.Step()
+
+	if once != 1 {
+		t.Fatalf("Expected the timer to fire once, but got %d calls", once)
+	}
+
+	stopper.Stop()
+
+	clock.Advance(time.Second)
+	animator.Step()
+
+	if once != 1 || repeated != 3 {
+		t.Fatalf("Expected no more calls, but got (%d, %d)", once, repeated)
+	}
+}
+
+func TestEasing(t *testing.T) {
+	fns := map[string]EasingFn{
+		"Linear":         Linear,
+		"EaseInQuad":     EaseInQuad,
+		"EaseOutQuad":    EaseOutQuad,
+		"EaseInOutQuad":  EaseInOutQuad,
+		"EaseInCubic":    EaseInCubic,
+		"EaseOutCubic":   EaseOutCubic,
+		"EaseInOutCubic": EaseInOutCubic,
+		"EaseOutBounce":  EaseOutBounce,
+		"EaseInOutSine":  EaseInOutSine,
+	}
+
+	for name, fn := range fns {
+		if start := fn(0); start > 1e-9 || start < -1e-9 {
+			t.Errorf("Expected %s(0) to be 0, but got %f", name, start)
+		}
+
+		if end := fn(1); end > 1+1e-9 || end < 1-1e-9 {
+			t.Errorf("Expected %s(1) to be 1, but got %f", name, end)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package anim
+
+import (
+	"slices"
+	"sync"
+	"time"
+)
+
+// Stopper is a scheduled call that can be cancelled.
+type Stopper interface {
+	// Stop cancels the call.
+	//
+	// Returns:
+	//   - bool: True if the call was cancelled, false if it already happened or was
+	//     already cancelled.
+	Stop() bool
+}
+
+// Clock is a source of time.
+type Clock interface {
+	// Now returns the current time.
+	//
+	// Returns:
+	//   - time.Time: The current time.
+	Now() time.Time
+
+	// AfterFunc calls the function, in its own goroutine, once the duration elapsed.
+	//
+	// Parameters:
+	//   - d: The duration to wait.
+	//   - fn: The function to call. Assumed not nil.
+	//
+	// Returns:
+	//   - Stopper: The scheduled call. Never returns nil.
+	AfterFunc(d time.Duration, fn func()) Stopper
+}
+
+// RealClock is the clock of the system.
+type RealClock struct{}
+
+// Now implements the Clock interface.
+func (RealClock) Now() time.Time {
+	return time.Now()
+}
+
+// AfterFunc implements the Clock interface.
+func (RealClock) AfterFunc(d time.Duration, fn func()) Stopper {
+	return time.AfterFunc(d, fn)
+}
+
+// VirtualClock is a clock whose time only moves when told to. Useful for tests.
+type VirtualClock struct {
+	// now is the current time.
+	now time.Time
+
+	// calls are the scheduled calls that did not happen yet.
+	calls []*virtual_call
+
+	// mu is the mutex of the clock.
+	mu sync.Mutex
+}
+
+// virtual_call is a call scheduled on a virtual clock.
+type virtual_call struct {
+	// clock is the clock the call was scheduled on.
+	clock *VirtualClock
+
+	// at is the time at which the call happens.
+	at time.Time
+
+	// fn is the function to call.
+	fn func()
+}
+
+// Stop implements the Stopper interface.
+func (vc *virtual_call) Stop() bool {
+	vc.clock.mu.Lock()
+	defer vc.clock.mu.Unlock()
+
+	idx := slices.Index(vc.clock.calls, vc)
+	if idx == -1 {
+		return false
+	}
+
+	vc.clock.calls = slices.Delete(vc.clock.calls, idx, idx+1)
+
+	return true
+}
+
+// NewVirtualClock creates a new virtual clock.
+//
+// Parameters:
+//   - start: The initial time of the clock.
+//
+// Returns:
+//   - *VirtualClock: The new clock. Never returns nil.
+func NewVirtualClock(start time.Time) *VirtualClock {
+	return &VirtualClock{
+		now: start,
+	}
+}
+
+// Now implements the Clock interface.
+func (c *VirtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return c.now
+}
+
+// AfterFunc implements the Clock interface.
+//
+// Unlike the real clock, the function is called synchronously by Advance.
+func (c *VirtualClock) AfterFunc(d time.Duration, fn func()) Stopper {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	call := &virtual_call{
+		clock: c,
+		at:    c.now.Add(d),
+		fn:    fn,
+	}
+
+	c.calls = append(c.calls, call)
+
+	return call
+}
+
+// Advance moves the clock forward by the given duration and, in chronological
+// order, makes every scheduled call that is due. The time of the clock is set to
+// the time of each call while it happens.
+//
+// Parameters:
+//   - d: The duration to move forward. Negative durations do nothing.
+func (c *VirtualClock) Advance(d time.Duration) {
+	if d < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	end := c.now.Add(d)
+	c.mu.Unlock()
+
+	for {
+		c.mu.Lock()
+
+		idx := -1
+
+		for i, call := range c.calls {
+			if call.at.After(end) {
+				continue
+			}
+
+			if idx == -1 || call.at.Before(c.calls[idx].at) {
+				idx = i
+			}
+		}
+
+		if idx == -1 {
+			c.now = end
+			c.mu.Unlock()
+
+			return
+		}
+
+		call := c.calls[idx]
+		c.calls = slices.Delete(c.calls, idx, idx+1)
+
+		if call.at.After(c.now) {
+			c.now = call.at
+		}
+
+		c.mu.Unlock()
+
+		call.fn()
+	}
+}
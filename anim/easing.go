@@ -0,0 +1,89 @@
+package anim
+
+import "math"
+
+// EasingFn is a function that maps the progress of an animation to the progress of
+// the animated value.
+//
+// Parameters:
+//   - t: The progress of the animation, between 0 and 1.
+//
+// Returns:
+//   - float64: The progress of the value. 0 at the start and 1 at the end, but may
+//     go beyond those bounds in between.
+type EasingFn func(t float64) float64
+
+// Linear moves at a constant speed.
+func Linear(t float64) float64 {
+	return t
+}
+
+// EaseInQuad starts slowly and accelerates.
+func EaseInQuad(t float64) float64 {
+	return t * t
+}
+
+// EaseOutQuad starts quickly and decelerates.
+func EaseOutQuad(t float64) float64 {
+	return t * (2 - t)
+}
+
+// EaseInOutQuad accelerates until halfway through and then decelerates.
+func EaseInOutQuad(t float64) float64 {
+	if t < 0.5 {
+		return 2 * t * t
+	}
+
+	return -1 + (4-2*t)*t
+}
+
+// EaseInCubic starts slowly and accelerates, more sharply than EaseInQuad.
+func EaseInCubic(t float64) float64 {
+	return t * t * t
+}
+
+// EaseOutCubic starts quickly and decelerates, more sharply than EaseOutQuad.
+func EaseOutCubic(t float64) float64 {
+	t--
+
+	return t*t*t + 1
+}
+
+// EaseInOutCubic accelerates until halfway through and then decelerates, more
+// sharply than EaseInOutQuad.
+func EaseInOutCubic(t float64) float64 {
+	if t < 0.5 {
+		return 4 * t * t * t
+	}
+
+	t = 2*t - 2
+
+	return (t*t*t + 2) / 2
+}
+
+// EaseOutBounce decelerates and bounces at the end.
+func EaseOutBounce(t float64) float64 {
+	const (
+		n1 = 7.5625
+		d1 = 2.75
+	)
+
+	switch {
+	case t < 1/d1:
+		return n1 * t * t
+	case t < 2/d1:
+		t -= 1.5 / d1
+		return n1*t*t + 0.75
+	case t < 2.5/d1:
+		t -= 2.25 / d1
+		return n1*t*t + 0.9375
+	default:
+		t -= 2.625 / d1
+		return n1*t*t + 0.984375
+	}
+}
+
+// EaseInOutSine accelerates and decelerates following a sine curve.
+func EaseInOutSine(t float64) float64 {
+	return -(math.Cos(math.Pi*t) - 1) / 2
+}
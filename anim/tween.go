@@ -0,0 +1,236 @@
+package anim
+
+import (
+	"math"
+	"time"
+)
+
+// Number is the set of numeric types that can be animated.
+type Number interface {
+	~int | ~int8 | ~int16 | ~int32 | ~int64 |
+		~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64 |
+		~float32 | ~float64
+}
+
+// Animation is an animation driven by an Animator.
+type Animation interface {
+	// Step advances the animation to the given time.
+	//
+	// Parameters:
+	//   - now: The current time.
+	//
+	// Returns:
+	//   - bool: True if the animation is still running, false if it finished.
+	Step(now time.Time) bool
+}
+
+// Tween is an animation that moves a value from one number to another over a
+// duration.
+type Tween[T Number] struct {
+	// from is the initial value.
+	from T
+
+	// to is the final value.
+	to T
+
+	// duration is the duration of the animation.
+	duration time.Duration
+
+	// easing is the easing function of the animation.
+	easing EasingFn
+
+	// on_update is called with the new value at each step.
+	on_update func(value T)
+
+	// start is the time of the first step.
+	start time.Time
+}
+
+// NewTween creates a new tween. The animation starts at its first step.
+//
+// Parameters:
+//   - from: The initial value.
+//   - to: The final value.
+//   - duration: The duration of the animation.
+//   - easing: The easing function. If nil, Linear is used.
+//   - on_update: The function called with the new value at each step. Can be nil.
+//
+// Returns:
+//   - *Tween: The new tween. Never returns nil.
+func NewTween[T Number](from, to T, duration time.Duration, easing EasingFn, on_update func(value T)) *Tween[T] {
+	if easing == nil {
+		easing = Linear
+	}
+
+	return &Tween[T]{
+		from:      from,
+		to:        to,
+		duration:  duration,
+		easing:    easing,
+		on_update: on_update,
+	}
+}
+
+// Progress returns the progress of the animation at the given time.
+//
+// Parameters:
+//   - now: The current time.
+//
+// Returns:
+//   - float64: The progress, between 0 and 1.
+func (t *Tween[T]) Progress(now time.Time) float64 {
+	if t == nil || t.start.IsZero() {
+		return 0
+	} else if t.duration <= 0 {
+		return 1
+	}
+
+	p := float64(now.Sub(t.start)) / float64(t.duration)
+
+	return min(max(p, 0), 1)
+}
+
+// Value returns the value at the given time.
+//
+// Parameters:
+//   - now: The current time.
+//
+// Returns:
+//   - T: The value. Integer values are rounded to the nearest integer.
+func (t *Tween[T]) Value(now time.Time) T {
+	if t == nil {
+		return 0
+	}
+
+	p := t.easing(t.Progress(now))
+
+	v := float64(t.from) + (float64(t.to)-float64(t.from))*p
+
+	switch any(t.from).(type) {
+	case float32, float64:
+		return T(v)
+	default:
+		return T(math.Round(v))
+	}
+}
+
+// Step implements the Animation interface.
+func (t *Tween[T]) Step(now time.Time) bool {
+	if t == nil {
+		return false
+	}
+
+	if t.start.IsZero() {
+		t.start = now
+	}
+
+	if t.on_update != nil {
+		t.on_update(t.Value(now))
+	}
+
+	return t.Progress(now) < 1
+}
+
+// Frames is an animation that cycles through a sequence of frames (e.g., the
+// characters of a spinner) at a fixed interval until it is stopped.
+type Frames[T any] struct {
+	// frames are the frames of the animation.
+	frames []T
+
+	// interval is the duration of each frame.
+	interval time.Duration
+
+	// on_frame is called with the current frame whenever it changes.
+	on_frame func(frame T)
+
+	// start is the time of the first step.
+	start time.Time
+
+	// last is the index of the last frame reported.
+	last int
+
+	// stopped is true if the animation was stopped.
+	stopped bool
+}
+
+// NewFrames creates a new frame animation.
+//
+// Parameters:
+//   - frames: The frames of the animation.
+//   - interval: The duration of each frame. Values less than 1 are treated as 1.
+//   - on_frame: The function called whenever the frame changes. Can be nil.
+//
+// Returns:
+//   - *Frames: The new animation. Never returns nil.
+func NewFrames[T any](frames []T, interval time.Duration, on_frame func(frame T)) *Frames[T] {
+	return &Frames[T]{
+		frames:   frames,
+		interval: max(interval, 1),
+		on_frame: on_frame,
+		last:     -1,
+	}
+}
+
+// Frame returns the frame at the given time.
+//
+// Parameters:
+//   - now: The current time.
+//
+// Returns:
+//   - T: The frame. The zero value if there are no frames.
+func (f *Frames[T]) Frame(now time.Time) T {
+	if f == nil || len(f.frames) == 0 {
+		return *new(T)
+	}
+
+	return f.frames[f.index(now)]
+}
+
+// index is a helper method that returns the index of the frame at the given time.
+//
+// Parameters:
+//   - now: The current time.
+//
+// Returns:
+//   - int: The index of the frame.
+func (f *Frames[T]) index(now time.Time) int {
+	if f.start.IsZero() {
+		return 0
+	}
+
+	n := int(now.Sub(f.start) / f.interval)
+
+	return max(n, 0) % len(f.frames)
+}
+
+// Stop stops the animation at its next step. Does nothing with a nil receiver.
+func (f *Frames[T]) Stop() {
+	if f == nil {
+		return
+	}
+
+	f.stopped = true
+}
+
+// Step implements the Animation interface.
+func (f *Frames[T]) Step(now time.Time) bool {
+	if f == nil || f.stopped || len(f.frames) == 0 {
+		return false
+	}
+
+	if f.start.IsZero() {
+		f.start = now
+	}
+
+	idx := f.index(now)
+
+	if idx != f.last {
+		f.last = idx
+
+		if f.on_frame != nil {
+			f.on_frame(f.frames[idx])
+		}
+	}
+
+	return true
+}
@@ -0,0 +1,105 @@
+package ansi
+
+import (
+	"io"
+	"strings"
+
+	"github.com/PlayerR9/display/colors"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+// FormatRow formats a row of cells as a line of text. Trailing blank cells are
+// trimmed and nil cells are written as spaces.
+//
+// Parameters:
+//   - row: The row of cells.
+//   - depth: The colour depth of the output. With colors.DepthMono, no escape
+//     sequences are written.
+//
+// Returns:
+//   - string: The line, without a trailing newline.
+func FormatRow(row []*dtb.Cell, depth colors.Depth) string {
+	end := len(row)
+
+	for end > 0 && is_blank(row[end-1], depth) {
+		end--
+	}
+
+	var builder strings.Builder
+
+	current := tcell.StyleDefault
+
+	for _, cell := range row[:end] {
+		char, style := ' ', tcell.StyleDefault
+
+		if cell != nil {
+			char, style = cell.Char, cell.Style
+
+			if char == 0 {
+				char = ' '
+			}
+		}
+
+		if depth != colors.DepthMono && style != current {
+			builder.WriteString(SGR(style, depth))
+			current = style
+		}
+
+		builder.WriteRune(char)
+	}
+
+	if current != tcell.StyleDefault {
+		builder.WriteString(Reset)
+	}
+
+	return builder.String()
+}
+
+// is_blank checks whether a cell does not show anything.
+//
+// Parameters:
+//   - cell: The cell.
+//   - depth: The colour depth of the output.
+//
+// Returns:
+//   - bool: True if the cell is nil or a space whose style is not visible.
+func is_blank(cell *dtb.Cell, depth colors.Depth) bool {
+	if cell == nil {
+		return true
+	} else if cell.Char != ' ' && cell.Char != 0 {
+		return false
+	}
+
+	if depth == colors.DepthMono {
+		return true
+	}
+
+	_, bg, attrs := cell.Style.Decompose()
+
+	return bg == tcell.ColorDefault && attrs&(tcell.AttrReverse|tcell.AttrUnderline) == 0
+}
+
+// WriteTable writes every row of the table as a line of text. See FormatRow.
+//
+// Parameters:
+//   - w: The writer.
+//   - table: The table to write.
+//   - depth: The colour depth of the output.
+//
+// Returns:
+//   - error: An error if the table could not be written.
+func WriteTable(w io.Writer, table *dtb.Table, depth colors.Depth) error {
+	if table == nil {
+		return nil
+	}
+
+	for row := range table.Row() {
+		_, err := io.WriteString(w, FormatRow(row, depth)+"\n")
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
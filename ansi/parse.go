@@ -0,0 +1,223 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// TabWidth is the number of columns between two tab stops.
+	TabWidth int = 8
+)
+
+// apply_sgr is a helper function that applies the parameters of an SGR sequence to
+// a style. Unknown parameters are ignored.
+//
+// Parameters:
+//   - style: The current style.
+//   - base: The style that resets go back to.
+//   - params: The parameters, separated by semicolons.
+//
+// Returns:
+//   - tcell.Style: The new style.
+func apply_sgr(style, base tcell.Style, params string) tcell.Style {
+	base_fg, base_bg, _ := base.Decompose()
+
+	var codes []int
+
+	for _, field := range strings.Split(params, ";") {
+		n, err := strconv.Atoi(field)
+		if err != nil {
+			n = 0
+		}
+
+		codes = append(codes, n)
+	}
+
+	// extended parses the colour of a 38 or 48 code, starting at codes[i].
+	extended := func(i int) (tcell.Color, int, bool) {
+		if i+1 >= len(codes) {
+			return 0, len(codes), false
+		}
+
+		switch codes[i+1] {
+		case 5:
+			if i+2 < len(codes) {
+				return tcell.Color(codes[i+2] & 0xff), i + 2, true
+			}
+		case 2:
+			if i+4 < len(codes) {
+				return tcell.NewRGBColor(int32(codes[i+2]), int32(codes[i+3]), int32(codes[i+4])), i + 4, true
+			}
+		}
+
+		return 0, len(codes), false
+	}
+
+	for i := 0; i < len(codes); i++ {
+		code := codes[i]
+
+		switch {
+		case code == 0:
+			style = base
+		case code == 1:
+			style = style.Bold(true)
+		case code == 2:
+			style = style.Dim(true)
+		case code == 3:
+			style = style.Italic(true)
+		case code == 4:
+			style = style.Underline(true)
+		case code == 5 || code == 6:
+			style = style.Blink(true)
+		case code == 7:
+			style = style.Reverse(true)
+		case code == 22:
+			style = style.Bold(false).Dim(false)
+		case code == 23:
+			style = style.Italic(false)
+		case code == 24:
+			style = style.Underline(false)
+		case code == 25:
+			style = style.Blink(false)
+		case code == 27:
+			style = style.Reverse(false)
+		case code >= 30 && code <= 37:
+			style = style.Foreground(tcell.Color(code - 30))
+		case code == 38:
+			c, next, ok := extended(i)
+			if ok {
+				style = style.Foreground(c)
+			}
+
+			i = next
+		case code == 39:
+			style = style.Foreground(base_fg)
+		case code >= 40 && code <= 47:
+			style = style.Background(tcell.Color(code - 40))
+		case code == 48:
+			c, next, ok := extended(i)
+			if ok {
+				style = style.Background(c)
+			}
+
+			i = next
+		case code == 49:
+			style = style.Background(base_bg)
+		case code >= 90 && code <= 97:
+			style = style.Foreground(tcell.Color(code - 90 + 8))
+		case code >= 100 && code <= 107:
+			style = style.Background(tcell.Color(code - 100 + 8))
+		}
+	}
+
+	return style
+}
+
+// skip_escape is a helper function that returns the length of the escape sequence
+// at the start of the text.
+//
+// Parameters:
+//   - text: The text, starting with an escape character.
+//
+// Returns:
+//   - int: The length of the sequence, in bytes.
+//   - string: The parameters of the sequence if it is an SGR sequence.
+//   - bool: True if it is an SGR sequence.
+func skip_escape(text string) (int, string, bool) {
+	if len(text) < 2 {
+		return len(text), "", false
+	}
+
+	switch text[1] {
+	case '[':
+		// CSI: parameter and intermediate bytes up to a final byte in [0x40, 0x7e].
+		for i := 2; i < len(text); i++ {
+			if text[i] >= 0x40 && text[i] <= 0x7e {
+				return i + 1, text[2:i], text[i] == 'm'
+			}
+		}
+
+		return len(text), "", false
+	case ']':
+		// OSC: up to BEL or ST.
+		for i := 2; i < len(text); i++ {
+			if text[i] == '\a' {
+				return i + 1, "", false
+			} else if text[i] == '\x1b' && i+1 < len(text) && text[i+1] == '\\' {
+				return i + 2, "", false
+			}
+		}
+
+		return len(text), "", false
+	default:
+		return 2, "", false
+	}
+}
+
+// Parse turns a line with ANSI escape sequences into cells. SGR sequences change
+// the style of the following cells; other escape sequences and control characters
+// are dropped. Tabs are expanded to the next multiple of TabWidth.
+//
+// Parameters:
+//   - line: The line.
+//   - base: The style of the cells before any sequence, and after a reset.
+//
+// Returns:
+//   - []*dtb.Cell: The cells.
+func Parse(line string, base tcell.Style) []*dtb.Cell {
+	cells := make([]*dtb.Cell, 0, len(line))
+	style := base
+
+	for len(line) > 0 {
+		if line[0] == '\x1b' {
+			n, params, ok := skip_escape(line)
+			if ok {
+				style = apply_sgr(style, base, params)
+			}
+
+			line = line[n:]
+			continue
+		}
+
+		r, size := utf8.DecodeRuneInString(line)
+		line = line[size:]
+
+		switch {
+		case r == '\t':
+			for range TabWidth - len(cells)%TabWidth {
+				cells = append(cells, dtb.NewCell(' ', style))
+			}
+		case r < 0x20 || r == 0x7f:
+			// Other control characters are dropped.
+		default:
+			cells = append(cells, dtb.NewCell(r, style))
+		}
+	}
+
+	return cells
+}
+
+// Strip removes the escape sequences and control characters of a line, as Parse
+// does, and returns its text.
+//
+// Parameters:
+//   - line: The line.
+//
+// Returns:
+//   - string: The text of the line.
+func Strip(line string) string {
+	cells := Parse(line, tcell.StyleDefault)
+
+	var builder strings.Builder
+
+	for _, cell := range cells {
+		builder.WriteRune(cell.Char)
+	}
+
+	return builder.String()
+}
@@ -0,0 +1,403 @@
+package ansi
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+	"sync"
+
+	"github.com/PlayerR9/display/colors"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// DefaultWidth is the width of line screens when COLUMNS is not set.
+	DefaultWidth int = 80
+
+	// DefaultHeight is the height of line screens when LINES is not set.
+	DefaultHeight int = 24
+)
+
+// LineScreen is a tcell.Screen for outputs that are not terminals (pipes, files,
+// CI logs). It keeps the content in memory and, on every Show, writes the rows of
+// the frame as lines of plain or ANSI text. When the new frame only appends rows
+// to the previous one, just the new rows are written; otherwise, the whole frame
+// is written again.
+//
+// Input is read line by line: each line is delivered as one key event per rune,
+// followed by tcell.KeyEnter. The end of the input is delivered as tcell.KeyCtrlD.
+type LineScreen struct {
+	tcell.SimulationScreen
+
+	// in is the line reader of the input. Nil if there is no input.
+	in *bufio.Scanner
+
+	// out is the output.
+	out io.Writer
+
+	// depth is the colour depth of the output.
+	depth colors.Depth
+
+	// width and height are the size of the screen.
+	width, height int
+
+	// events is the channel of the events returned by PollEvent.
+	events chan tcell.Event
+
+	// quit is closed when the screen is finalised.
+	quit chan struct{}
+
+	// lines is the channel of the lines read from the input.
+	lines chan string
+
+	// read_once starts reading the input once for the whole life of the screen,
+	// since a blocked read cannot be interrupted on suspend.
+	read_once sync.Once
+
+	// forwarders waits for the goroutines started by Init to stop, so that the
+	// screen can be initialized again once finalized.
+	forwarders sync.WaitGroup
+
+	// pending are the key events of the input not delivered yet. Only used by
+	// forward_lines.
+	pending []tcell.Event
+
+	// eof is true once the end of the input was read. Only used by forward_lines.
+	eof bool
+
+	// last is the last frame written.
+	last []string
+
+	// mu is the mutex of the screen.
+	mu sync.Mutex
+}
+
+// NewLineScreen creates a new line screen. Its size is given by the COLUMNS and
+// LINES environment variables, if set.
+//
+// Parameters:
+//   - in: The input. Can be nil.
+//   - out: The output. Must not be nil.
+//   - depth: The colour depth of the output.
+//
+// Returns:
+//   - *LineScreen: The new line screen. Never returns nil.
+func NewLineScreen(in io.Reader, out io.Writer, depth colors.Depth) *LineScreen {
+	ls := &LineScreen{
+		SimulationScreen: tcell.NewSimulationScreen("UTF-8"),
+		out:              out,
+		depth:            depth,
+		width:            env_size("COLUMNS", DefaultWidth),
+		height:           env_size("LINES", DefaultHeight),
+		lines:            make(chan string),
+	}
+
+	if in != nil {
+		ls.in = bufio.NewScanner(in)
+	}
+
+	return ls
+}
+
+// env_size returns the positive integer stored in an environment variable.
+//
+// Parameters:
+//   - name: The name of the variable.
+//   - def: The value to use if the variable is not a positive integer.
+//
+// Returns:
+//   - int: The size.
+func env_size(name string, def int) int {
+	n, err := strconv.Atoi(os.Getenv(name))
+	if err != nil || n <= 0 {
+		return def
+	}
+
+	return n
+}
+
+// Init implements the tcell.Screen interface.
+func (ls *LineScreen) Init() error {
+	err := ls.SimulationScreen.Init()
+	if err != nil {
+		return err
+	}
+
+	ls.SimulationScreen.SetSize(ls.width, ls.height)
+
+	quit := make(chan struct{})
+
+	ls.mu.Lock()
+	ls.events = make(chan tcell.Event)
+	ls.quit = quit
+	ls.last = nil
+	ls.mu.Unlock()
+
+	if ls.in != nil {
+		ls.read_once.Do(func() {
+			go ls.read_lines()
+		})
+	}
+
+	ls.forwarders.Add(2)
+
+	go ls.forward_events(ls.events, quit)
+	go ls.forward_lines(ls.events, quit)
+
+	return nil
+}
+
+// read_lines is a helper method that reads the input line by line until its end.
+func (ls *LineScreen) read_lines() {
+	defer close(ls.lines)
+
+	for ls.in.Scan() {
+		ls.lines <- ls.in.Text()
+	}
+}
+
+// forward_events is a helper method that forwards the events of the simulation
+// screen (resizes, posted events, ...).
+//
+// Parameters:
+//   - events: The channel to forward the events to.
+//   - quit: The channel that, once closed, stops forwarding.
+func (ls *LineScreen) forward_events(events chan tcell.Event, quit chan struct{}) {
+	defer ls.forwarders.Done()
+
+	for {
+		ev := ls.SimulationScreen.PollEvent()
+		if ev == nil {
+			return
+		}
+
+		select {
+		case events <- ev:
+		case <-quit:
+			return
+		}
+	}
+}
+
+// forward_lines is a helper method that converts the lines of the input to key
+// events. The events of a line that are not delivered when the screen is
+// finalized are kept and delivered once it is initialized again.
+//
+// Parameters:
+//   - events: The channel to forward the events to.
+//   - quit: The channel that, once closed, stops forwarding.
+func (ls *LineScreen) forward_lines(events chan tcell.Event, quit chan struct{}) {
+	defer ls.forwarders.Done()
+
+	if ls.in == nil {
+		return
+	}
+
+	for {
+		for len(ls.pending) > 0 {
+			select {
+			case events <- ls.pending[0]:
+				ls.pending = ls.pending[1:]
+			case <-quit:
+				return
+			}
+		}
+
+		if ls.eof {
+			return
+		}
+
+		var line string
+		var ok bool
+
+		select {
+		case line, ok = <-ls.lines:
+		case <-quit:
+			return
+		}
+
+		if !ok {
+			ls.pending = append(ls.pending, tcell.NewEventKey(tcell.KeyCtrlD, 0, tcell.ModNone))
+			ls.eof = true
+
+			continue
+		}
+
+		for _, r := range line {
+			ls.pending = append(ls.pending, tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+		}
+
+		ls.pending = append(ls.pending, tcell.NewEventKey(tcell.KeyEnter, '\r', tcell.ModNone))
+	}
+}
+
+// Fini implements the tcell.Screen interface.
+func (ls *LineScreen) Fini() {
+	ls.mu.Lock()
+
+	if ls.quit != nil {
+		close(ls.quit)
+		ls.quit = nil
+	}
+
+	ls.mu.Unlock()
+
+	ls.SimulationScreen.Fini()
+
+	ls.forwarders.Wait()
+}
+
+// PollEvent implements the tcell.Screen interface.
+func (ls *LineScreen) PollEvent() tcell.Event {
+	ls.mu.Lock()
+	events, quit := ls.events, ls.quit
+	ls.mu.Unlock()
+
+	if quit == nil {
+		return nil
+	}
+
+	select {
+	case ev := <-events:
+		return ev
+	case <-quit:
+		return nil
+	}
+}
+
+// Colors implements the tcell.Screen interface.
+func (ls *LineScreen) Colors() int {
+	switch ls.depth {
+	case colors.DepthTrueColor:
+		return 1 << 24
+	case colors.Depth256:
+		return 256
+	case colors.Depth16:
+		return 16
+	case colors.Depth8:
+		return 8
+	default:
+		return 0
+	}
+}
+
+// Show implements the tcell.Screen interface.
+func (ls *LineScreen) Show() {
+	ls.SimulationScreen.Show()
+
+	ls.mu.Lock()
+	defer ls.mu.Unlock()
+
+	frame := ls.frame()
+
+	if slices.Equal(frame, ls.last) {
+		return
+	}
+
+	lines := frame
+
+	if len(frame) > len(ls.last) && slices.Equal(frame[:len(ls.last)], ls.last) {
+		lines = frame[len(ls.last):]
+	}
+
+	for _, line := range lines {
+		_, err := io.WriteString(ls.out, line+"\n")
+		if err != nil {
+			break
+		}
+	}
+
+	ls.last = frame
+}
+
+// Sync implements the tcell.Screen interface.
+func (ls *LineScreen) Sync() {
+	ls.Show()
+}
+
+// frame is a helper method that formats the content of the screen as lines,
+// without the trailing empty ones.
+//
+// Returns:
+//   - []string: The lines.
+func (ls *LineScreen) frame() []string {
+	cells, width, height := ls.SimulationScreen.GetContents()
+
+	frame := make([]string, 0, height)
+	row := make([]*dtb.Cell, width)
+
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			cell := cells[y*width+x]
+
+			if len(cell.Runes) == 0 {
+				row[x] = nil
+			} else {
+				row[x] = dtb.NewCell(cell.Runes[0], cell.Style)
+			}
+		}
+
+		frame = append(frame, FormatRow(row, ls.depth))
+	}
+
+	for len(frame) > 0 && frame[len(frame)-1] == "" {
+		frame = frame[:len(frame)-1]
+	}
+
+	return frame
+}
+
+// IsTerminal checks whether a file is a terminal.
+//
+// Parameters:
+//   - f: The file.
+//
+// Returns:
+//   - bool: True if the file is a terminal.
+func IsTerminal(f *os.File) bool {
+	if f == nil {
+		return false
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+
+	return info.Mode()&os.ModeCharDevice != 0
+}
+
+// OutputDepth returns the colour depth to use for an output that is not a
+// terminal: colors.DepthMono unless FORCE_COLOR or CLICOLOR_FORCE is set, in which
+// case the depth is detected from the environment (see colors.Detect).
+//
+// Returns:
+//   - colors.Depth: The colour depth.
+func OutputDepth() colors.Depth {
+	if os.Getenv("FORCE_COLOR") == "" && os.Getenv("CLICOLOR_FORCE") == "" {
+		return colors.DepthMono
+	}
+
+	return colors.Detect(nil)
+}
+
+// NewScreen creates a tcell screen for the standard output: a terminal screen if
+// the standard output is a terminal that tcell supports and, otherwise, a
+// LineScreen that reads from the standard input.
+//
+// Returns:
+//   - tcell.Screen: The new screen.
+//   - error: An error if the screen could not be created.
+func NewScreen() (tcell.Screen, error) {
+	if IsTerminal(os.Stdout) {
+		screen, err := tcell.NewScreen()
+		if err == nil {
+			return screen, nil
+		}
+	}
+
+	return NewLineScreen(os.Stdin, os.Stdout, OutputDepth()), nil
+}
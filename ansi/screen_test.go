@@ -0,0 +1,101 @@
+package ansi
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/PlayerR9/display/colors"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+// TestLineScreen tests that frames are written line by line and that the input is
+// delivered as key events.
+func TestLineScreen(t *testing.T) {
+	t.Setenv("COLUMNS", "10")
+	t.Setenv("LINES", "4")
+
+	var out bytes.Buffer
+
+	ls := NewLineScreen(strings.NewReader("42\n"), &out, colors.DepthMono)
+
+	err := ls.Init()
+	if err != nil {
+		t.Fatalf("expected no error, got %v", err)
+	}
+
+	defer ls.Fini()
+
+	put := func(y int, text string) {
+		for x, r := range text {
+			ls.SetContent(x, y, r, nil, tcell.StyleDefault.Bold(true))
+		}
+	}
+
+	ls.Clear()
+	put(0, "hello")
+	ls.Show()
+	ls.Show()
+
+	put(1, "world")
+	ls.Show()
+
+	if got, want := out.String(), "hello\nworld\n"; got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+
+	want := []tcell.Key{tcell.KeyRune, tcell.KeyRune, tcell.KeyEnter, tcell.KeyCtrlD}
+
+	for i, key := range want {
+		ev, ok := ls.PollEvent().(*tcell.EventKey)
+		if !ok || ev.Key() != key {
+			t.Fatalf("event %d: want key %v, got %v", i, key, ev)
+		}
+	}
+}
+
+// TestFormatRow tests the escape sequences of styled rows.
+func TestFormatRow(t *testing.T) {
+	red := tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true)
+
+	row := []*dtb.Cell{
+		dtb.NewCell('a', red),
+		dtb.NewCell('b', red),
+		dtb.NewCell('c', tcell.StyleDefault),
+		dtb.NewCell(' ', tcell.StyleDefault),
+		nil,
+	}
+
+	got := FormatRow(row, colors.Depth16)
+	want := "\x1b[0;1;91mab\x1b[0mc"
+
+	if got != want {
+		t.Errorf("want %q, got %q", want, got)
+	}
+}
+
+// TestParse tests the parsing of escape sequences into cells.
+func TestParse(t *testing.T) {
+	cells := Parse("a\x1b[1;38;5;200mb\x1b]0;title\a\x1b[39mc\x1b[0m\td", tcell.StyleDefault)
+
+	if len(cells) != 9 {
+		t.Fatalf("want 9 cells, got %d", len(cells))
+	}
+
+	want := []tcell.Style{
+		tcell.StyleDefault,
+		tcell.StyleDefault.Bold(true).Foreground(tcell.Color(200)),
+		tcell.StyleDefault.Bold(true),
+	}
+
+	for i, style := range want {
+		if cells[i].Style != style {
+			t.Errorf("cell %d: want %v, got %v", i, style, cells[i].Style)
+		}
+	}
+
+	if got := Strip("a\x1b[31mb\x1b[0m\x07c"); got != "abc" {
+		t.Errorf("want %q, got %q", "abc", got)
+	}
+}
@@ -0,0 +1,86 @@
+package ansi
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/PlayerR9/display/colors"
+	"github.com/gdamore/tcell"
+)
+
+// Reset is the escape sequence that resets every attribute and colour.
+const Reset = "\x1b[0m"
+
+// color_code returns the SGR parameters of a colour.
+//
+// Parameters:
+//   - c: The colour. Assumed to be already degraded.
+//   - base: 30 for foregrounds and 40 for backgrounds.
+//
+// Returns:
+//   - string: The parameters. Empty for the default colour.
+func color_code(c tcell.Color, base int) string {
+	if c == tcell.ColorDefault {
+		return ""
+	}
+
+	if c&tcell.ColorIsRGB == 0 && c >= 0 && c < 256 {
+		switch {
+		case c < 8:
+			return strconv.Itoa(base + int(c))
+		case c < 16:
+			return strconv.Itoa(base + 60 + int(c) - 8)
+		default:
+			return strconv.Itoa(base+8) + ";5;" + strconv.Itoa(int(c))
+		}
+	}
+
+	r, g, b := c.RGB()
+	if r < 0 {
+		return ""
+	}
+
+	return strconv.Itoa(base+8) + ";2;" + strconv.Itoa(int(r)) + ";" + strconv.Itoa(int(g)) + ";" + strconv.Itoa(int(b))
+}
+
+// SGR returns the escape sequence that selects the given style, after degrading
+// it to the given colour depth. The sequence always starts by resetting the
+// previous style.
+//
+// Parameters:
+//   - style: The style.
+//   - depth: The colour depth of the output.
+//
+// Returns:
+//   - string: The escape sequence.
+func SGR(style tcell.Style, depth colors.Depth) string {
+	fg, bg, attrs := colors.Degrade(style, depth).Decompose()
+
+	params := []string{"0"}
+
+	for _, attr := range [...]struct {
+		mask tcell.AttrMask
+		code string
+	}{
+		{tcell.AttrBold, "1"},
+		{tcell.AttrDim, "2"},
+		{tcell.AttrItalic, "3"},
+		{tcell.AttrUnderline, "4"},
+		{tcell.AttrBlink, "5"},
+		{tcell.AttrReverse, "7"},
+	} {
+		if attrs&attr.mask != 0 {
+			params = append(params, attr.code)
+		}
+	}
+
+	if code := color_code(fg, 30); code != "" {
+		params = append(params, code)
+	}
+
+	if code := color_code(bg, 40); code != "" {
+		params = append(params, code)
+	}
+
+	return "\x1b[" + strings.Join(params, ";") + "m"
+}
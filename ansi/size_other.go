@@ -0,0 +1,21 @@
+//go:build !(linux || darwin || dragonfly || freebsd || netbsd || openbsd)
+
+package ansi
+
+import (
+	"os"
+)
+
+// TerminalSize returns the size of a terminal. The size is never known on this
+// platform.
+//
+// Parameters:
+//   - f: The terminal.
+//
+// Returns:
+//   - int: The number of columns.
+//   - int: The number of rows.
+//   - bool: Always false.
+func TerminalSize(f *os.File) (int, int, bool) {
+	return 0, 0, false
+}
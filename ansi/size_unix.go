@@ -0,0 +1,39 @@
+//go:build linux || darwin || dragonfly || freebsd || netbsd || openbsd
+
+package ansi
+
+import (
+	"os"
+	"syscall"
+	"unsafe"
+)
+
+// winsize is the size of a terminal, as returned by the TIOCGWINSZ ioctl.
+type winsize struct {
+	rows, cols, x_pixels, y_pixels uint16
+}
+
+// TerminalSize returns the size of a terminal.
+//
+// Parameters:
+//   - f: The terminal.
+//
+// Returns:
+//   - int: The number of columns.
+//   - int: The number of rows.
+//   - bool: True if the size is known, false otherwise; for instance, if f is not
+//     a terminal.
+func TerminalSize(f *os.File) (int, int, bool) {
+	if f == nil {
+		return 0, 0, false
+	}
+
+	var ws winsize
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, f.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&ws)))
+	if errno != 0 || ws.cols == 0 || ws.rows == 0 {
+		return 0, 0, false
+	}
+
+	return int(ws.cols), int(ws.rows), true
+}
@@ -0,0 +1,399 @@
+package canvas
+
+import (
+	"sync"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// Mode is the way the pixels of a canvas are mapped to the cells of a table.
+type Mode int
+
+const (
+	// Braille packs 2x4 pixels in each cell with braille characters. All the pixels
+	// of a cell share one colour.
+	Braille Mode = iota
+
+	// HalfBlock packs 1x2 pixels in each cell with half-block characters. Each pixel
+	// has its own colour.
+	HalfBlock
+)
+
+// String implements the fmt.Stringer interface.
+func (m Mode) String() string {
+	switch m {
+	case Braille:
+		return "braille"
+	case HalfBlock:
+		return "half-block"
+	default:
+		return "unknown"
+	}
+}
+
+// cell_size returns the number of pixels per cell of the mode.
+//
+// Returns:
+//   - int: The number of pixels per cell, horizontally.
+//   - int: The number of pixels per cell, vertically.
+func (m Mode) cell_size() (int, int) {
+	if m == HalfBlock {
+		return 1, 2
+	}
+
+	return 2, 4
+}
+
+// braille_dots are the bits of the dots of a braille character, by column and row
+// of the dot within the cell.
+var braille_dots = [2][4]rune{
+	{0x01, 0x02, 0x04, 0x40},
+	{0x08, 0x10, 0x20, 0x80},
+}
+
+// Canvas is a grid of pixels drawn on a table with sub-cell characters. Pixels are
+// addressed from the top-left corner; the ones outside of the canvas are ignored.
+// It is a table.Displayer and is safe for concurrent use.
+type Canvas struct {
+	// mode is the mode of the canvas.
+	mode Mode
+
+	// width and height are the size of the canvas, in cells.
+	width, height int
+
+	// on tells whether each pixel is set, row by row.
+	on []bool
+
+	// colors are the colours of each pixel, row by row. In braille mode, only the
+	// colour of the last pixel set in a cell is kept, on every pixel of the cell.
+	colors []tcell.Color
+
+	// color is the colour of the pixels that are set.
+	color tcell.Color
+
+	// role is the role of the style of the cells.
+	role theme.Role
+
+	// mu is the mutex of the canvas.
+	mu sync.Mutex
+}
+
+// New creates a new, empty canvas.
+//
+// Parameters:
+//   - width: The width of the canvas, in cells.
+//   - height: The height of the canvas, in cells.
+//   - mode: The mode of the canvas.
+//
+// Returns:
+//   - *Canvas: The new canvas. Never returns nil.
+func New(width, height int, mode Mode) *Canvas {
+	c := &Canvas{
+		mode:  mode,
+		color: tcell.ColorDefault,
+		role:  theme.RoleChart,
+	}
+
+	c.resize(width, height)
+
+	return c
+}
+
+// resize is a helper function that resizes and clears the canvas.
+//
+// Parameters:
+//   - width: The width of the canvas, in cells.
+//   - height: The height of the canvas, in cells.
+func (c *Canvas) resize(width, height int) {
+	c.width, c.height = max(width, 0), max(height, 0)
+
+	pw, ph := c.mode.cell_size()
+	n := c.width * pw * c.height * ph
+
+	c.on = make([]bool, n)
+	c.colors = make([]tcell.Color, n)
+}
+
+// Mode returns the mode of the canvas.
+//
+// Returns:
+//   - Mode: The mode. Braille with a nil receiver.
+func (c *Canvas) Mode() Mode {
+	if c == nil {
+		return Braille
+	}
+
+	return c.mode
+}
+
+// Size returns the size of the canvas, in pixels.
+//
+// Returns:
+//   - int: The width. 0 with a nil receiver.
+//   - int: The height. 0 with a nil receiver.
+func (c *Canvas) Size() (int, int) {
+	if c == nil {
+		return 0, 0
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pw, ph := c.mode.cell_size()
+
+	return c.width * pw, c.height * ph
+}
+
+// Resize changes the size of the canvas and clears it. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - width: The width of the canvas, in cells.
+//   - height: The height of the canvas, in cells.
+func (c *Canvas) Resize(width, height int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.resize(width, height)
+	c.mu.Unlock()
+}
+
+// SetColor sets the colour of the pixels drawn from now on. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - color: The colour. tcell.ColorDefault for the foreground of the role of the
+//     canvas.
+func (c *Canvas) SetColor(color tcell.Color) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.color = color
+	c.mu.Unlock()
+}
+
+// SetRole sets the role of the style of the cells. The colours of the pixels
+// replace its foreground and, for the lower pixel of full half-block cells, its
+// background. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - role: The role. Empty for theme.RoleChart.
+func (c *Canvas) SetRole(role theme.Role) {
+	if c == nil {
+		return
+	}
+
+	if role == "" {
+		role = theme.RoleChart
+	}
+
+	c.mu.Lock()
+	c.role = role
+	c.mu.Unlock()
+}
+
+// Clear turns all the pixels off. Does nothing with a nil receiver.
+func (c *Canvas) Clear() {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	clear(c.on)
+	clear(c.colors)
+}
+
+// index is a helper function that returns the index of a pixel.
+//
+// Parameters:
+//   - x: The x coordinate of the pixel.
+//   - y: The y coordinate of the pixel.
+//
+// Returns:
+//   - int: The index of the pixel.
+//   - bool: False if the pixel is outside of the canvas.
+func (c *Canvas) index(x, y int) (int, bool) {
+	pw, ph := c.mode.cell_size()
+	w, h := c.width*pw, c.height*ph
+
+	if x < 0 || y < 0 || x >= w || y >= h {
+		return 0, false
+	}
+
+	return y*w + x, true
+}
+
+// set is a helper function that turns a pixel on with the current colour.
+//
+// Parameters:
+//   - x: The x coordinate of the pixel.
+//   - y: The y coordinate of the pixel.
+func (c *Canvas) set(x, y int) {
+	idx, ok := c.index(x, y)
+	if !ok {
+		return
+	}
+
+	c.on[idx] = true
+
+	if c.mode == HalfBlock {
+		c.colors[idx] = c.color
+		return
+	}
+
+	// All the pixels of a braille cell share the colour.
+	w := c.width * 2
+	cx, cy := x-x%2, y-y%4
+
+	for dy := range 4 {
+		for dx := range 2 {
+			c.colors[(cy+dy)*w+cx+dx] = c.color
+		}
+	}
+}
+
+// Set turns a pixel on with the current colour. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - x: The x coordinate of the pixel.
+//   - y: The y coordinate of the pixel.
+func (c *Canvas) Set(x, y int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.set(x, y)
+	c.mu.Unlock()
+}
+
+// Unset turns a pixel off. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - x: The x coordinate of the pixel.
+//   - y: The y coordinate of the pixel.
+func (c *Canvas) Unset(x, y int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	idx, ok := c.index(x, y)
+	if ok {
+		c.on[idx] = false
+	}
+}
+
+// Get tells whether a pixel is on.
+//
+// Parameters:
+//   - x: The x coordinate of the pixel.
+//   - y: The y coordinate of the pixel.
+//
+// Returns:
+//   - bool: True if the pixel is on. False if it is outside of the canvas or with a
+//     nil receiver.
+func (c *Canvas) Get(x, y int) bool {
+	if c == nil {
+		return false
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	idx, ok := c.index(x, y)
+
+	return ok && c.on[idx]
+}
+
+// Draw implements the table.Displayer interface. Cells without pixels are left
+// untouched and the coordinates are left at the last row of the canvas.
+func (c *Canvas) Draw(table *dtb.Table, x, y *int) error {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.width == 0 || c.height == 0 {
+		return nil
+	}
+
+	base := c.role.Style()
+	fg, _, _ := base.Decompose()
+
+	color_of := func(idx int) tcell.Color {
+		if c.colors[idx] == tcell.ColorDefault {
+			return fg
+		}
+
+		return c.colors[idx]
+	}
+
+	left, top := *x, *y
+	*y += c.height - 1
+
+	switch c.mode {
+	case HalfBlock:
+		w := c.width
+
+		for cy := range c.height {
+			for cx := range c.width {
+				upper := (2*cy)*w + cx
+				lower := upper + w
+
+				var cell *dtb.Cell
+
+				switch {
+				case c.on[upper] && c.on[lower]:
+					cell = dtb.NewCell('▀', base.Foreground(color_of(upper)).Background(color_of(lower)))
+				case c.on[upper]:
+					cell = dtb.NewCell('▀', base.Foreground(color_of(upper)))
+				case c.on[lower]:
+					cell = dtb.NewCell('▄', base.Foreground(color_of(lower)))
+				default:
+					continue
+				}
+
+				table.WriteAt(left+cx, top+cy, cell)
+			}
+		}
+	default:
+		w := c.width * 2
+
+		for cy := range c.height {
+			for cx := range c.width {
+				var dots rune
+
+				for dy := range 4 {
+					for dx := range 2 {
+						if c.on[(4*cy+dy)*w+2*cx+dx] {
+							dots |= braille_dots[dx][dy]
+						}
+					}
+				}
+
+				if dots == 0 {
+					continue
+				}
+
+				style := base.Foreground(color_of((4*cy)*w + 2*cx))
+
+				table.WriteAt(left+cx, top+cy, dtb.NewCell(0x2800+dots, style))
+			}
+		}
+	}
+
+	return nil
+}
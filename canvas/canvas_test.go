@@ -0,0 +1,66 @@
+package canvas
+
+import (
+	"image"
+	"testing"
+
+	"github.com/PlayerR9/display/internal/drawtest"
+	"github.com/gdamore/tcell"
+)
+
+func TestBraille(t *testing.T) {
+	c := New(2, 1, Braille)
+
+	if w, h := c.Size(); w != 4 || h != 4 {
+		t.Fatalf("Expected a size of 4x4, but got %dx%d", w, h)
+	}
+
+	c.Line(0, 0, 3, 3)
+
+	drawtest.CheckLines(t, drawtest.Draw(t, c, 2, 1).GetLines(), []string{"⠑⢄"})
+
+	c.Clear()
+	c.Rect(0, 0, 4, 4)
+
+	drawtest.CheckLines(t, drawtest.Draw(t, c, 2, 1).GetLines(), []string{"⣏⣹"})
+}
+
+func TestHalfBlock(t *testing.T) {
+	c := New(3, 2, HalfBlock)
+	c.SetColor(tcell.ColorRed)
+	c.Rect(0, 0, 3, 4)
+	c.SetColor(tcell.ColorBlue)
+	c.Fill(1, 1)
+
+	table := drawtest.Draw(t, c, 3, 2)
+
+	drawtest.CheckLines(t, table.GetLines(), []string{"▀▀▀", "▀▀▀"})
+
+	fg, bg, _ := table.CellAt(1, 0).Style.Decompose()
+	if fg != tcell.ColorRed || bg != tcell.ColorBlue {
+		t.Errorf("Expected red over blue, but got %v over %v", fg, bg)
+	}
+}
+
+func TestShapes(t *testing.T) {
+	c := New(5, 5, HalfBlock)
+	c.Circle(2, 4, 2)
+
+	for _, p := range []image.Point{{0, 4}, {4, 4}, {2, 2}, {2, 6}} {
+		if !c.Get(p.X, p.Y) {
+			t.Errorf("Expected pixel %v to be on", p)
+		}
+	}
+
+	if c.Get(2, 4) {
+		t.Errorf("Expected the centre of the circle to be off")
+	}
+
+	c.Clear()
+	c.Polygon(image.Point{0, 0}, image.Point{4, 0}, image.Point{0, 4})
+	c.Fill(1, 1)
+
+	if !c.Get(1, 2) || c.Get(4, 4) {
+		t.Errorf("Expected only the inside of the triangle to be filled")
+	}
+}
@@ -0,0 +1,215 @@
+package canvas
+
+import (
+	"image"
+)
+
+// abs is a helper function that returns the absolute value of an integer.
+//
+// Parameters:
+//   - n: The integer.
+//
+// Returns:
+//   - int: The absolute value.
+func abs(n int) int {
+	if n < 0 {
+		return -n
+	}
+
+	return n
+}
+
+// line is a helper function that draws a line with Bresenham's algorithm.
+//
+// Parameters:
+//   - x0, y0: The first end of the line.
+//   - x1, y1: The second end of the line.
+func (c *Canvas) line(x0, y0, x1, y1 int) {
+	dx := abs(x1 - x0)
+	dy := -abs(y1 - y0)
+
+	sx, sy := 1, 1
+
+	if x0 > x1 {
+		sx = -1
+	}
+
+	if y0 > y1 {
+		sy = -1
+	}
+
+	err := dx + dy
+
+	for {
+		c.set(x0, y0)
+
+		if x0 == x1 && y0 == y1 {
+			return
+		}
+
+		e2 := 2 * err
+
+		if e2 >= dy {
+			err += dy
+			x0 += sx
+		}
+
+		if e2 <= dx {
+			err += dx
+			y0 += sy
+		}
+	}
+}
+
+// Line draws a line with Bresenham's algorithm. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - x0, y0: The first end of the line.
+//   - x1, y1: The second end of the line.
+func (c *Canvas) Line(x0, y0, x1, y1 int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	c.line(x0, y0, x1, y1)
+	c.mu.Unlock()
+}
+
+// Rect draws the outline of a rectangle. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - x, y: The top-left corner of the rectangle.
+//   - width, height: The size of the rectangle. Nothing is drawn if either is not
+//     positive.
+func (c *Canvas) Rect(x, y, width, height int) {
+	if c == nil || width <= 0 || height <= 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	x1, y1 := x+width-1, y+height-1
+
+	c.line(x, y, x1, y)
+	c.line(x, y1, x1, y1)
+	c.line(x, y, x, y1)
+	c.line(x1, y, x1, y1)
+}
+
+// FillRect draws a filled rectangle. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - x, y: The top-left corner of the rectangle.
+//   - width, height: The size of the rectangle. Nothing is drawn if either is not
+//     positive.
+func (c *Canvas) FillRect(x, y, width, height int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for py := y; py < y+height; py++ {
+		for px := x; px < x+width; px++ {
+			c.set(px, py)
+		}
+	}
+}
+
+// Circle draws the outline of a circle with the midpoint algorithm. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - cx, cy: The centre of the circle.
+//   - r: The radius of the circle. Nothing is drawn if it is negative.
+func (c *Canvas) Circle(cx, cy, r int) {
+	if c == nil || r < 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	x, y := r, 0
+	err := 1 - r
+
+	for x >= y {
+		c.set(cx+x, cy+y)
+		c.set(cx+y, cy+x)
+		c.set(cx-y, cy+x)
+		c.set(cx-x, cy+y)
+		c.set(cx-x, cy-y)
+		c.set(cx-y, cy-x)
+		c.set(cx+y, cy-x)
+		c.set(cx+x, cy-y)
+
+		y++
+
+		if err < 0 {
+			err += 2*y + 1
+		} else {
+			x--
+			err += 2*(y-x) + 1
+		}
+	}
+}
+
+// Polygon draws the outline of a closed polygon. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - points: The vertices of the polygon, in order. The last one is joined to the
+//     first one.
+func (c *Canvas) Polygon(points ...image.Point) {
+	if c == nil || len(points) == 0 {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	prev := points[len(points)-1]
+
+	for _, p := range points {
+		c.line(prev.X, prev.Y, p.X, p.Y)
+		prev = p
+	}
+}
+
+// Fill turns on the pixels that are off and 4-connected to the given pixel through
+// pixels that are off. Does nothing if the pixel is on or with a nil receiver.
+//
+// Parameters:
+//   - x: The x coordinate of the pixel.
+//   - y: The y coordinate of the pixel.
+func (c *Canvas) Fill(x, y int) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	stack := []image.Point{{X: x, Y: y}}
+
+	for len(stack) > 0 {
+		p := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		idx, ok := c.index(p.X, p.Y)
+		if !ok || c.on[idx] {
+			continue
+		}
+
+		c.set(p.X, p.Y)
+
+		stack = append(stack,
+			image.Point{X: p.X + 1, Y: p.Y},
+			image.Point{X: p.X - 1, Y: p.Y},
+			image.Point{X: p.X, Y: p.Y + 1},
+			image.Point{X: p.X, Y: p.Y - 1},
+		)
+	}
+}
@@ -0,0 +1,396 @@
+package chart
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"sync"
+
+	dlo "github.com/PlayerR9/display/layout"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// DefaultBarWidth is the width of the bars of vertical bar charts.
+const DefaultBarWidth int = 3
+
+// partial_blocks are the characters that fill one to seven eighths of a cell, from
+// the left.
+var partial_blocks = [...]rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉'}
+
+// Bar is a bar of a bar chart.
+type Bar struct {
+	// Label is the text next to the bar.
+	Label string
+
+	// Value is the value of the bar. Negative values are drawn as empty bars.
+	Value float64
+
+	// Role is the role of the style of the bar. Empty for the "chart.<n>" role of
+	// its position.
+	Role theme.Role
+}
+
+// format_value is a helper function that formats a value compactly.
+//
+// Parameters:
+//   - v: The value.
+//
+// Returns:
+//   - string: The formatted value.
+func format_value(v float64) string {
+	return strconv.FormatFloat(v, 'g', 4, 64)
+}
+
+// series_role is a helper function that returns the default role of the n-th
+// series or bar of a chart.
+//
+// Parameters:
+//   - n: The position of the series.
+//
+// Returns:
+//   - theme.Role: The role.
+func series_role(n int) theme.Role {
+	return theme.RoleChart.Child(strconv.Itoa(n % theme.ChartColors))
+}
+
+// BarChart is a chart of labelled bars, either horizontal (growing from left to
+// right, one per row) or vertical (growing from bottom to top, labels below). It
+// is a table.Displayer. With a window size, pushing bars drops the oldest ones.
+type BarChart struct {
+	// bars holds the bars, from the oldest to the newest.
+	bars []Bar
+
+	// size is the maximum number of bars. Non-positive for no limit.
+	size int
+
+	// direction is the direction the bars are laid out in: dlo.Horizontal for
+	// horizontal bars.
+	direction dlo.Direction
+
+	// max is the value of a full bar. Non-positive to follow the largest value.
+	max float64
+
+	// width and height are the size of the chart. Non-positive to fill the table.
+	width, height int
+
+	// bar_width is the width of vertical bars.
+	bar_width int
+
+	// mu is the mutex of the chart.
+	mu sync.Mutex
+}
+
+// NewBarChart creates a new, empty bar chart.
+//
+// Parameters:
+//   - direction: dlo.Horizontal for horizontal bars and dlo.Vertical for vertical
+//     ones.
+//
+// Returns:
+//   - *BarChart: The new chart. Never returns nil.
+func NewBarChart(direction dlo.Direction) *BarChart {
+	return &BarChart{
+		direction: direction,
+		bar_width: DefaultBarWidth,
+	}
+}
+
+// SetBars replaces the bars of the chart. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - bars: The bars.
+func (bc *BarChart) SetBars(bars ...Bar) {
+	if bc == nil {
+		return
+	}
+
+	bc.mu.Lock()
+
+	bc.bars = slices.Clone(bars)
+	bc.trim()
+
+	bc.mu.Unlock()
+}
+
+// Push adds bars after the others, dropping the oldest ones beyond the window
+// size. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - bars: The bars.
+func (bc *BarChart) Push(bars ...Bar) {
+	if bc == nil {
+		return
+	}
+
+	bc.mu.Lock()
+
+	bc.bars = append(bc.bars, bars...)
+	bc.trim()
+
+	bc.mu.Unlock()
+}
+
+// SetWindow changes the maximum number of bars. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - size: The number of bars. Non-positive for no limit.
+func (bc *BarChart) SetWindow(size int) {
+	if bc == nil {
+		return
+	}
+
+	bc.mu.Lock()
+
+	bc.size = size
+	bc.trim()
+
+	bc.mu.Unlock()
+}
+
+// trim is a helper method that drops the oldest bars beyond the window size.
+//
+// Assertions:
+//   - bc.mu is locked.
+func (bc *BarChart) trim() {
+	if bc.size > 0 && len(bc.bars) > bc.size {
+		bc.bars = slices.Delete(bc.bars, 0, len(bc.bars)-bc.size)
+	}
+}
+
+// SetMax changes the value of a full bar. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - max: The value. Non-positive to follow the largest value.
+func (bc *BarChart) SetMax(max float64) {
+	if bc == nil {
+		return
+	}
+
+	bc.mu.Lock()
+	bc.max = max
+	bc.mu.Unlock()
+}
+
+// SetSize changes the size of the chart. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - width: The width. Non-positive to fill the table from the x coordinate.
+//   - height: The height. Non-positive to fill the table from the y coordinate.
+func (bc *BarChart) SetSize(width, height int) {
+	if bc == nil {
+		return
+	}
+
+	bc.mu.Lock()
+	bc.width, bc.height = width, height
+	bc.mu.Unlock()
+}
+
+// SetBarWidth changes the width of vertical bars. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - width: The width. Non-positive values mean DefaultBarWidth.
+func (bc *BarChart) SetBarWidth(width int) {
+	if bc == nil {
+		return
+	}
+
+	if width <= 0 {
+		width = DefaultBarWidth
+	}
+
+	bc.mu.Lock()
+	bc.bar_width = width
+	bc.mu.Unlock()
+}
+
+// full is a helper method that returns the value of a full bar.
+//
+// Parameters:
+//   - bars: The drawn bars.
+//
+// Returns:
+//   - float64: The value.
+//
+// Assertions:
+//   - bc.mu is locked.
+func (bc *BarChart) full(bars []Bar) float64 {
+	if bc.max > 0 {
+		return bc.max
+	}
+
+	full := 0.0
+
+	for _, bar := range bars {
+		full = max(full, bar.Value)
+	}
+
+	return full
+}
+
+// Draw implements the table.Displayer interface. The chart is drawn from the given
+// coordinates, which are left at its last row.
+func (bc *BarChart) Draw(table *dtb.Table, x, y *int) error {
+	bc.mu.Lock()
+	defer bc.mu.Unlock()
+
+	width, height := bc.width, bc.height
+
+	if width <= 0 {
+		width = table.Width() - *x
+	}
+
+	if height <= 0 {
+		height = table.Height() - *y
+	}
+
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	if bc.direction == dlo.Horizontal {
+		bc.draw_horizontal(table, *x, *y, width, height)
+	} else {
+		bc.draw_vertical(table, *x, *y, width, height)
+	}
+
+	*y += height - 1
+
+	return nil
+}
+
+// draw_horizontal is a helper method that draws the newest bars that fit, one per
+// row, with their label on the left and their value on the right.
+//
+// Parameters:
+//   - table: The table.
+//   - x, y: The top-left corner of the chart.
+//   - width, height: The size of the chart.
+//
+// Assertions:
+//   - bc.mu is locked.
+func (bc *BarChart) draw_horizontal(table *dtb.Table, x, y, width, height int) {
+	bars := bc.bars[max(len(bc.bars)-height, 0):]
+	full := bc.full(bars)
+
+	label_width, value_width := 0, 0
+
+	for _, bar := range bars {
+		label_width = max(label_width, len([]rune(bar.Label)))
+		value_width = max(value_width, len(format_value(bar.Value)))
+	}
+
+	label_width = min(label_width, width/3)
+	muted := theme.RoleMuted.Style()
+
+	for row, bar := range bars {
+		write(table, x, y+row, bar.Label, label_width, muted)
+
+		role := bar.Role
+		if role == "" {
+			role = series_role(len(bc.bars) - len(bars) + row)
+		}
+
+		style := role.Style()
+
+		start := x + label_width + 1
+		length := width - label_width - value_width - 2
+		eighths := int(scale(bar.Value, 0, full) * float64(max(length, 0)*8))
+
+		if full <= 0 {
+			eighths = 0
+		}
+
+		for i := range max(length, 0) {
+			r := ' '
+
+			switch {
+			case i < eighths/8:
+				r = '█'
+			case i == eighths/8 && eighths%8 > 0:
+				r = partial_blocks[eighths%8-1]
+			}
+
+			table.WriteAt(start+i, y+row, dtb.NewCell(r, style))
+		}
+
+		value := format_value(bar.Value)
+		write(table, x+width-len(value), y+row, value, len(value), muted)
+	}
+}
+
+// draw_vertical is a helper method that draws the newest bars that fit, side by
+// side, with their label below.
+//
+// Parameters:
+//   - table: The table.
+//   - x, y: The top-left corner of the chart.
+//   - width, height: The size of the chart.
+//
+// Assertions:
+//   - bc.mu is locked.
+func (bc *BarChart) draw_vertical(table *dtb.Table, x, y, width, height int) {
+	count := (width + 1) / (bc.bar_width + 1)
+
+	bars := bc.bars[max(len(bc.bars)-count, 0):]
+	full := bc.full(bars)
+
+	plot := height - 1
+	muted := theme.RoleMuted.Style()
+
+	for i, bar := range bars {
+		col := x + i*(bc.bar_width+1)
+
+		write(table, col, y+height-1, bar.Label, bc.bar_width, muted)
+
+		role := bar.Role
+		if role == "" {
+			role = series_role(len(bc.bars) - len(bars) + i)
+		}
+
+		style := role.Style()
+
+		eighths := 0
+		if full > 0 {
+			eighths = int(math.Round(scale(bar.Value, 0, full) * float64(plot*8)))
+		}
+
+		for row := range plot {
+			// Rows are counted from the bottom.
+			filled := eighths - row*8
+			if filled <= 0 {
+				break
+			}
+
+			r := levels[min(filled, 8)-1]
+
+			for k := range bc.bar_width {
+				table.WriteAt(col+k, y+plot-1-row, dtb.NewCell(r, style))
+			}
+		}
+	}
+}
+
+// write is a helper function that writes text on a row, cut to a width.
+//
+// Parameters:
+//   - table: The table.
+//   - x, y: The coordinates of the first character.
+//   - text: The text.
+//   - width: The maximum number of characters.
+//   - style: The style of the text.
+func write(table *dtb.Table, x, y int, text string, width int, style tcell.Style) {
+	i := 0
+
+	for _, r := range text {
+		if i >= width {
+			return
+		}
+
+		table.WriteAt(x+i, y, dtb.NewCell(r, style))
+		i++
+	}
+}
@@ -0,0 +1,59 @@
+package chart
+
+import (
+	"testing"
+
+	"github.com/PlayerR9/display/internal/drawtest"
+	dlo "github.com/PlayerR9/display/layout"
+)
+
+func TestSparkline(t *testing.T) {
+	s := NewSparkline(8)
+
+	for i := range 10 {
+		s.Push(float64(i))
+	}
+
+	// Only the last 8 values are kept and rescaled to their range.
+	drawtest.CheckLines(t, drawtest.Draw(t, s, 10, 1).GetLines(), []string{"▁▂▃▄▅▆▇█  "})
+}
+
+func TestBarChart(t *testing.T) {
+	bc := NewBarChart(dlo.Horizontal)
+	bc.SetBars(
+		Bar{Label: "a", Value: 10},
+		Bar{Label: "bb", Value: 5},
+	)
+
+	drawtest.CheckLines(t, drawtest.Draw(t, bc, 10, 2).GetLines(), []string{
+		"a  ████ 10",
+		"bb ██    5",
+	})
+
+	bc = NewBarChart(dlo.Vertical)
+	bc.SetBarWidth(1)
+	bc.SetWindow(2)
+	bc.Push(Bar{Label: "x", Value: 1}, Bar{Label: "y", Value: 4}, Bar{Label: "z", Value: 2})
+
+	drawtest.CheckLines(t, drawtest.Draw(t, bc, 3, 3).GetLines(), []string{
+		"█  ",
+		"█ █",
+		"y z",
+	})
+}
+
+func TestLineChart(t *testing.T) {
+	lc := NewLineChart(3)
+	up := lc.AddSeries("up", "")
+	lc.AddSeries("flat", "")
+
+	lc.Push(up, 0, 5, 10)
+	lc.SetValues(1, []float64{10, 10})
+
+	drawtest.CheckLines(t, drawtest.Draw(t, lc, 6, 4).GetLines(), []string{
+		"10┤ ⡩⠋",
+		" 0┤⡰⠁ ",
+		"  └───",
+		"■ up  ",
+	})
+}
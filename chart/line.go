@@ -0,0 +1,347 @@
+package chart
+
+import (
+	"math"
+	"sync"
+
+	"github.com/PlayerR9/display/canvas"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+)
+
+// series is a series of a line chart.
+type series struct {
+	// name is the name of the series, shown in the legend.
+	name string
+
+	// w holds the values.
+	w window
+
+	// role is the role of the style of the series.
+	role theme.Role
+}
+
+// LineChart is a chart of one or more series of values drawn as lines with braille
+// dots, with a y axis labelled with the range of the values and a legend of the
+// series. It is a table.Displayer.
+//
+// Each series holds the newest values of a stream, up to the size of the chart;
+// the newest value is drawn at the right edge and the range follows the values
+// unless fixed.
+type LineChart struct {
+	// series are the series.
+	series []*series
+
+	// size is the number of values of each series shown across the chart.
+	size int
+
+	// lo and hi are the fixed range of the values. If lo >= hi, the range follows
+	// the values.
+	lo, hi float64
+
+	// width and height are the size of the chart. Non-positive to fill the table.
+	width, height int
+
+	// legend is true if the legend is drawn.
+	legend bool
+
+	// mu is the mutex of the chart.
+	mu sync.Mutex
+}
+
+// NewLineChart creates a new line chart without series.
+//
+// Parameters:
+//   - size: The number of values of each series shown across the chart. Values
+//     less than 2 mean 2.
+//
+// Returns:
+//   - *LineChart: The new chart. Never returns nil.
+func NewLineChart(size int) *LineChart {
+	return &LineChart{
+		size:   max(size, 2),
+		legend: true,
+	}
+}
+
+// AddSeries adds a series to the chart.
+//
+// Parameters:
+//   - name: The name of the series, shown in the legend.
+//   - role: The role of the style of the series. Empty for the "chart.<n>" role
+//     of its position.
+//
+// Returns:
+//   - int: The index of the series. -1 with a nil receiver.
+func (lc *LineChart) AddSeries(name string, role theme.Role) int {
+	if lc == nil {
+		return -1
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if role == "" {
+		role = series_role(len(lc.series))
+	}
+
+	lc.series = append(lc.series, &series{
+		name: name,
+		w: window{
+			size: lc.size,
+		},
+		role: role,
+	})
+
+	return len(lc.series) - 1
+}
+
+// Push adds values to a series, dropping the oldest ones beyond the size of the
+// chart. Does nothing with a nil receiver or an unknown series.
+//
+// Parameters:
+//   - idx: The index of the series.
+//   - values: The values. NaN values break the line.
+func (lc *LineChart) Push(idx int, values ...float64) {
+	if lc == nil {
+		return
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if idx >= 0 && idx < len(lc.series) {
+		lc.series[idx].w.push(values...)
+	}
+}
+
+// SetValues replaces the values of a series. Does nothing with a nil receiver or
+// an unknown series.
+//
+// Parameters:
+//   - idx: The index of the series.
+//   - values: The values.
+func (lc *LineChart) SetValues(idx int, values []float64) {
+	if lc == nil {
+		return
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	if idx >= 0 && idx < len(lc.series) {
+		lc.series[idx].w.set(values)
+	}
+}
+
+// SetWindow changes the number of values of each series shown across the chart.
+// Does nothing with a nil receiver.
+//
+// Parameters:
+//   - size: The number of values. Values less than 2 mean 2.
+func (lc *LineChart) SetWindow(size int) {
+	if lc == nil {
+		return
+	}
+
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	lc.size = max(size, 2)
+
+	for _, s := range lc.series {
+		s.w.resize(lc.size)
+	}
+}
+
+// SetRange fixes the range of the values. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - lo: The value drawn at the bottom.
+//   - hi: The value drawn at the top. If not greater than lo, the range follows
+//     the values.
+func (lc *LineChart) SetRange(lo, hi float64) {
+	if lc == nil {
+		return
+	}
+
+	lc.mu.Lock()
+	lc.lo, lc.hi = lo, hi
+	lc.mu.Unlock()
+}
+
+// SetSize changes the size of the chart. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - width: The width. Non-positive to fill the table from the x coordinate.
+//   - height: The height. Non-positive to fill the table from the y coordinate.
+func (lc *LineChart) SetSize(width, height int) {
+	if lc == nil {
+		return
+	}
+
+	lc.mu.Lock()
+	lc.width, lc.height = width, height
+	lc.mu.Unlock()
+}
+
+// ShowLegend shows or hides the legend. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - show: True to show the legend.
+func (lc *LineChart) ShowLegend(show bool) {
+	if lc == nil {
+		return
+	}
+
+	lc.mu.Lock()
+	lc.legend = show
+	lc.mu.Unlock()
+}
+
+// value_range is a helper method that returns the range of the values.
+//
+// Returns:
+//   - float64: The value drawn at the bottom.
+//   - float64: The value drawn at the top.
+//
+// Assertions:
+//   - lc.mu is locked.
+func (lc *LineChart) value_range() (float64, float64) {
+	if lc.lo < lc.hi {
+		return lc.lo, lc.hi
+	}
+
+	lo, hi := math.Inf(1), math.Inf(-1)
+
+	for _, s := range lc.series {
+		if l, h, ok := bounds(s.w.values); ok {
+			lo, hi = min(lo, l), max(hi, h)
+		}
+	}
+
+	if lo > hi {
+		return 0, 1
+	}
+
+	return lo, hi
+}
+
+// Draw implements the table.Displayer interface. The chart is drawn from the given
+// coordinates, which are left at its last row.
+func (lc *LineChart) Draw(table *dtb.Table, x, y *int) error {
+	lc.mu.Lock()
+	defer lc.mu.Unlock()
+
+	width, height := lc.width, lc.height
+
+	if width <= 0 {
+		width = table.Width() - *x
+	}
+
+	if height <= 0 {
+		height = table.Height() - *y
+	}
+
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	left, top := *x, *y
+	*y += height - 1
+
+	muted := theme.RoleMuted.Style()
+	border := theme.RoleBorder.Style()
+
+	if lc.legend && len(lc.series) > 0 && height > 2 {
+		col := left
+
+		for _, s := range lc.series {
+			table.WriteAt(col, top+height-1, dtb.NewCell('■', s.role.Style()))
+			write(table, col+2, top+height-1, s.name, width-(col+2-left), muted)
+
+			col += len([]rune(s.name)) + 4
+		}
+
+		height--
+	}
+
+	lo, hi := lc.value_range()
+
+	labels := [...]string{format_value(hi), format_value((lo + hi) / 2), format_value(lo)}
+
+	label_width := 0
+
+	for _, label := range labels {
+		label_width = max(label_width, len(label))
+	}
+
+	// The plot area is right of the labels and the axis, and above the x axis.
+	plot_x := left + label_width + 1
+	plot_w := width - label_width - 1
+	plot_h := height - 1
+
+	if plot_w <= 0 || plot_h <= 0 {
+		return nil
+	}
+
+	for row := range plot_h {
+		table.WriteAt(plot_x-1, top+row, dtb.NewCell('│', border))
+	}
+
+	table.WriteAt(plot_x-1, top+plot_h, dtb.NewCell('└', border))
+
+	for col := range plot_w {
+		table.WriteAt(plot_x+col, top+plot_h, dtb.NewCell('─', border))
+	}
+
+	label_rows := [...]int{0, (plot_h - 1) / 2, plot_h - 1}
+
+	for i, label := range labels {
+		if i == 1 && (label_rows[1] == 0 || label_rows[1] == plot_h-1) {
+			continue
+		}
+
+		row := top + label_rows[i]
+
+		write(table, plot_x-1-len(label), row, label, len(label), muted)
+		table.WriteAt(plot_x-1, row, dtb.NewCell('┤', border))
+	}
+
+	cv := canvas.New(plot_w, plot_h, canvas.Braille)
+
+	px_max := 2*plot_w - 1
+	py_max := 4*plot_h - 1
+
+	for _, s := range lc.series {
+		fg, _, _ := s.role.Style().Decompose()
+		cv.SetColor(fg)
+
+		values := s.w.values
+		offset := lc.size - len(values)
+
+		prev_x, prev_y, has_prev := 0, 0, false
+
+		for i, v := range values {
+			if math.IsNaN(v) {
+				has_prev = false
+				continue
+			}
+
+			px := (offset + i) * px_max / (lc.size - 1)
+			py := py_max - int(math.Round(scale(v, lo, hi)*float64(py_max)))
+
+			if has_prev {
+				cv.Line(prev_x, prev_y, px, py)
+			} else {
+				cv.Set(px, py)
+			}
+
+			prev_x, prev_y, has_prev = px, py, true
+		}
+	}
+
+	cx, cy := plot_x, top
+
+	return cv.Draw(table, &cx, &cy)
+}
@@ -0,0 +1,163 @@
+package chart
+
+import (
+	"math"
+	"sync"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+)
+
+// levels are the characters of the eight heights of a sparkline cell.
+var levels = [...]rune{'▁', '▂', '▃', '▄', '▅', '▆', '▇', '█'}
+
+// Sparkline is a one-row chart of the newest values of a stream. It is a
+// table.Displayer.
+type Sparkline struct {
+	// w holds the values.
+	w window
+
+	// lo and hi are the fixed range of the values. If lo >= hi, the range follows
+	// the shown values.
+	lo, hi float64
+
+	// width is the width of the sparkline. Non-positive to fill the table.
+	width int
+
+	// role is the role of the style of the sparkline.
+	role theme.Role
+
+	// mu is the mutex of the sparkline.
+	mu sync.Mutex
+}
+
+// NewSparkline creates a new, empty sparkline.
+//
+// Parameters:
+//   - size: The number of values kept. Non-positive for no limit.
+//
+// Returns:
+//   - *Sparkline: The new sparkline. Never returns nil.
+func NewSparkline(size int) *Sparkline {
+	return &Sparkline{
+		w: window{
+			size: size,
+		},
+		role: theme.RoleChart.Child("0"),
+	}
+}
+
+// Push adds values to the sparkline, dropping the oldest ones beyond its size.
+// Does nothing with a nil receiver.
+//
+// Parameters:
+//   - values: The values. NaN values are drawn as gaps.
+func (s *Sparkline) Push(values ...float64) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.w.push(values...)
+	s.mu.Unlock()
+}
+
+// SetValues replaces the values of the sparkline. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - values: The values.
+func (s *Sparkline) SetValues(values []float64) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.w.set(values)
+	s.mu.Unlock()
+}
+
+// SetRange fixes the range of the values. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - lo: The value drawn at the bottom.
+//   - hi: The value drawn at the top. If not greater than lo, the range follows
+//     the shown values.
+func (s *Sparkline) SetRange(lo, hi float64) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.lo, s.hi = lo, hi
+	s.mu.Unlock()
+}
+
+// SetWidth changes the width of the sparkline. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - width: The width. Non-positive to fill the table from the x coordinate.
+func (s *Sparkline) SetWidth(width int) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.width = width
+	s.mu.Unlock()
+}
+
+// SetRole changes the role of the style of the sparkline. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - role: The role.
+func (s *Sparkline) SetRole(role theme.Role) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.role = role
+	s.mu.Unlock()
+}
+
+// Draw implements the table.Displayer interface. The newest values that fit are
+// drawn from the given coordinates, which are left after the last one.
+func (s *Sparkline) Draw(table *dtb.Table, x, y *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	width := s.width
+	if width <= 0 {
+		width = table.Width() - *x
+	}
+
+	if width <= 0 {
+		return nil
+	}
+
+	values := s.w.tail(width)
+
+	lo, hi := s.lo, s.hi
+	if lo >= hi {
+		lo, hi, _ = bounds(values)
+	}
+
+	style := s.role.Style()
+
+	for i, v := range values {
+		r := ' '
+
+		if !math.IsNaN(v) {
+			level := int(math.Round(scale(v, lo, hi) * float64(len(levels)-1)))
+			r = levels[level]
+		}
+
+		table.WriteAt(*x+i, *y, dtb.NewCell(r, style))
+	}
+
+	*x += len(values)
+
+	return nil
+}
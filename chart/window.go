@@ -0,0 +1,102 @@
+package chart
+
+import (
+	"math"
+	"slices"
+)
+
+// window is a fixed-size window over a stream of values: once full, each new
+// value drops the oldest one.
+type window struct {
+	// values are the values, from the oldest to the newest.
+	values []float64
+
+	// size is the maximum number of values. Non-positive for no limit.
+	size int
+}
+
+// push adds values to the window and drops the oldest ones beyond its size.
+//
+// Parameters:
+//   - values: The values to add.
+func (w *window) push(values ...float64) {
+	w.values = append(w.values, values...)
+
+	if w.size > 0 && len(w.values) > w.size {
+		w.values = slices.Delete(w.values, 0, len(w.values)-w.size)
+	}
+}
+
+// set replaces the values of the window, keeping the newest ones.
+//
+// Parameters:
+//   - values: The values.
+func (w *window) set(values []float64) {
+	w.values = w.values[:0]
+	w.push(values...)
+}
+
+// resize changes the size of the window, dropping the oldest values beyond it.
+//
+// Parameters:
+//   - size: The size. Non-positive for no limit.
+func (w *window) resize(size int) {
+	w.size = size
+	w.push()
+}
+
+// tail returns the newest values.
+//
+// Parameters:
+//   - n: The maximum number of values.
+//
+// Returns:
+//   - []float64: The values. Not a copy.
+func (w *window) tail(n int) []float64 {
+	if len(w.values) <= n {
+		return w.values
+	}
+
+	return w.values[len(w.values)-n:]
+}
+
+// bounds is a helper function that returns the smallest and largest finite values.
+//
+// Parameters:
+//   - values: The values.
+//
+// Returns:
+//   - float64: The smallest value.
+//   - float64: The largest value.
+//   - bool: False if there are no finite values.
+func bounds(values []float64) (float64, float64, bool) {
+	lo, hi := math.Inf(1), math.Inf(-1)
+
+	for _, v := range values {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			continue
+		}
+
+		lo = min(lo, v)
+		hi = max(hi, v)
+	}
+
+	return lo, hi, lo <= hi
+}
+
+// scale is a helper function that maps a value of a range to [0, 1].
+//
+// Parameters:
+//   - v: The value.
+//   - lo: The start of the range.
+//   - hi: The end of the range.
+//
+// Returns:
+//   - float64: The mapped value, clamped to [0, 1]. 0.5 if the range is empty.
+func scale(v, lo, hi float64) float64 {
+	if hi <= lo {
+		return 0.5
+	}
+
+	return max(min((v-lo)/(hi-lo), 1), 0)
+}
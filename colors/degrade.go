@@ -0,0 +1,154 @@
+package colors
+
+import (
+	"sync"
+
+	"github.com/gdamore/tcell"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// cache_key is the key of the cache of the nearest colours.
+type cache_key struct {
+	// hex is the 24-bit value of the colour.
+	hex int32
+
+	// depth is the depth the colour is mapped to.
+	depth Depth
+}
+
+var (
+	// cache caches the nearest colour of each (colour, depth) pair.
+	cache sync.Map
+
+	// palette holds the colours of the 256 xterm colours.
+	palette = sync.OnceValue(func() []colorful.Color {
+		colors := make([]colorful.Color, 256)
+
+		for i := range colors {
+			colors[i] = to_colorful(tcell.Color(i).Hex())
+		}
+
+		return colors
+	})
+)
+
+// to_colorful converts a 24-bit value to a colorful.Color.
+//
+// Parameters:
+//   - hex: The 24-bit value.
+//
+// Returns:
+//   - colorful.Color: The colour.
+func to_colorful(hex int32) colorful.Color {
+	return colorful.Color{
+		R: float64((hex>>16)&0xff) / 255,
+		G: float64((hex>>8)&0xff) / 255,
+		B: float64(hex&0xff) / 255,
+	}
+}
+
+// Nearest returns the colour of the palette of the given depth that is perceptually
+// the closest to the given colour, according to the CIEDE2000 distance. Results are
+// cached, so mapping a whole screen is cheap after the first frame.
+//
+// For Depth256, only the 6x6x6 cube and the grey ramp (16-255) are considered, since
+// terminals commonly redefine the first 16 colours.
+//
+// Parameters:
+//   - c: The colour to map.
+//   - depth: The depth to map the colour to.
+//
+// Returns:
+//   - tcell.Color: The nearest colour. c itself if it already fits the depth, and
+//     tcell.ColorDefault for DepthMono.
+func Nearest(c tcell.Color, depth Depth) tcell.Color {
+	if c == tcell.ColorDefault || depth == DepthTrueColor {
+		return c
+	} else if depth == DepthMono {
+		return tcell.ColorDefault
+	}
+
+	size := depth.palette_size()
+
+	if c >= 0 && c&tcell.ColorIsRGB == 0 && int(c) < size {
+		return c
+	}
+
+	hex := c.Hex()
+	if hex < 0 {
+		return c
+	}
+
+	key := cache_key{hex: hex, depth: depth}
+
+	if v, ok := cache.Load(key); ok {
+		return v.(tcell.Color)
+	}
+
+	start := 0
+	if depth == Depth256 {
+		start = 16
+	}
+
+	target := to_colorful(hex)
+	colors := palette()
+
+	best := start
+	best_dist := target.DistanceCIEDE2000(colors[start])
+
+	for i := start + 1; i < size; i++ {
+		dist := target.DistanceCIEDE2000(colors[i])
+		if dist < best_dist {
+			best = i
+			best_dist = dist
+		}
+	}
+
+	cache.Store(key, tcell.Color(best))
+
+	return tcell.Color(best)
+}
+
+// Degrade maps the colours of the style down to the given depth. Attributes are
+// kept as they are.
+//
+// With DepthMono, colours are dropped. A style whose background is lighter than its
+// foreground (such as a selection) is reversed so it stays distinguishable.
+//
+// Parameters:
+//   - style: The style to degrade.
+//   - depth: The depth of the terminal.
+//
+// Returns:
+//   - tcell.Style: The degraded style.
+func Degrade(style tcell.Style, depth Depth) tcell.Style {
+	if depth == DepthTrueColor {
+		return style
+	}
+
+	fg, bg, attrs := style.Decompose()
+
+	if depth != DepthMono {
+		return style.Foreground(Nearest(fg, depth)).Background(Nearest(bg, depth))
+	}
+
+	mono := style.Foreground(tcell.ColorDefault).Background(tcell.ColorDefault)
+
+	if bg == tcell.ColorDefault || bg.Hex() < 0 {
+		return mono
+	}
+
+	if fg == tcell.ColorDefault || fg.Hex() < 0 {
+		// Assume the usual light text on a dark terminal.
+		fg = tcell.ColorSilver
+	}
+
+	_, _, fg_l := to_colorful(fg.Hex()).Hcl()
+	_, _, bg_l := to_colorful(bg.Hex()).Hcl()
+
+	if bg_l > fg_l {
+		mono = mono.Reverse(attrs&tcell.AttrReverse == 0)
+	}
+
+	return mono
+}
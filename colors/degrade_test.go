@@ -0,0 +1,74 @@
+package colors
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+// TestNearest tests the mapping of truecolor values to indexed palettes.
+func TestNearest(t *testing.T) {
+	tests := []struct {
+		name  string
+		color tcell.Color
+		depth Depth
+		want  tcell.Color
+	}{
+		{"pure red to 16", tcell.NewRGBColor(255, 0, 0), Depth16, tcell.ColorRed},
+		{"dark blue to 16", tcell.NewRGBColor(0, 0, 120), Depth16, tcell.ColorNavy},
+		{"near white to 8", tcell.NewRGBColor(250, 250, 250), Depth8, tcell.ColorSilver},
+		{"indexed kept", tcell.ColorTeal, Depth16, tcell.ColorTeal},
+		{"cube colour to 256", tcell.NewRGBColor(0x5f, 0x87, 0xaf), Depth256, tcell.Color67},
+		{"default kept", tcell.ColorDefault, Depth16, tcell.ColorDefault},
+		{"mono drops", tcell.ColorRed, DepthMono, tcell.ColorDefault},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Nearest(tt.color, tt.depth)
+			if got != tt.want {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
+
+// TestDegradeMono tests that light backgrounds are reversed in monochrome.
+func TestDegradeMono(t *testing.T) {
+	selection := tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorWhite).Bold(true)
+
+	got := Degrade(selection, DepthMono)
+	if want := tcell.StyleDefault.Bold(true).Reverse(true); got != want {
+		t.Errorf("want %v, got %v", want, got)
+	}
+
+	text := tcell.StyleDefault.Foreground(tcell.ColorYellow)
+
+	got = Degrade(text, DepthMono)
+	if got != tcell.StyleDefault {
+		t.Errorf("want %v, got %v", tcell.StyleDefault, got)
+	}
+}
+
+// TestDetect tests that the environment overrides the screen.
+func TestDetect(t *testing.T) {
+	t.Setenv("NO_COLOR", "1")
+
+	if got := Detect(nil); got != DepthMono {
+		t.Errorf("want %v, got %v", DepthMono, got)
+	}
+
+	t.Setenv("NO_COLOR", "")
+	t.Setenv("TERM", "xterm-256color")
+	t.Setenv("COLORTERM", "truecolor")
+
+	if got := Detect(nil); got != DepthTrueColor {
+		t.Errorf("want %v, got %v", DepthTrueColor, got)
+	}
+
+	t.Setenv("COLORTERM", "")
+
+	if got := Detect(nil); got != Depth256 {
+		t.Errorf("want %v, got %v", Depth256, got)
+	}
+}
@@ -0,0 +1,121 @@
+package colors
+
+import (
+	"os"
+	"strings"
+
+	"github.com/gdamore/tcell"
+)
+
+// Depth is the number of colours a terminal can display.
+type Depth int
+
+const (
+	// DepthMono is the depth of terminals without colours, or of terminals whose
+	// user asked for no colours through the NO_COLOR environment variable.
+	DepthMono Depth = iota
+
+	// Depth8 is the depth of terminals with the 8 basic ANSI colours.
+	Depth8
+
+	// Depth16 is the depth of terminals with the 16 ANSI colours.
+	Depth16
+
+	// Depth256 is the depth of terminals with the 256 xterm colours.
+	Depth256
+
+	// DepthTrueColor is the depth of terminals with 24-bit colours.
+	DepthTrueColor
+)
+
+// String implements the fmt.Stringer interface.
+func (d Depth) String() string {
+	return [...]string{
+		"mono",
+		"8",
+		"16",
+		"256",
+		"truecolor",
+	}[d]
+}
+
+// palette_size returns the number of indexed colours of the depth.
+//
+// Returns:
+//   - int: The number of indexed colours. 0 for DepthMono and DepthTrueColor.
+func (d Depth) palette_size() int {
+	switch d {
+	case Depth8:
+		return 8
+	case Depth16:
+		return 16
+	case Depth256:
+		return 256
+	default:
+		return 0
+	}
+}
+
+// FromColors returns the depth of a terminal that can display the given number of
+// colours, as reported by tcell.Screen.Colors.
+//
+// Parameters:
+//   - n: The number of colours.
+//
+// Returns:
+//   - Depth: The depth.
+func FromColors(n int) Depth {
+	switch {
+	case n >= 1<<24:
+		return DepthTrueColor
+	case n >= 256:
+		return Depth256
+	case n >= 16:
+		return Depth16
+	case n >= 8:
+		return Depth8
+	default:
+		return DepthMono
+	}
+}
+
+// Detect detects the colour depth of the terminal from the screen and the
+// environment. In order:
+//   - NO_COLOR (with any non-empty value) or TERM=dumb gives DepthMono.
+//   - COLORTERM=truecolor or COLORTERM=24bit gives DepthTrueColor.
+//   - Otherwise, the number of colours reported by the screen is used. If the screen
+//     is nil, TERM is used instead.
+//
+// Parameters:
+//   - screen: The initialised screen. Can be nil.
+//
+// Returns:
+//   - Depth: The detected depth.
+func Detect(screen tcell.Screen) Depth {
+	if os.Getenv("NO_COLOR") != "" {
+		return DepthMono
+	}
+
+	term := os.Getenv("TERM")
+	if term == "dumb" {
+		return DepthMono
+	}
+
+	switch strings.ToLower(os.Getenv("COLORTERM")) {
+	case "truecolor", "24bit":
+		return DepthTrueColor
+	}
+
+	if screen != nil {
+		return FromColors(screen.Colors())
+	}
+
+	switch {
+	case strings.Contains(term, "256color"):
+		return Depth256
+	case term == "":
+		return DepthMono
+	default:
+		return Depth16
+	}
+}
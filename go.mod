@@ -3,16 +3,17 @@ module github.com/PlayerR9/display
 go 1.23.1
 
 require (
+	github.com/BurntSushi/toml v1.4.0
 	github.com/PlayerR9/go-commons v0.1.16
 	github.com/PlayerR9/go-debug v0.1.6
 	github.com/PlayerR9/safe v0.1.10
 	github.com/PlayerR9/table v0.1.13
 	github.com/gdamore/tcell v1.4.0
+	github.com/lucasb-eyer/go-colorful v1.2.0
 )
 
 require (
 	github.com/gdamore/encoding v1.0.1 // indirect
-	github.com/lucasb-eyer/go-colorful v1.2.0 // indirect
 	github.com/mattn/go-runewidth v0.0.16 // indirect
 	github.com/rivo/uniseg v0.4.7 // indirect
 	golang.org/x/exp v0.0.0-20240909161429-701f63a606c0 // indirect
@@ -1,7 +1,10 @@
 package highlight
 
 import (
+	"unicode/utf8"
+
 	ds "github.com/PlayerR9/display/screen"
+	"github.com/PlayerR9/display/theme"
 	gcers "github.com/PlayerR9/go-commons/errors"
 	gda "github.com/PlayerR9/go-debug/assert"
 	"github.com/gdamore/tcell"
@@ -94,7 +97,7 @@ func (h Highlight[E, T]) Draw(screen ds.Drawable, x_coord, y_coord *int) error {
 				return []rune(data)
 			}
 		} else {
-			style = rule.style
+			style = rule.Style()
 			fn = rule.fn
 		}
 
@@ -162,6 +165,21 @@ func (h *Highlight[E, T]) Register(type_ E, style tcell.Style, fn WriteDataFn) {
 	h.table[type_] = NewTokenRule(style, fn)
 }
 
+// RegisterRole registers a new token rule whose style follows the given role of the
+// current theme.
+//
+// Parameters:
+//   - type_: The type of the token.
+//   - role: The role of the style of the token.
+//   - fn: The function that is applied to the token data.
+func (h *Highlight[E, T]) RegisterRole(type_ E, role theme.Role, fn WriteDataFn) {
+	if h == nil {
+		return
+	}
+
+	h.table[type_] = NewRoleTokenRule(role, fn)
+}
+
 // SetTokens sets the tokens to highlight.
 //
 // Parameters:
@@ -187,3 +205,48 @@ func (h *Highlight[E, T]) SetTokens(data []byte, tokens []T) *Highlight[E, T] {
 		data:   data,
 	}
 }
+
+// Styles returns the style of each character of the data, according to the tokens
+// that cover it; for instance, to re-colour text that is drawn by something else.
+// Token rules only give the style: their function is not applied.
+//
+// Parameters:
+//   - bg_style: The style of the characters that are not covered by a registered
+//     token.
+//
+// Returns:
+//   - []tcell.Style: One style per character (not byte) of the data.
+func (h Highlight[E, T]) Styles(bg_style tcell.Style) []tcell.Style {
+	by_byte := make([]tcell.Style, len(h.data))
+
+	for i := range by_byte {
+		by_byte[i] = bg_style
+	}
+
+	for _, tk := range h.tokens {
+		rule, ok := h.table[tk.GetType()]
+		if !ok {
+			continue
+		}
+
+		style := rule.Style()
+
+		pos := max(tk.GetPos(), 0)
+		end := min(pos+len(tk.GetData()), len(h.data))
+
+		for i := pos; i < end; i++ {
+			by_byte[i] = style
+		}
+	}
+
+	styles := make([]tcell.Style, 0, utf8.RuneCount(h.data))
+
+	for i := 0; i < len(h.data); {
+		_, size := utf8.DecodeRune(h.data[i:])
+
+		styles = append(styles, by_byte[i])
+		i += size
+	}
+
+	return styles
+}
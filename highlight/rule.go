@@ -1,6 +1,9 @@
 package highlight
 
-import "github.com/gdamore/tcell"
+import (
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
 
 // WriteDataFn is a function that writes data.
 //
@@ -16,6 +19,10 @@ type TokenRule struct {
 	// style is the style of the token.
 	style tcell.Style
 
+	// role is the role of the style of the token in the current theme. If empty,
+	// style is used instead.
+	role theme.Role
+
 	// fn is the function that is applied to the token data.
 	fn WriteDataFn
 }
@@ -40,3 +47,31 @@ func NewTokenRule(style tcell.Style, fn WriteDataFn) *TokenRule {
 		fn:    fn,
 	}
 }
+
+// NewRoleTokenRule creates a new token rule whose style follows the given role of the
+// current theme.
+//
+// Parameters:
+//   - role: The role of the style of the token (e.g., theme.RoleToken.Child("keyword")).
+//   - fn: The function that is applied to the token data.
+//
+// Returns:
+//   - *TokenRule: The new token rule. Never returns nil.
+func NewRoleTokenRule(role theme.Role, fn WriteDataFn) *TokenRule {
+	rule := NewTokenRule(tcell.StyleDefault, fn)
+	rule.role = role
+
+	return rule
+}
+
+// Style returns the style of the token.
+//
+// Returns:
+//   - tcell.Style: The style of the token.
+func (tr TokenRule) Style() tcell.Style {
+	if tr.role != "" {
+		return tr.role.Style()
+	}
+
+	return tr.style
+}
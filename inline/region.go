@@ -0,0 +1,418 @@
+package inline
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/PlayerR9/display/ansi"
+	"github.com/PlayerR9/display/colors"
+	dtb "github.com/PlayerR9/display/table"
+	gcers "github.com/PlayerR9/go-commons/errors"
+)
+
+const (
+	// hide_cursor hides the terminal cursor.
+	hide_cursor string = "\x1b[?25l"
+
+	// show_cursor shows the terminal cursor.
+	show_cursor string = "\x1b[?25h"
+
+	// clear_line clears from the cursor to the end of the line.
+	clear_line string = "\x1b[K"
+
+	// clear_below clears from the cursor to the end of the screen.
+	clear_below string = "\x1b[J"
+)
+
+// Region is a block of lines, below the current cursor position, that is redrawn
+// in place without taking over the whole terminal (e.g., progress bars or a task
+// list under the shell prompt). Ordinary lines written with Println or Write are
+// printed above the block, which is then redrawn below them. On Close, the last
+// frame is left in the scrollback.
+//
+// If the output is not a terminal, frames are not drawn in place: lines written
+// above the block are passed through and only the final frame is written on Close.
+type Region struct {
+	// out is the output.
+	out io.Writer
+
+	// is_tty is true if the output is a terminal.
+	is_tty bool
+
+	// tty is the output, if it is a terminal. Nil otherwise.
+	tty *os.File
+
+	// depth is the colour depth of the output.
+	depth colors.Depth
+
+	// width is the width of the block.
+	width int
+
+	// width_forced is true if the width was set with SetWidth and must not follow
+	// the size of the terminal.
+	width_forced bool
+
+	// height is the number of lines of the block.
+	height int
+
+	// elem is the displayer drawn in the block.
+	elem dtb.Displayer
+
+	// frame is the last rendered frame.
+	frame []string
+
+	// drawn is the number of lines of the block currently on the terminal.
+	drawn int
+
+	// hidden is true if the cursor was hidden.
+	hidden bool
+
+	// partial holds the text written with Write that does not end with a newline
+	// yet.
+	partial []byte
+
+	// closed is true if the region is closed.
+	closed bool
+
+	// mu is the mutex of the region.
+	mu sync.Mutex
+}
+
+// NewRegion creates a new region of the given number of lines. If the output is a
+// terminal, the region is as wide as the terminal, whose size is queried on every
+// render; otherwise, its width is given by the COLUMNS environment variable, if
+// set, or ansi.DefaultWidth.
+//
+// Parameters:
+//   - out: The output. If nil, os.Stdout is used.
+//   - height: The number of lines of the region.
+//
+// Returns:
+//   - *Region: The new region.
+//   - error: An error if height is not positive.
+func NewRegion(out io.Writer, height int) (*Region, error) {
+	if height <= 0 {
+		return nil, gcers.NewErrInvalidParameter("height", gcers.NewErrGT(0))
+	}
+
+	if out == nil {
+		out = os.Stdout
+	}
+
+	width := ansi.DefaultWidth
+
+	n, err := strconv.Atoi(os.Getenv("COLUMNS"))
+	if err == nil && n > 0 {
+		width = n
+	}
+
+	r := &Region{
+		out:    out,
+		width:  width,
+		height: height,
+		depth:  ansi.OutputDepth(),
+	}
+
+	if f, ok := out.(*os.File); ok && ansi.IsTerminal(f) {
+		r.is_tty = true
+		r.tty = f
+		r.depth = colors.Detect(nil)
+	}
+
+	return r, nil
+}
+
+// SetWidth changes the width of the region, which then no longer follows the size
+// of the terminal. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - width: The new width. Non-positive values are ignored.
+func (r *Region) SetWidth(width int) {
+	if r == nil || width <= 0 {
+		return
+	}
+
+	r.mu.Lock()
+	r.width = width
+	r.width_forced = true
+	r.mu.Unlock()
+}
+
+// SetColorDepth forces the colour depth of the output. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - depth: The colour depth.
+func (r *Region) SetColorDepth(depth colors.Depth) {
+	if r == nil {
+		return
+	}
+
+	r.mu.Lock()
+	r.depth = depth
+	r.mu.Unlock()
+}
+
+// Render draws the displayer in the region, replacing the previous frame.
+//
+// Parameters:
+//   - elem: The displayer to draw. If nil, the region is left blank.
+//
+// Returns:
+//   - error: An error if the displayer could not be drawn or the frame could not be
+//     written.
+//
+// Errors:
+//   - gcers.NilReceiver: If the receiver is nil.
+//   - io.ErrClosedPipe: If the region is closed.
+func (r *Region) Render(elem dtb.Displayer) error {
+	if r == nil {
+		return gcers.NilReceiver
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return io.ErrClosedPipe
+	}
+
+	r.elem = elem
+
+	err := r.render()
+	if err != nil {
+		return err
+	}
+
+	return r.redraw(nil)
+}
+
+// Refresh draws the last displayer again; for instance, after its state changed.
+//
+// Returns:
+//   - error: An error if the displayer could not be drawn.
+func (r *Region) Refresh() error {
+	if r == nil {
+		return gcers.NilReceiver
+	}
+
+	r.mu.Lock()
+	elem := r.elem
+	r.mu.Unlock()
+
+	return r.Render(elem)
+}
+
+// render is a helper method that draws the displayer into a new frame.
+//
+// Returns:
+//   - error: An error if the displayer could not be drawn.
+//
+// Assertions:
+//   - r.mu is locked.
+func (r *Region) render() error {
+	if r.tty != nil && !r.width_forced {
+		// Lines wider than the terminal would wrap and break the cursor movements of
+		// redraw.
+		if width, _, ok := ansi.TerminalSize(r.tty); ok {
+			r.width = width
+		}
+	}
+
+	table, err := dtb.NewTable(r.width, r.height)
+	if err != nil {
+		return err
+	}
+
+	if r.elem != nil {
+		err = dtb.DrawClipped(table, r.elem, 0, 0, r.width, r.height)
+		if err != nil {
+			return err
+		}
+	}
+
+	frame := make([]string, 0, r.height)
+
+	for row := range table.Row() {
+		frame = append(frame, ansi.FormatRow(row, r.depth))
+	}
+
+	r.frame = frame
+
+	return nil
+}
+
+// redraw is a helper method that erases the block, writes the given text above it
+// and draws the current frame below.
+//
+// Parameters:
+//   - above: The text to write above the block. Must be empty or end with a
+//     newline.
+//
+// Returns:
+//   - error: An error if the output could not be written.
+//
+// Assertions:
+//   - r.mu is locked.
+func (r *Region) redraw(above []byte) error {
+	if !r.is_tty {
+		if len(above) == 0 {
+			return nil
+		}
+
+		_, err := r.out.Write(above)
+		return err
+	}
+
+	var buf bytes.Buffer
+
+	if !r.hidden {
+		buf.WriteString(hide_cursor)
+		r.hidden = true
+	}
+
+	if r.drawn > 0 {
+		// Move to the first column of the first line of the block.
+		fmt.Fprintf(&buf, "\r\x1b[%dA", r.drawn)
+	}
+
+	if len(above) > 0 {
+		buf.WriteString(clear_below)
+		buf.Write(above)
+	}
+
+	for _, line := range r.frame {
+		buf.WriteString("\r")
+		buf.WriteString(line)
+		buf.WriteString(clear_line)
+		buf.WriteString("\n")
+	}
+
+	r.drawn = len(r.frame)
+
+	_, err := r.out.Write(buf.Bytes())
+	return err
+}
+
+// Write implements the io.Writer interface. The complete lines of p are printed
+// above the region; an incomplete last line is kept until its newline is written.
+// This allows, for instance, log.SetOutput(region).
+func (r *Region) Write(p []byte) (int, error) {
+	if r == nil {
+		return 0, gcers.NilReceiver
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return 0, io.ErrClosedPipe
+	}
+
+	r.partial = append(r.partial, p...)
+
+	idx := bytes.LastIndexByte(r.partial, '\n')
+	if idx == -1 {
+		return len(p), nil
+	}
+
+	above := r.partial[:idx+1]
+	r.partial = append([]byte(nil), r.partial[idx+1:]...)
+
+	err := r.redraw(above)
+	if err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+// Println prints a line above the region.
+//
+// Parameters:
+//   - a: The operands, formatted as with fmt.Sprintln.
+//
+// Returns:
+//   - error: An error if the line could not be printed.
+func (r *Region) Println(a ...any) error {
+	_, err := r.Write([]byte(fmt.Sprintln(a...)))
+	return err
+}
+
+// Printf prints a formatted line above the region. A trailing newline is added if
+// missing.
+//
+// Parameters:
+//   - format: The format, as with fmt.Sprintf.
+//   - a: The operands.
+//
+// Returns:
+//   - error: An error if the line could not be printed.
+func (r *Region) Printf(format string, a ...any) error {
+	text := fmt.Sprintf(format, a...)
+
+	if !strings.HasSuffix(text, "\n") {
+		text += "\n"
+	}
+
+	_, err := r.Write([]byte(text))
+	return err
+}
+
+// Close flushes any pending partial line, leaves the last frame in the scrollback
+// and gives the terminal back. Closing a closed region does nothing.
+//
+// Returns:
+//   - error: An error if the output could not be written.
+func (r *Region) Close() error {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.closed {
+		return nil
+	}
+
+	r.closed = true
+
+	var above []byte
+
+	if len(r.partial) > 0 {
+		above = append(r.partial, '\n')
+		r.partial = nil
+	}
+
+	if !r.is_tty {
+		var buf bytes.Buffer
+
+		buf.Write(above)
+
+		for _, line := range r.frame {
+			buf.WriteString(line)
+			buf.WriteString("\n")
+		}
+
+		_, err := r.out.Write(buf.Bytes())
+		return err
+	}
+
+	err := r.redraw(above)
+	if err != nil {
+		return err
+	}
+
+	if !r.hidden {
+		return nil
+	}
+
+	_, err = io.WriteString(r.out, show_cursor)
+	return err
+}
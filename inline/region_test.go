@@ -0,0 +1,62 @@
+package inline
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/PlayerR9/display/colors"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+// text is a displayer that writes a line of text.
+type text string
+
+// Draw implements the table.Displayer interface.
+func (t text) Draw(table *dtb.Table, x, y *int) error {
+	table.WriteLineAt(x, y, string(t), tcell.StyleDefault, true)
+
+	return nil
+}
+
+// TestRegion tests that lines printed above the region redraw it below them.
+func TestRegion(t *testing.T) {
+	var out bytes.Buffer
+
+	r, err := NewRegion(&out, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	r.is_tty = true
+	r.SetWidth(10)
+	r.SetColorDepth(colors.DepthMono)
+
+	err = r.Render(text("50%"))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	out.Reset()
+
+	err = r.Println("done: a")
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	want := "\r\x1b[2A" + clear_below + "done: a\n" + "\r50%" + clear_line + "\n" + "\r" + clear_line + "\n"
+	if got := out.String(); got != want {
+		t.Errorf("Expected %q, but got %q", want, got)
+	}
+
+	out.Reset()
+
+	err = r.Close()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if got := out.String(); !bytes.HasSuffix([]byte(got), []byte(show_cursor)) {
+		t.Errorf("Expected the cursor to be shown again, but got %q", got)
+	}
+}
@@ -0,0 +1,53 @@
+// Package drawtest provides helpers to test the elements that draw on a table.
+package drawtest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	dtb "github.com/PlayerR9/display/table"
+)
+
+// Draw draws an element on a new table of the given size. The test fails if the
+// table could not be created or the element could not be drawn.
+//
+// Parameters:
+//   - t: The test.
+//   - elem: The element to draw.
+//   - width: The width of the table.
+//   - height: The height of the table.
+//
+// Returns:
+//   - *dtb.Table: The table.
+func Draw(t testing.TB, elem dtb.Displayer, width, height int) *dtb.Table {
+	t.Helper()
+
+	table, err := dtb.NewTable(width, height)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	x, y := 0, 0
+
+	err = elem.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	return table
+}
+
+// CheckLines fails the test if the lines are not the expected ones.
+//
+// Parameters:
+//   - t: The test.
+//   - got: The lines.
+//   - want: The expected lines.
+func CheckLines(t testing.TB, got, want []string) {
+	t.Helper()
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("Expected:\n%s\nbut got:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
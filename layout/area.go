@@ -0,0 +1,206 @@
+package layout
+
+import (
+	"sync"
+
+	dtb "github.com/PlayerR9/display/table"
+)
+
+// Direction is the axis along which a layout splits an area.
+type Direction int
+
+const (
+	// Vertical splits an area from top to bottom.
+	Vertical Direction = iota
+
+	// Horizontal splits an area from left to right.
+	Horizontal
+)
+
+// String implements the errors.Enumer interface.
+func (d Direction) String() string {
+	return [...]string{
+		"vertical",
+		"horizontal",
+	}[d]
+}
+
+// Area is a rectangular area of a table.
+type Area struct {
+	// X is the x coordinate of the top-left corner.
+	X int
+
+	// Y is the y coordinate of the top-left corner.
+	Y int
+
+	// Width is the width of the area.
+	Width int
+
+	// Height is the height of the area.
+	Height int
+}
+
+// IsEmpty checks whether the area has no cells.
+//
+// Returns:
+//   - bool: True if the area is empty, false otherwise.
+func (a Area) IsEmpty() bool {
+	return a.Width <= 0 || a.Height <= 0
+}
+
+// TableArea returns the area of the table that starts at the given coordinates and
+// extends to the bottom-right corner of the table.
+//
+// Parameters:
+//   - table: The table.
+//   - x: The x coordinate of the top-left corner.
+//   - y: The y coordinate of the top-left corner.
+//
+// Returns:
+//   - Area: The area. Empty if the table is nil or the coordinates are out of bounds.
+func TableArea(table *dtb.Table, x, y int) Area {
+	x = max(x, 0)
+	y = max(y, 0)
+
+	return Area{
+		X:      x,
+		Y:      y,
+		Width:  max(table.Width()-x, 0),
+		Height: max(table.Height()-y, 0),
+	}
+}
+
+// Layout splits an area along an axis according to the constraints of each element.
+type Layout struct {
+	// direction is the axis along which the area is split.
+	direction Direction
+
+	// spacing is the space between two elements.
+	spacing int
+
+	// constraints are the constraints of each element.
+	constraints []Constraint
+
+	// mu is the mutex of the layout.
+	mu sync.RWMutex
+}
+
+// NewLayout creates a new layout.
+//
+// Parameters:
+//   - direction: The axis along which the area is split.
+//   - constraints: The constraints of each element.
+//
+// Returns:
+//   - *Layout: The new layout. Never returns nil.
+func NewLayout(direction Direction, constraints ...Constraint) *Layout {
+	return &Layout{
+		direction:   direction,
+		constraints: constraints,
+	}
+}
+
+// SetSpacing sets the space between two elements. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - spacing: The space between two elements. Negative values are treated as 0.
+func (l *Layout) SetSpacing(spacing int) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.spacing = max(spacing, 0)
+}
+
+// SetConstraints replaces the constraints of the layout. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - constraints: The constraints of each element.
+func (l *Layout) SetConstraints(constraints ...Constraint) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.constraints = constraints
+}
+
+// Split splits the given area among the elements. When the area is too small to
+// hold the spacing, the spacing is dropped before any element is shrunk. See Solve
+// for how the space is split.
+//
+// Parameters:
+//   - area: The area to split.
+//
+// Returns:
+//   - []Area: The area of each element, in the same order as the constraints.
+func (l *Layout) Split(area Area) []Area {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.RLock()
+	defer l.mu.RUnlock()
+
+	return SplitArea(area, l.direction, l.spacing, l.constraints)
+}
+
+// SplitArea splits the given area among elements along an axis. When the area is
+// too small to hold the spacing, the spacing is dropped before any element is
+// shrunk. See Solve for how the space is split.
+//
+// Parameters:
+//   - area: The area to split.
+//   - direction: The axis along which the area is split.
+//   - spacing: The space between two elements. Negative values are treated as 0.
+//   - constraints: The constraints of each element.
+//
+// Returns:
+//   - []Area: The area of each element, in the same order as the constraints.
+func SplitArea(area Area, direction Direction, spacing int, constraints []Constraint) []Area {
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	space := area.Height
+	if direction == Horizontal {
+		space = area.Width
+	}
+
+	space = max(space, 0)
+	spacing = max(spacing, 0)
+
+	gaps := spacing * (len(constraints) - 1)
+	if gaps > space {
+		spacing = 0
+		gaps = 0
+	}
+
+	sizes := Solve(space-gaps, constraints)
+
+	areas := make([]Area, 0, len(sizes))
+	offset := 0
+
+	for _, size := range sizes {
+		a := area
+
+		if direction == Horizontal {
+			a.X += offset
+			a.Width = size
+		} else {
+			a.Y += offset
+			a.Height = size
+		}
+
+		areas = append(areas, a)
+		offset += size + spacing
+	}
+
+	return areas
+}
@@ -0,0 +1,222 @@
+package layout
+
+import "strconv"
+
+// Kind is the kind of a constraint.
+type Kind int
+
+const (
+	// FixedKind is the kind of a constraint that asks for an exact size.
+	FixedKind Kind = iota
+
+	// PercentageKind is the kind of a constraint that asks for a percentage of the
+	// available space.
+	PercentageKind
+
+	// RatioKind is the kind of a constraint that asks for a fraction of the available
+	// space.
+	RatioKind
+
+	// MinKind is the kind of a constraint that asks for at least a given size and
+	// grows with the leftover space.
+	MinKind
+
+	// MaxKind is the kind of a constraint that grows with the leftover space up to a
+	// given size.
+	MaxKind
+
+	// FillKind is the kind of a constraint that takes a share of the leftover space
+	// proportional to its weight.
+	FillKind
+)
+
+// String implements the errors.Enumer interface.
+func (k Kind) String() string {
+	return [...]string{
+		"fixed",
+		"percentage",
+		"ratio",
+		"min",
+		"max",
+		"fill",
+	}[k]
+}
+
+// Constraint is a constraint on the size of an element along an axis.
+type Constraint struct {
+	// kind is the kind of the constraint.
+	kind Kind
+
+	// value is the size, percentage, numerator or weight of the constraint.
+	value int
+
+	// denominator is the denominator of a ratio constraint.
+	denominator int
+}
+
+// String implements the fmt.Stringer interface.
+func (c Constraint) String() string {
+	switch c.kind {
+	case PercentageKind:
+		return "percentage(" + strconv.Itoa(c.value) + "%)"
+	case RatioKind:
+		return "ratio(" + strconv.Itoa(c.value) + "/" + strconv.Itoa(c.denominator) + ")"
+	default:
+		return c.kind.String() + "(" + strconv.Itoa(c.value) + ")"
+	}
+}
+
+// Kind returns the kind of the constraint.
+//
+// Returns:
+//   - Kind: The kind of the constraint.
+func (c Constraint) Kind() Kind {
+	return c.kind
+}
+
+// Fixed creates a constraint that asks for exactly the given size.
+//
+// Parameters:
+//   - size: The size. Negative values are treated as 0.
+//
+// Returns:
+//   - Constraint: The new constraint.
+func Fixed(size int) Constraint {
+	return Constraint{
+		kind:  FixedKind,
+		value: max(size, 0),
+	}
+}
+
+// Percentage creates a constraint that asks for a percentage of the available space.
+//
+// Parameters:
+//   - percent: The percentage. Clamped between 0 and 100.
+//
+// Returns:
+//   - Constraint: The new constraint.
+func Percentage(percent int) Constraint {
+	return Constraint{
+		kind:  PercentageKind,
+		value: min(max(percent, 0), 100),
+	}
+}
+
+// Ratio creates a constraint that asks for the given fraction of the available space.
+//
+// Parameters:
+//   - numerator: The numerator of the fraction. Negative values are treated as 0.
+//   - denominator: The denominator of the fraction. Values less than 1 are treated
+//     as 1.
+//
+// Returns:
+//   - Constraint: The new constraint.
+//
+// Fractions greater than 1 are treated as 1.
+func Ratio(numerator, denominator int) Constraint {
+	denominator = max(denominator, 1)
+
+	return Constraint{
+		kind:        RatioKind,
+		value:       min(max(numerator, 0), denominator),
+		denominator: denominator,
+	}
+}
+
+// Min creates a constraint that asks for at least the given size and grows with
+// the leftover space as a fill constraint of weight 1.
+//
+// Parameters:
+//   - size: The minimum size. Negative values are treated as 0.
+//
+// Returns:
+//   - Constraint: The new constraint.
+func Min(size int) Constraint {
+	return Constraint{
+		kind:  MinKind,
+		value: max(size, 0),
+	}
+}
+
+// Max creates a constraint that grows with the leftover space as a fill constraint
+// of weight 1 but never beyond the given size.
+//
+// Parameters:
+//   - size: The maximum size. Negative values are treated as 0.
+//
+// Returns:
+//   - Constraint: The new constraint.
+func Max(size int) Constraint {
+	return Constraint{
+		kind:  MaxKind,
+		value: max(size, 0),
+	}
+}
+
+// Fill creates a constraint that takes a share of the leftover space proportional
+// to its weight.
+//
+// Parameters:
+//   - weight: The weight. Values less than 1 are treated as 1.
+//
+// Returns:
+//   - Constraint: The new constraint.
+func Fill(weight int) Constraint {
+	return Constraint{
+		kind:  FillKind,
+		value: max(weight, 1),
+	}
+}
+
+// base is a helper method that returns the size the constraint asks for before any
+// leftover space is distributed.
+//
+// Parameters:
+//   - space: The available space.
+//
+// Returns:
+//   - int: The base size.
+func (c Constraint) base(space int) int {
+	switch c.kind {
+	case FixedKind, MinKind:
+		return c.value
+	case PercentageKind:
+		return space * c.value / 100
+	case RatioKind:
+		return space * c.value / c.denominator
+	default:
+		return 0
+	}
+}
+
+// weight is a helper method that returns the weight with which the constraint grows
+// with the leftover space.
+//
+// Returns:
+//   - int: The weight. 0 if the constraint does not grow.
+func (c Constraint) weight() int {
+	switch c.kind {
+	case MinKind, MaxKind:
+		return 1
+	case FillKind:
+		return c.value
+	default:
+		return 0
+	}
+}
+
+// priority is a helper method that returns how late the constraint is shrunk when
+// there is not enough space. Lower priorities are shrunk first.
+//
+// Returns:
+//   - int: The priority.
+func (c Constraint) priority() int {
+	switch c.kind {
+	case FixedKind:
+		return 2
+	case MinKind:
+		return 1
+	default:
+		return 0
+	}
+}
@@ -0,0 +1,135 @@
+package layout
+
+// Solve splits the given space among elements according to their constraints.
+//
+// The space is split in the following way:
+//  1. Every element gets the size its constraint asks for: fixed and min constraints
+//     their size, percentage and ratio constraints their share of the whole space,
+//     and max and fill constraints nothing.
+//  2. If there is space left, it is distributed among min, max and fill constraints
+//     proportionally to their weight (1 for min and max constraints) without
+//     exceeding the size of max constraints. Any remainder goes to the first
+//     elements. If no constraint can grow, the space is left unused at the end.
+//  3. If the elements do not fit, they are shrunk in the following order until they
+//     do: percentage and ratio constraints, then min constraints and, finally, fixed
+//     constraints. Within the same group, the last elements are shrunk first.
+//
+// Parameters:
+//   - space: The space to split. Negative values are treated as 0.
+//   - constraints: The constraints of the elements.
+//
+// Returns:
+//   - []int: The size of each element, in the same order as the constraints. Never
+//     negative and their sum never exceeds the space.
+func Solve(space int, constraints []Constraint) []int {
+	if len(constraints) == 0 {
+		return nil
+	}
+
+	space = max(space, 0)
+
+	sizes := make([]int, 0, len(constraints))
+	used := 0
+
+	for _, c := range constraints {
+		size := c.base(space)
+
+		sizes = append(sizes, size)
+		used += size
+	}
+
+	if used > space {
+		shrink(sizes, constraints, used-space)
+	} else if used < space {
+		grow(sizes, constraints, space-used)
+	}
+
+	return sizes
+}
+
+// shrink is a helper function that shrinks the elements by the given amount.
+//
+// Parameters:
+//   - sizes: The sizes of the elements.
+//   - constraints: The constraints of the elements.
+//   - excess: The amount by which to shrink the elements.
+func shrink(sizes []int, constraints []Constraint, excess int) {
+	for priority := 0; priority <= 2 && excess > 0; priority++ {
+		for i := len(sizes) - 1; i >= 0 && excess > 0; i-- {
+			if constraints[i].priority() != priority {
+				continue
+			}
+
+			delta := min(excess, sizes[i])
+
+			sizes[i] -= delta
+			excess -= delta
+		}
+	}
+}
+
+// grow is a helper function that distributes the leftover space among the elements
+// that can grow.
+//
+// Parameters:
+//   - sizes: The sizes of the elements.
+//   - constraints: The constraints of the elements.
+//   - leftover: The leftover space.
+func grow(sizes []int, constraints []Constraint, leftover int) {
+	can_grow := func(i int) bool {
+		c := constraints[i]
+
+		if c.weight() == 0 {
+			return false
+		}
+
+		return c.kind != MaxKind || sizes[i] < c.value
+	}
+
+	for leftover > 0 {
+		var total_weight int
+
+		for i, c := range constraints {
+			if can_grow(i) {
+				total_weight += c.weight()
+			}
+		}
+
+		if total_weight == 0 {
+			return
+		}
+
+		given := 0
+
+		for i, c := range constraints {
+			if !can_grow(i) {
+				continue
+			}
+
+			share := leftover * c.weight() / total_weight
+
+			if c.kind == MaxKind {
+				share = min(share, c.value-sizes[i])
+			}
+
+			sizes[i] += share
+			given += share
+		}
+
+		if given == 0 {
+			// Only the remainder is left; give it to the first elements.
+			for i := range constraints {
+				if leftover == given {
+					break
+				}
+
+				if can_grow(i) {
+					sizes[i]++
+					given++
+				}
+			}
+		}
+
+		leftover -= given
+	}
+}
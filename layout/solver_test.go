@@ -0,0 +1,61 @@
+package layout
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestSolve(t *testing.T) {
+	type solveTest struct {
+		space       int
+		constraints []Constraint
+		expected    []int
+	}
+
+	tests := []solveTest{
+		{space: 10, constraints: []Constraint{Fixed(3), Fill(1)}, expected: []int{3, 7}},
+		{space: 10, constraints: []Constraint{Fill(1), Fill(1), Fill(1)}, expected: []int{4, 3, 3}},
+		{space: 12, constraints: []Constraint{Fill(1), Fill(2)}, expected: []int{4, 8}},
+		{space: 20, constraints: []Constraint{Percentage(25), Ratio(1, 2), Fixed(2)}, expected: []int{5, 10, 2}},
+		{space: 20, constraints: []Constraint{Max(4), Min(3)}, expected: []int{4, 16}},
+		{space: 10, constraints: []Constraint{Fixed(2), Fixed(3)}, expected: []int{2, 3}},
+		{space: 5, constraints: []Constraint{Fixed(3), Min(2), Percentage(50)}, expected: []int{3, 2, 0}},
+		{space: 4, constraints: []Constraint{Fixed(3), Fixed(3)}, expected: []int{3, 1}},
+		{space: 0, constraints: []Constraint{Fixed(3), Fill(1)}, expected: []int{0, 0}},
+	}
+
+	for i, test := range tests {
+		sizes := Solve(test.space, test.constraints)
+
+		if !slices.Equal(sizes, test.expected) {
+			t.Errorf("At test %d, expected %v, but got %v", i, test.expected, sizes)
+		}
+	}
+}
+
+func TestSplitArea(t *testing.T) {
+	area := Area{X: 1, Y: 2, Width: 10, Height: 3}
+
+	areas := SplitArea(area, Horizontal, 2, []Constraint{Fixed(3), Fill(1)})
+
+	expected := []Area{
+		{X: 1, Y: 2, Width: 3, Height: 3},
+		{X: 6, Y: 2, Width: 5, Height: 3},
+	}
+
+	if !slices.Equal(areas, expected) {
+		t.Fatalf("Expected %v, but got %v", expected, areas)
+	}
+
+	// Not enough space for the spacing: it is dropped first.
+	areas = SplitArea(Area{Width: 1, Height: 1}, Horizontal, 2, []Constraint{Fill(1), Fill(1)})
+
+	expected = []Area{
+		{X: 0, Y: 0, Width: 1, Height: 1},
+		{X: 1, Y: 0, Width: 0, Height: 1},
+	}
+
+	if !slices.Equal(areas, expected) {
+		t.Fatalf("Expected %v, but got %v", expected, areas)
+	}
+}
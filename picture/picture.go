@@ -0,0 +1,386 @@
+package picture
+
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/draw"
+	"image/gif"
+	"io"
+	"sync"
+	"time"
+
+	_ "image/jpeg"
+	_ "image/png"
+
+	"github.com/PlayerR9/display/colors"
+	dtb "github.com/PlayerR9/display/table"
+)
+
+const (
+	// DefaultDelay is the delay of the frames of animated GIFs that do not have
+	// one. Browsers use the same value.
+	DefaultDelay time.Duration = 100 * time.Millisecond
+)
+
+// Picture is an image drawn with half-block characters, two pixels per cell. The
+// image is scaled to the area it is given while keeping its aspect ratio. It is a
+// table.Displayer and, when animated, an anim.Animation.
+type Picture struct {
+	// frames are the frames of the picture. Never empty.
+	frames []image.Image
+
+	// delays are the delays of the frames.
+	delays []time.Duration
+
+	// loops is the number of times the frames are played. Non-positive to loop
+	// forever.
+	loops int
+
+	// frame is the index of the current frame.
+	frame int
+
+	// played is the number of times the frames have been played.
+	played int
+
+	// next is when the next frame is due. Zero until the first step.
+	next time.Time
+
+	// width and height are the maximum size of the picture, in cells. Non-positive
+	// to fill the table.
+	width, height int
+
+	// depth is the colour depth the picture is drawn with.
+	depth colors.Depth
+
+	// dither tells whether colours are dithered when the depth is not
+	// DepthTrueColor.
+	dither bool
+
+	// cache is the last rendering of the picture. Nil if none.
+	cache *rendering
+
+	// mu is the mutex of the picture.
+	mu sync.Mutex
+}
+
+// New creates a new picture of a still image.
+//
+// Parameters:
+//   - img: The image.
+//
+// Returns:
+//   - *Picture: The new picture. Nil if img is nil.
+func New(img image.Image) *Picture {
+	if img == nil {
+		return nil
+	}
+
+	return &Picture{
+		frames: []image.Image{img},
+		delays: []time.Duration{0},
+		depth:  colors.DepthTrueColor,
+	}
+}
+
+// NewGIF creates a new picture of an animated GIF. The frames are composited
+// according to their disposal methods, so each frame is a complete image.
+//
+// Parameters:
+//   - g: The GIF.
+//
+// Returns:
+//   - *Picture: The new picture. Nil if g is nil or has no frames.
+func NewGIF(g *gif.GIF) *Picture {
+	if g == nil || len(g.Image) == 0 {
+		return nil
+	}
+
+	bounds := image.Rect(0, 0, g.Config.Width, g.Config.Height)
+	if bounds.Empty() {
+		bounds = g.Image[0].Bounds()
+	}
+
+	canvas := image.NewRGBA(bounds)
+
+	frames := make([]image.Image, 0, len(g.Image))
+	delays := make([]time.Duration, 0, len(g.Image))
+
+	for i, frame := range g.Image {
+		var previous *image.RGBA
+
+		disposal := byte(0)
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		if disposal == gif.DisposalPrevious {
+			previous = image.NewRGBA(bounds)
+			copy(previous.Pix, canvas.Pix)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+
+		snapshot := image.NewRGBA(bounds)
+		copy(snapshot.Pix, canvas.Pix)
+
+		frames = append(frames, snapshot)
+
+		delay := DefaultDelay
+		if i < len(g.Delay) && g.Delay[i] > 0 {
+			delay = time.Duration(g.Delay[i]) * 10 * time.Millisecond
+		}
+
+		delays = append(delays, delay)
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	// In a GIF, 0 loops forever and -1 plays once.
+	loops := 0
+	if g.LoopCount > 0 {
+		loops = g.LoopCount + 1
+	} else if g.LoopCount < 0 {
+		loops = 1
+	}
+
+	return &Picture{
+		frames: frames,
+		delays: delays,
+		loops:  loops,
+		depth:  colors.DepthTrueColor,
+	}
+}
+
+// Decode decodes a PNG, JPEG or GIF image. All the frames of GIFs are kept.
+//
+// Parameters:
+//   - r: The reader of the image.
+//
+// Returns:
+//   - *Picture: The picture. Nil if an error occurred.
+//   - error: An error if the image could not be read or decoded.
+func Decode(r io.Reader) (*Picture, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("could not read image: %w", err)
+	}
+
+	_, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode image: %w", err)
+	}
+
+	if format == "gif" {
+		g, err := gif.DecodeAll(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("could not decode gif: %w", err)
+		}
+
+		return NewGIF(g), nil
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("could not decode %s: %w", format, err)
+	}
+
+	return New(img), nil
+}
+
+// SetSize sets the maximum size of the picture. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - width: The maximum width, in cells. Non-positive to fill the table.
+//   - height: The maximum height, in cells. Non-positive to fill the table.
+func (p *Picture) SetSize(width, height int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.width, p.height = width, height
+	p.mu.Unlock()
+}
+
+// SetDepth sets the colour depth the picture is drawn with; for instance, the one
+// returned by colors.Detect. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - depth: The depth.
+func (p *Picture) SetDepth(depth colors.Depth) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.depth = depth
+	p.mu.Unlock()
+}
+
+// SetDither sets whether colours are dithered with Floyd-Steinberg error diffusion
+// when the depth is not DepthTrueColor. Dithering hides the banding of smooth
+// gradients at the cost of some noise. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - dither: True to dither colours.
+func (p *Picture) SetDither(dither bool) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.dither = dither
+	p.mu.Unlock()
+}
+
+// Frames returns the number of frames of the picture.
+//
+// Returns:
+//   - int: The number of frames. 0 with a nil receiver.
+func (p *Picture) Frames() int {
+	if p == nil {
+		return 0
+	}
+
+	return len(p.frames)
+}
+
+// Frame returns the index of the current frame.
+//
+// Returns:
+//   - int: The index. 0 with a nil receiver.
+func (p *Picture) Frame() int {
+	if p == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.frame
+}
+
+// SetFrame shows the given frame. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - frame: The index of the frame. It wraps around the number of frames.
+func (p *Picture) SetFrame(frame int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.frames)
+
+	p.frame = ((frame % n) + n) % n
+	p.next = time.Time{}
+}
+
+// NextFrame shows the frame after the current one, wrapping around. Does nothing
+// with a nil receiver.
+func (p *Picture) NextFrame() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.frame = (p.frame + 1) % len(p.frames)
+	p.next = time.Time{}
+}
+
+// Step implements the anim.Animation interface: the frames of an animated picture
+// are advanced according to their delays until it has played all its loops.
+func (p *Picture) Step(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if len(p.frames) < 2 {
+		return false
+	}
+
+	done := func() bool {
+		return p.loops > 0 && p.played >= p.loops
+	}
+
+	if p.next.IsZero() {
+		p.next = now.Add(p.delays[p.frame])
+		return !done()
+	}
+
+	for !done() && !now.Before(p.next) {
+		if p.frame == len(p.frames)-1 {
+			p.played++
+
+			if done() {
+				break
+			}
+		}
+
+		p.frame = (p.frame + 1) % len(p.frames)
+		p.next = p.next.Add(p.delays[p.frame])
+	}
+
+	return !done()
+}
+
+// Draw implements the table.Displayer interface. The picture is drawn from the
+// given coordinates, which are left at its last row. Transparent pixels leave
+// the cells of the table as they are.
+func (p *Picture) Draw(table *dtb.Table, x, y *int) error {
+	if p == nil {
+		return nil
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	width, height := p.width, p.height
+
+	if width <= 0 {
+		width = table.Width() - *x
+	}
+
+	if height <= 0 {
+		height = table.Height() - *y
+	}
+
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	key := render_key{
+		frame:  p.frame,
+		width:  width,
+		height: height,
+		depth:  p.depth,
+		dither: p.dither && p.depth != colors.DepthTrueColor,
+	}
+
+	if p.cache == nil || p.cache.key != key {
+		p.cache = render(p.frames[p.frame], key)
+	}
+
+	left, top := *x, *y
+
+	for row, cells := range p.cache.cells {
+		for col, cell := range cells {
+			if cell != nil {
+				table.WriteAt(left+col, top+row, cell)
+			}
+		}
+	}
+
+	*y += max(len(p.cache.cells)-1, 0)
+
+	return nil
+}
@@ -0,0 +1,138 @@
+package picture
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/gif"
+	"image/png"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PlayerR9/display/colors"
+	"github.com/PlayerR9/display/internal/drawtest"
+	"github.com/gdamore/tcell"
+)
+
+func filled(w, h int, c color.Color) *image.RGBA {
+	img := image.NewRGBA(image.Rect(0, 0, w, h))
+
+	for y := range h {
+		for x := range w {
+			img.Set(x, y, c)
+		}
+	}
+
+	return img
+}
+
+func TestHalfBlocks(t *testing.T) {
+	img := filled(1, 2, color.RGBA{R: 255, A: 255})
+	img.Set(0, 1, color.RGBA{B: 255, A: 255})
+
+	table := drawtest.Draw(t, New(img), 1, 1)
+
+	cell := table.CellAt(0, 0)
+	if cell == nil || cell.Char != '▀' {
+		t.Fatalf("Expected a half block, but got %v", cell)
+	}
+
+	fg, bg, _ := cell.Style.Decompose()
+	if fg != tcell.NewRGBColor(255, 0, 0) || bg != tcell.NewRGBColor(0, 0, 255) {
+		t.Errorf("Expected red over blue, but got %v over %v", fg, bg)
+	}
+}
+
+func TestAspectRatio(t *testing.T) {
+	p := New(filled(4, 2, color.White))
+
+	// 4x2 pixels scale to 10x5 pixels: 10 columns and 3 rows, the last one half
+	// empty.
+	lines := drawtest.Draw(t, p, 10, 10).GetLines()
+
+	want := []string{
+		"▀▀▀▀▀▀▀▀▀▀",
+		"▀▀▀▀▀▀▀▀▀▀",
+		"▀▀▀▀▀▀▀▀▀▀",
+		"          ",
+	}
+
+	for i, line := range want {
+		if lines[i] != line {
+			t.Fatalf("Expected:\n%s\nbut got:\n%s", strings.Join(want, "\n"), strings.Join(lines[:len(want)], "\n"))
+		}
+	}
+}
+
+func TestDither(t *testing.T) {
+	p := New(filled(8, 8, color.Gray{Y: 128}))
+	p.SetDepth(colors.DepthMono)
+
+	flat := drawtest.Draw(t, p, 8, 4).GetLines()
+	if strings.Trim(strings.Join(flat, ""), "█") != "" {
+		t.Errorf("Expected a uniform grey without dithering, but got %q", flat)
+	}
+
+	p.SetDither(true)
+
+	dithered := strings.Join(drawtest.Draw(t, p, 8, 4).GetLines(), "")
+	if !strings.ContainsAny(dithered, " ▀▄") || !strings.ContainsAny(dithered, "█▀▄") {
+		t.Errorf("Expected a mix of lit and unlit pixels, but got %q", dithered)
+	}
+}
+
+func TestGIF(t *testing.T) {
+	palette := color.Palette{color.Black, color.White}
+
+	g := &gif.GIF{
+		Image: []*image.Paletted{
+			image.NewPaletted(image.Rect(0, 0, 2, 2), palette),
+			image.NewPaletted(image.Rect(0, 0, 2, 2), palette),
+		},
+		Delay:     []int{10, 20},
+		LoopCount: -1,
+	}
+
+	p := NewGIF(g)
+	if p.Frames() != 2 {
+		t.Fatalf("Expected 2 frames, but got %d", p.Frames())
+	}
+
+	start := time.Unix(0, 0)
+
+	if !p.Step(start) || p.Frame() != 0 {
+		t.Fatalf("Expected the first frame to be running")
+	}
+
+	if !p.Step(start.Add(100*time.Millisecond)) || p.Frame() != 1 {
+		t.Fatalf("Expected the second frame after 100ms, but got frame %d", p.Frame())
+	}
+
+	if p.Step(start.Add(300*time.Millisecond)) || p.Frame() != 1 {
+		t.Fatalf("Expected the animation to stop on its last frame, but got frame %d", p.Frame())
+	}
+}
+
+func TestDecode(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := png.Encode(&buf, filled(2, 2, color.White))
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	p, err := Decode(&buf)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if p.Frames() != 1 {
+		t.Errorf("Expected 1 frame, but got %d", p.Frames())
+	}
+
+	_, err = Decode(strings.NewReader("not an image"))
+	if err == nil {
+		t.Errorf("Expected an error")
+	}
+}
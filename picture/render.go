@@ -0,0 +1,330 @@
+package picture
+
+import (
+	"image"
+	"math"
+
+	"github.com/PlayerR9/display/colors"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// render_key identifies a rendering of a picture.
+type render_key struct {
+	// frame is the index of the frame.
+	frame int
+
+	// width and height are the maximum size of the rendering, in cells.
+	width, height int
+
+	// depth is the colour depth of the rendering.
+	depth colors.Depth
+
+	// dither tells whether the colours are dithered.
+	dither bool
+}
+
+// rendering is a frame of a picture turned into cells.
+type rendering struct {
+	// key identifies the rendering.
+	key render_key
+
+	// cells are the cells of the rendering, row by row. Nil cells are transparent.
+	cells [][]*dtb.Cell
+}
+
+// rgba is a colour with components in [0, 1], not premultiplied.
+type rgba struct {
+	r, g, b, a float64
+}
+
+// pixel is a pixel of a rendering.
+type pixel struct {
+	// color is the colour of the pixel.
+	color tcell.Color
+
+	// opaque tells whether the pixel is drawn at all.
+	opaque bool
+
+	// lit tells whether the pixel is lit, in DepthMono.
+	lit bool
+}
+
+// fit is a helper function that returns the largest size, in pixels, that an image
+// can be scaled to within the given number of cells while keeping its aspect ratio.
+// Half-block pixels are about square, so no correction is needed.
+//
+// Parameters:
+//   - iw, ih: The size of the image, in pixels.
+//   - width, height: The size of the area, in cells.
+//
+// Returns:
+//   - int: The width, in pixels.
+//   - int: The height, in pixels.
+func fit(iw, ih, width, height int) (int, int) {
+	if iw <= 0 || ih <= 0 {
+		return 0, 0
+	}
+
+	s := min(float64(width)/float64(iw), float64(2*height)/float64(ih))
+
+	ow := min(max(int(math.Round(float64(iw)*s)), 1), width)
+	oh := min(max(int(math.Round(float64(ih)*s)), 1), 2*height)
+
+	return ow, oh
+}
+
+// sample is a helper function that scales an image by averaging the source pixels
+// that fall in each output pixel.
+//
+// Parameters:
+//   - img: The image.
+//   - ow, oh: The size of the output, in pixels.
+//
+// Returns:
+//   - []rgba: The pixels of the output, row by row.
+func sample(img image.Image, ow, oh int) []rgba {
+	bounds := img.Bounds()
+	iw, ih := bounds.Dx(), bounds.Dy()
+
+	out := make([]rgba, ow*oh)
+
+	for y := range oh {
+		y0 := y * ih / oh
+		y1 := max((y+1)*ih/oh, y0+1)
+
+		for x := range ow {
+			x0 := x * iw / ow
+			x1 := max((x+1)*iw/ow, x0+1)
+
+			var r, g, b, a float64
+
+			for sy := y0; sy < y1; sy++ {
+				for sx := x0; sx < x1; sx++ {
+					pr, pg, pb, pa := img.At(bounds.Min.X+sx, bounds.Min.Y+sy).RGBA()
+
+					r += float64(pr)
+					g += float64(pg)
+					b += float64(pb)
+					a += float64(pa)
+				}
+			}
+
+			if a == 0 {
+				continue
+			}
+
+			n := float64((x1 - x0) * (y1 - y0))
+
+			out[y*ow+x] = rgba{
+				r: r / a,
+				g: g / a,
+				b: b / a,
+				a: a / n / 0xffff,
+			}
+		}
+	}
+
+	return out
+}
+
+// clamp is a helper function that clamps a component to [0, 1].
+//
+// Parameters:
+//   - v: The component.
+//
+// Returns:
+//   - float64: The clamped component.
+func clamp(v float64) float64 {
+	return min(max(v, 0), 1)
+}
+
+// to_color is a helper function that converts a colour to a tcell.Color.
+//
+// Parameters:
+//   - c: The colour.
+//
+// Returns:
+//   - tcell.Color: The colour.
+func to_color(c rgba) tcell.Color {
+	return tcell.NewRGBColor(
+		int32(math.Round(clamp(c.r)*255)),
+		int32(math.Round(clamp(c.g)*255)),
+		int32(math.Round(clamp(c.b)*255)),
+	)
+}
+
+// from_color is a helper function that converts a tcell.Color to a colour.
+//
+// Parameters:
+//   - c: The colour.
+//
+// Returns:
+//   - rgba: The opaque colour.
+func from_color(c tcell.Color) rgba {
+	hex := c.Hex()
+
+	return rgba{
+		r: float64((hex>>16)&0xff) / 255,
+		g: float64((hex>>8)&0xff) / 255,
+		b: float64(hex&0xff) / 255,
+		a: 1,
+	}
+}
+
+// quantize is a helper function that maps the pixels of an image to the colours of
+// the given depth, optionally diffusing the error of each pixel to its neighbours
+// with the Floyd-Steinberg weights.
+//
+// Parameters:
+//   - src: The pixels, row by row. It is modified when dithering.
+//   - ow, oh: The size of the image, in pixels.
+//   - depth: The colour depth.
+//   - dither: True to dither the colours.
+//
+// Returns:
+//   - []pixel: The pixels, row by row.
+func quantize(src []rgba, ow, oh int, depth colors.Depth, dither bool) []pixel {
+	out := make([]pixel, len(src))
+
+	spread := func(x, y int, er, eg, eb float64, w float64) {
+		if x < 0 || x >= ow || y >= oh {
+			return
+		}
+
+		c := &src[y*ow+x]
+
+		c.r += er * w
+		c.g += eg * w
+		c.b += eb * w
+	}
+
+	for y := range oh {
+		for x := range ow {
+			c := src[y*ow+x]
+
+			if c.a < 0.5 {
+				continue
+			}
+
+			var got rgba
+
+			switch depth {
+			case colors.DepthTrueColor:
+				out[y*ow+x] = pixel{color: to_color(c), opaque: true}
+				continue
+			case colors.DepthMono:
+				luma := 0.2126*c.r + 0.7152*c.g + 0.0722*c.b
+				lit := luma >= 0.5
+
+				out[y*ow+x] = pixel{color: tcell.ColorDefault, opaque: true, lit: lit}
+
+				if lit {
+					got = rgba{r: 1, g: 1, b: 1}
+				}
+			default:
+				color := colors.Nearest(to_color(c), depth)
+
+				out[y*ow+x] = pixel{color: color, opaque: true}
+				got = from_color(color)
+			}
+
+			if !dither {
+				continue
+			}
+
+			er, eg, eb := c.r-got.r, c.g-got.g, c.b-got.b
+
+			if depth == colors.DepthMono {
+				luma := 0.2126*er + 0.7152*eg + 0.0722*eb
+				er, eg, eb = luma, luma, luma
+			}
+
+			spread(x+1, y, er, eg, eb, 7.0/16)
+			spread(x-1, y+1, er, eg, eb, 3.0/16)
+			spread(x, y+1, er, eg, eb, 5.0/16)
+			spread(x+1, y+1, er, eg, eb, 1.0/16)
+		}
+	}
+
+	return out
+}
+
+// render is a helper function that turns a frame into cells.
+//
+// Parameters:
+//   - img: The frame.
+//   - key: The parameters of the rendering.
+//
+// Returns:
+//   - *rendering: The rendering. Never returns nil.
+func render(img image.Image, key render_key) *rendering {
+	result := &rendering{
+		key: key,
+	}
+
+	bounds := img.Bounds()
+
+	ow, oh := fit(bounds.Dx(), bounds.Dy(), key.width, key.height)
+	if ow == 0 || oh == 0 {
+		return result
+	}
+
+	pixels := quantize(sample(img, ow, oh), ow, oh, key.depth, key.dither)
+	base := theme.RoleText.Style()
+
+	at := func(x, y int) pixel {
+		if y >= oh {
+			return pixel{}
+		}
+
+		return pixels[y*ow+x]
+	}
+
+	result.cells = make([][]*dtb.Cell, (oh+1)/2)
+
+	for row := range result.cells {
+		cells := make([]*dtb.Cell, ow)
+
+		for col := range cells {
+			upper, lower := at(col, 2*row), at(col, 2*row+1)
+
+			if key.depth == colors.DepthMono {
+				if !upper.opaque && !lower.opaque {
+					continue
+				}
+
+				var char rune
+
+				switch {
+				case upper.lit && lower.lit:
+					char = '█'
+				case upper.lit:
+					char = '▀'
+				case lower.lit:
+					char = '▄'
+				default:
+					char = ' '
+				}
+
+				cells[col] = dtb.NewCell(char, base)
+
+				continue
+			}
+
+			switch {
+			case upper.opaque && lower.opaque:
+				cells[col] = dtb.NewCell('▀', base.Foreground(upper.color).Background(lower.color))
+			case upper.opaque:
+				cells[col] = dtb.NewCell('▀', base.Foreground(upper.color))
+			case lower.opaque:
+				cells[col] = dtb.NewCell('▄', base.Foreground(lower.color))
+			}
+		}
+
+		result.cells[row] = cells
+	}
+
+	return result
+}
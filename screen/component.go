@@ -26,6 +26,42 @@ type Drawable interface {
 	BgStyle() tcell.Style
 }
 
+// table_drawable is a Drawable that draws on a table.
+type table_drawable struct {
+	// table is the table to draw on.
+	table *dtb.Table
+
+	// bg_style is the background style.
+	bg_style tcell.Style
+}
+
+// DrawCell implements the Drawable interface.
+func (td table_drawable) DrawCell(x, y int, char rune, style tcell.Style) {
+	td.table.WriteAt(x, y, dtb.NewCell(char, style))
+}
+
+// BgStyle implements the Drawable interface.
+func (td table_drawable) BgStyle() tcell.Style {
+	return td.bg_style
+}
+
+// NewTableDrawable creates a Drawable that draws on a table; for instance, to draw
+// a TextBox from the Draw method of a Drawer. Cells outside of the table are not
+// drawn.
+//
+// Parameters:
+//   - table: The table to draw on. Assumed not nil.
+//   - bg_style: The background style.
+//
+// Returns:
+//   - Drawable: The drawable. Never returns nil.
+func NewTableDrawable(table *dtb.Table, bg_style tcell.Style) Drawable {
+	return table_drawable{
+		table:    table,
+		bg_style: bg_style,
+	}
+}
+
 // Drawer is a table drawer.
 type Drawer interface {
 	// DrawTable draws the table.
@@ -43,6 +79,42 @@ type Drawer interface {
 	Draw(screen *dtb.Table, x_coord, y_coord *int) error
 }
 
+// Handler is an element that can react to input events.
+type Handler interface {
+	// HandleEvent handles an input event (e.g., *tcell.EventKey or *tcell.EventMouse).
+	// Mouse events use the coordinates of the screen.
+	//
+	// Parameters:
+	//   - ev: The event to handle. Assumed not nil.
+	//
+	// Returns:
+	//   - bool: True if the event was consumed, false otherwise.
+	HandleEvent(ev tcell.Event) bool
+}
+
+// Focuser is a handler that is told when it gains or loses the focus; for
+// instance, to highlight itself.
+type Focuser interface {
+	Handler
+
+	// SetFocused is called whenever the element gains or loses the focus.
+	//
+	// Parameters:
+	//   - focused: True if the element gained the focus, false if it lost it.
+	SetFocused(focused bool)
+}
+
+// Arranger is an element that is told the area assigned to its node; for instance,
+// to translate the coordinates of mouse events, which use the coordinates of the
+// screen.
+type Arranger interface {
+	// SetArea is called whenever the node of the element is arranged.
+	//
+	// Parameters:
+	//   - area: The area of the node.
+	SetArea(area Rect)
+}
+
 type Display struct {
 	buffer *dtb.Table
 	frame  *dtb.Table
@@ -64,6 +136,16 @@ func (d *Display) resize(new_width, new_height int) error {
 		return err
 	}
 
+	err = d.frame.ResizeWidth(new_width)
+	if err != nil {
+		return err
+	}
+
+	err = d.frame.ResizeHeight(new_height)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
@@ -0,0 +1,156 @@
+package screen
+
+import (
+	"io"
+	"strconv"
+)
+
+// CursorShape is the shape of the terminal cursor.
+type CursorShape int
+
+const (
+	// CursorDefault is the shape configured in the terminal.
+	CursorDefault CursorShape = iota
+
+	// CursorBlinkingBlock is a blinking block.
+	CursorBlinkingBlock
+
+	// CursorBlock is a steady block.
+	CursorBlock
+
+	// CursorBlinkingUnderline is a blinking underline.
+	CursorBlinkingUnderline
+
+	// CursorUnderline is a steady underline.
+	CursorUnderline
+
+	// CursorBlinkingBar is a blinking vertical bar.
+	CursorBlinkingBar
+
+	// CursorBar is a steady vertical bar.
+	CursorBar
+)
+
+// String implements the fmt.Stringer interface.
+func (cs CursorShape) String() string {
+	return [...]string{
+		"default",
+		"blinking block",
+		"block",
+		"blinking underline",
+		"underline",
+		"blinking bar",
+		"bar",
+	}[cs]
+}
+
+// sequence returns the DECSCUSR escape sequence that selects the shape. The values
+// of the constants follow the parameter of the sequence.
+//
+// Returns:
+//   - string: The escape sequence.
+func (cs CursorShape) sequence() string {
+	return "\x1b[" + strconv.Itoa(int(cs)) + " q"
+}
+
+// Cursorer is an element that wants the terminal cursor; for instance, a text
+// input. When the element has the focus (or is the top-most popup), the screen
+// places the cursor at the reported position after each frame. Otherwise, the
+// cursor is hidden.
+type Cursorer interface {
+	// Cursor returns the desired position and shape of the cursor.
+	//
+	// Returns:
+	//   - int: The x coordinate, relative to the area of the element.
+	//   - int: The y coordinate, relative to the area of the element.
+	//   - CursorShape: The shape of the cursor.
+	//   - bool: False if the element does not want the cursor right now.
+	Cursor() (int, int, CursorShape, bool)
+}
+
+// cursor_state is the cursor of a frame.
+type cursor_state struct {
+	// x and y are the coordinates of the cursor on the screen.
+	x, y int
+
+	// shape is the shape of the cursor.
+	shape CursorShape
+
+	// visible is true if the cursor is shown.
+	visible bool
+}
+
+// cursor_target is a helper method that computes where the cursor of the next
+// frame goes: the top-most popup, if any, decides; otherwise, the focused node of
+// the active workspace does. The cursor must be inside the area of the element.
+//
+// Returns:
+//   - cursor_state: The cursor.
+func (s *Screen) cursor_target() cursor_state {
+	var area Rect
+	var elem any
+
+	if top := s.TopPopup(); top != nil {
+		area = top.Area(s.dt.frame.Width(), s.dt.frame.Height())
+		elem = top
+	} else {
+		focus := s.ActiveWorkspace().Focus()
+
+		area = focus.Rect()
+		elem = focus.Element()
+	}
+
+	c, ok := elem.(Cursorer)
+	if !ok {
+		return cursor_state{}
+	}
+
+	x, y, shape, ok := c.Cursor()
+	if !ok || !(Rect{Width: area.Width, Height: area.Height}).Contains(x, y) {
+		return cursor_state{}
+	}
+
+	return cursor_state{
+		x:       area.X + x,
+		y:       area.Y + y,
+		shape:   shape,
+		visible: true,
+	}
+}
+
+// place_cursor is a helper method that shows or hides the terminal cursor.
+//
+// Parameters:
+//   - cursor: The cursor of the frame.
+//
+// Assertions:
+//   - s.mu is locked.
+func (s *Screen) place_cursor(cursor cursor_state) {
+	if !cursor.visible {
+		s.screen.HideCursor()
+
+		return
+	}
+
+	s.screen.ShowCursor(cursor.x, cursor.y)
+	s.set_cursor_shape(cursor.shape)
+}
+
+// set_cursor_shape is a helper method that changes the shape of the cursor, if
+// the terminal supports it.
+//
+// Parameters:
+//   - shape: The shape of the cursor.
+//
+// Assertions:
+//   - s.mu is locked.
+func (s *Screen) set_cursor_shape(shape CursorShape) {
+	if s.cursor_out == nil || shape == s.cursor_shape {
+		return
+	}
+
+	_, err := io.WriteString(s.cursor_out, shape.sequence())
+	if err == nil {
+		s.cursor_shape = shape
+	}
+}
@@ -0,0 +1,522 @@
+package screen
+
+import (
+	"sync"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// DialogCancelled is the button index reported when a dialog is cancelled.
+	DialogCancelled int = -1
+
+	// DialogMinWidth is the minimum width of the content of a dialog.
+	DialogMinWidth int = 20
+)
+
+// Dialog is a modal popup that shows a message, an optional input field and a row
+// of buttons.
+//
+// Keys:
+//   - Tab, Right: Select the next button.
+//   - Shift+Tab, Left: Select the previous button.
+//   - Enter: Press the selected button.
+//   - Esc: Cancel the dialog.
+//   - Any other key edits the input field, if any.
+type Dialog struct {
+	// title is the title of the dialog.
+	title string
+
+	// message is the message of the dialog.
+	message string
+
+	// buttons are the labels of the buttons.
+	buttons []string
+
+	// selected is the index of the selected button.
+	selected int
+
+	// has_input is true if the dialog has an input field.
+	has_input bool
+
+	// input is the content of the input field.
+	input []rune
+
+	// style is the style of the dialog.
+	style tcell.Style
+
+	// themed is true if the styles are taken from the current theme.
+	themed bool
+
+	// focus_style is the style of the selected button and of the input field.
+	focus_style tcell.Style
+
+	// on_close is called once the dialog is closed.
+	on_close func(button int, input string)
+
+	// screen is the screen the dialog is open on.
+	screen *Screen
+
+	// rect is the last area of the dialog.
+	rect Rect
+
+	// button_x are the x coordinates of the buttons relative to the dialog, as of
+	// the last draw.
+	button_x []int
+
+	// cursor_x and cursor_y are the coordinates of the end of the input relative to
+	// the dialog, as of the last draw.
+	cursor_x, cursor_y int
+
+	// mu is the mutex of the dialog.
+	mu sync.Mutex
+}
+
+// NewDialog creates a new dialog.
+//
+// Parameters:
+//   - title: The title of the dialog.
+//   - message: The message of the dialog. It is wrapped to the width of the dialog.
+//   - buttons: The labels of the buttons. If empty, a single "OK" button is used.
+//   - on_close: The function called, from the event loop, once the dialog is closed
+//     with the index of the pressed button (DialogCancelled if cancelled) and the
+//     content of the input field. Can be nil.
+//
+// Returns:
+//   - *Dialog: The new dialog. Never returns nil.
+func NewDialog(title, message string, buttons []string, on_close func(button int, input string)) *Dialog {
+	if len(buttons) == 0 {
+		buttons = []string{"OK"}
+	}
+
+	return &Dialog{
+		title:    title,
+		message:  message,
+		buttons:  buttons,
+		themed:   true,
+		on_close: on_close,
+	}
+}
+
+// EnableInput adds an input field to the dialog. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - initial: The initial content of the input field.
+func (d *Dialog) EnableInput(initial string) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.has_input = true
+	d.input = []rune(initial)
+}
+
+// SetStyles changes the styles of the dialog, which otherwise come from the current
+// theme. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - style: The style of the dialog.
+//   - focus_style: The style of the selected button and of the input field.
+func (d *Dialog) SetStyles(style, focus_style tcell.Style) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.style = style
+	d.focus_style = focus_style
+	d.themed = false
+}
+
+// styles is a helper method that returns the styles of the dialog.
+//
+// Returns:
+//   - tcell.Style: The style of the dialog.
+//   - tcell.Style: The focus style of the dialog.
+func (d *Dialog) styles() (tcell.Style, tcell.Style) {
+	if !d.themed {
+		return d.style, d.focus_style
+	}
+
+	return theme.RoleDialog.Style(), theme.RoleDialogFocused.Style()
+}
+
+// content_width is a helper method that returns the width of the content of the
+// dialog given the width of the screen.
+//
+// Parameters:
+//   - width: The width of the screen.
+//
+// Returns:
+//   - int: The width of the content.
+func (d *Dialog) content_width(width int) int {
+	w := max(DialogMinWidth, len([]rune(d.title))+2, d.buttons_width())
+
+	for _, line := range wrap_words(d.message, max(width-4, 1)) {
+		w = max(w, len([]rune(line)))
+	}
+
+	return max(min(w, width-4), 1)
+}
+
+// buttons_width is a helper method that returns the width of the row of buttons.
+//
+// Returns:
+//   - int: The width of the row of buttons.
+func (d *Dialog) buttons_width() int {
+	var w int
+
+	for i, label := range d.buttons {
+		if i > 0 {
+			w++
+		}
+
+		w += len([]rune(label)) + 4
+	}
+
+	return w
+}
+
+// Area implements the Popup interface.
+//
+// The dialog is centered on the screen.
+func (d *Dialog) Area(width, height int) Rect {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	content := d.content_width(width)
+	lines := wrap_words(d.message, content)
+
+	h := len(lines) + 4
+	if d.has_input {
+		h += 2
+	}
+
+	w := content + 4
+	h = min(h, height)
+
+	d.rect = Rect{
+		X:      max((width-w)/2, 0),
+		Y:      max((height-h)/2, 0),
+		Width:  w,
+		Height: h,
+	}
+
+	return d.rect
+}
+
+// Cursor implements the Cursorer interface. The cursor is at the end of the input,
+// if any.
+func (d *Dialog) Cursor() (int, int, CursorShape, bool) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.cursor_x, d.cursor_y, CursorBar, d.has_input && d.cursor_y > 0
+}
+
+// Draw implements the Drawer interface.
+func (d *Dialog) Draw(table *dtb.Table, x_coord, y_coord *int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	style, focus_style := d.styles()
+
+	fill_table(table, style)
+	draw_border(table, style, d.title)
+
+	width := table.Width()
+	content := max(width-4, 1)
+
+	x, y := 2, 1
+
+	for _, line := range wrap_words(d.message, content) {
+		x = 2
+		table.WriteLineAt(&x, &y, line, style, true)
+		y++
+	}
+
+	if d.has_input {
+		y++
+
+		chars := d.input
+		if len(chars) >= content {
+			// Keep the end of the input (and the cursor) visible.
+			chars = chars[len(chars)-content+1:]
+		}
+
+		d.cursor_x, d.cursor_y = 2+len(chars), y
+
+		for i := 0; i < content; i++ {
+			c := ' '
+			if i < len(chars) {
+				c = chars[i]
+			}
+
+			table.WriteAt(2+i, y, dtb.NewCell(c, focus_style.Underline(true)))
+		}
+
+		y++
+	}
+
+	y++
+
+	d.button_x = d.button_x[:0]
+
+	x = max((width-d.buttons_width())/2, 2)
+
+	for i, label := range d.buttons {
+		label_style := style
+		if i == d.selected {
+			label_style = focus_style
+		}
+
+		d.button_x = append(d.button_x, x)
+
+		table.WriteLineAt(&x, &y, "[ "+label+" ]", label_style, true)
+		x++
+	}
+
+	*x_coord = width
+	*y_coord = table.Height()
+
+	return nil
+}
+
+// HandleEvent implements the Handler interface.
+func (d *Dialog) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		return d.handle_key(ev)
+	case *tcell.EventMouse:
+		if ev.Buttons()&tcell.Button1 == 0 {
+			return false
+		}
+
+		x, y := ev.Position()
+
+		d.mu.Lock()
+
+		idx := -1
+
+		if y == d.rect.Y+d.rect.Height-2 {
+			for i, bx := range d.button_x {
+				left := d.rect.X + bx
+
+				if x >= left && x < left+len([]rune(d.buttons[i]))+4 {
+					idx = i
+					break
+				}
+			}
+		}
+
+		d.mu.Unlock()
+
+		if idx == -1 {
+			return false
+		}
+
+		d.close(idx)
+
+		return true
+	}
+
+	return false
+}
+
+// handle_key is a helper method that handles a key event.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the event was consumed, false otherwise.
+func (d *Dialog) handle_key(ev *tcell.EventKey) bool {
+	d.mu.Lock()
+
+	switch ev.Key() {
+	case tcell.KeyEscape:
+		d.mu.Unlock()
+		d.close(DialogCancelled)
+
+		return true
+	case tcell.KeyEnter:
+		selected := d.selected
+		d.mu.Unlock()
+		d.close(selected)
+
+		return true
+	case tcell.KeyTab, tcell.KeyRight:
+		d.selected = (d.selected + 1) % len(d.buttons)
+	case tcell.KeyBacktab, tcell.KeyLeft:
+		d.selected = (d.selected + len(d.buttons) - 1) % len(d.buttons)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if !d.has_input || len(d.input) == 0 {
+			d.mu.Unlock()
+
+			return false
+		}
+
+		d.input = d.input[:len(d.input)-1]
+	case tcell.KeyRune:
+		if !d.has_input {
+			d.mu.Unlock()
+
+			return false
+		}
+
+		d.input = append(d.input, ev.Rune())
+	default:
+		d.mu.Unlock()
+
+		return false
+	}
+
+	d.mu.Unlock()
+
+	return true
+}
+
+// close is a helper method that closes the dialog and reports the result.
+//
+// Parameters:
+//   - button: The index of the pressed button or DialogCancelled.
+func (d *Dialog) close(button int) {
+	d.mu.Lock()
+
+	screen := d.screen
+	d.screen = nil
+
+	input := string(d.input)
+	on_close := d.on_close
+
+	d.mu.Unlock()
+
+	if screen == nil {
+		// Already closed.
+		return
+	}
+
+	screen.ClosePopup(d)
+
+	if on_close != nil {
+		on_close(button, input)
+	}
+}
+
+// OpenDialog opens a dialog on top of the screen and dims what is underneath. The
+// dialog closes itself once a button is pressed or it is cancelled. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - dialog: The dialog to open. Nil dialogs are ignored.
+func (s *Screen) OpenDialog(dialog *Dialog) {
+	if s == nil || dialog == nil {
+		return
+	}
+
+	s.OpenPopup(dialog, true)
+}
+
+// Alert opens a dialog that shows a message with an "OK" button.
+//
+// Parameters:
+//   - title: The title of the dialog.
+//   - message: The message to show.
+//
+// Returns:
+//   - <-chan struct{}: The channel that receives a value once the dialog is closed.
+//     Never nil. With a nil receiver, it already holds the value.
+func (s *Screen) Alert(title, message string) <-chan struct{} {
+	ch := make(chan struct{}, 1)
+
+	if s == nil {
+		ch <- struct{}{}
+
+		return ch
+	}
+
+	s.OpenDialog(NewDialog(title, message, []string{"OK"}, func(int, string) {
+		ch <- struct{}{}
+	}))
+
+	return ch
+}
+
+// Confirm opens a dialog that asks a yes/no question.
+//
+// Parameters:
+//   - title: The title of the dialog.
+//   - message: The question to ask.
+//
+// Returns:
+//   - <-chan bool: The channel that receives true if the user pressed "Yes" and
+//     false if they pressed "No" or cancelled the dialog. Never nil. With a nil
+//     receiver, it already holds false.
+func (s *Screen) Confirm(title, message string) <-chan bool {
+	ch := make(chan bool, 1)
+
+	if s == nil {
+		ch <- false
+
+		return ch
+	}
+
+	s.OpenDialog(NewDialog(title, message, []string{"Yes", "No"}, func(button int, _ string) {
+		ch <- button == 0
+	}))
+
+	return ch
+}
+
+// PromptResult is the result of a prompt dialog.
+type PromptResult struct {
+	// Text is the text entered by the user.
+	Text string
+
+	// Ok is true if the user confirmed the prompt, false if they cancelled it.
+	Ok bool
+}
+
+// Prompt opens a dialog that asks the user to enter a line of text.
+//
+// Parameters:
+//   - title: The title of the dialog.
+//   - message: The message to show above the input field.
+//   - initial: The initial content of the input field.
+//
+// Returns:
+//   - <-chan PromptResult: The channel that receives the result once the dialog is
+//     closed. Never nil. With a nil receiver, it already holds the initial text as
+//     a cancelled result.
+func (s *Screen) Prompt(title, message, initial string) <-chan PromptResult {
+	ch := make(chan PromptResult, 1)
+
+	if s == nil {
+		ch <- PromptResult{
+			Text: initial,
+			Ok:   false,
+		}
+
+		return ch
+	}
+
+	dialog := NewDialog(title, message, []string{"OK", "Cancel"}, func(button int, input string) {
+		ch <- PromptResult{
+			Text: input,
+			Ok:   button == 0,
+		}
+	})
+
+	dialog.EnableInput(initial)
+
+	s.OpenDialog(dialog)
+
+	return ch
+}
@@ -0,0 +1,152 @@
+package screen
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestConfirm(t *testing.T) {
+	s := new_test_screen(t, 40, 10)
+
+	ch := s.Confirm("Quit", "Do you really want to quit?")
+
+	top := s.TopPopup()
+	if top == nil {
+		t.Fatalf("Expected the dialog to be open")
+	}
+
+	if !top.HandleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone)) {
+		t.Fatalf("Expected the dialog to consume the key")
+	}
+
+	top.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	select {
+	case ok := <-ch:
+		if ok {
+			t.Fatalf("Expected \"No\" to be pressed")
+		}
+	default:
+		t.Fatalf("Expected a result once the dialog is closed")
+	}
+
+	if s.TopPopup() != nil {
+		t.Fatalf("Expected the dialog to be closed")
+	}
+}
+
+func TestPopupCompose(t *testing.T) {
+	s := new_test_screen(t, 40, 10)
+
+	_, err := s.Start()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+	defer s.Close()
+
+	_, _, err = s.Show(&mockDrawer{text: "underneath"}, 0, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	ch := s.Prompt("Name", "Enter your name:", "Jo")
+
+	top := s.TopPopup()
+	top.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 'e', tcell.ModNone))
+
+	s.render()
+
+	lines := s.dt.frame.GetLines()
+
+	if !strings.Contains(strings.Join(lines, "\n"), "[ OK ]") {
+		t.Fatalf("Expected the dialog to be drawn, but got:\n%s", strings.Join(lines, "\n"))
+	}
+
+	sim, ok := s.screen.(*sim_screen)
+	if !ok {
+		t.Fatalf("Expected a simulation screen")
+	}
+
+	cx, cy, visible := sim.GetCursor()
+	if !visible || s.dt.frame.CellAt(cx-1, cy) == nil || s.dt.frame.CellAt(cx-1, cy).Char != 'e' {
+		t.Fatalf("Expected the cursor after the input, but got (%d, %d) visible=%t", cx, cy, visible)
+	}
+
+	cell := s.dt.frame.CellAt(0, 0)
+	if cell == nil || cell.Char != 'u' {
+		t.Fatalf("Expected the screen to be visible underneath the dialog")
+	}
+
+	_, _, attr := cell.Style.Decompose()
+	if attr&tcell.AttrDim == 0 {
+		t.Fatalf("Expected the screen to be dimmed underneath the dialog")
+	}
+
+	top.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	res := <-ch
+	if !res.Ok || res.Text != "Joe" {
+		t.Fatalf("Expected result to be {Joe true}, but got %v", res)
+	}
+
+	s.render()
+
+	cell = s.dt.frame.CellAt(0, 0)
+	if _, _, attr := cell.Style.Decompose(); attr&tcell.AttrDim != 0 {
+		t.Fatalf("Expected the screen to be restored once the dialog is closed")
+	}
+}
+
+func TestDialogOpenPopup(t *testing.T) {
+	s := new_test_screen(t, 40, 10)
+
+	var closed bool
+
+	dialog := NewDialog("Info", "Opened as a popup.", nil, func(button int, _ string) {
+		closed = button == DialogCancelled
+	})
+
+	s.OpenPopup(dialog, false)
+
+	if !dialog.HandleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone)) {
+		t.Fatalf("Expected the dialog to consume the key")
+	}
+
+	if !closed {
+		t.Fatalf("Expected the dialog to be cancelled")
+	}
+
+	if s.TopPopup() != nil {
+		t.Fatalf("Expected the dialog to be closed")
+	}
+}
+
+func TestDialogNilScreen(t *testing.T) {
+	var s *Screen
+
+	select {
+	case <-s.Alert("Info", "No screen."):
+	default:
+		t.Fatalf("Expected Alert to be resolved")
+	}
+
+	select {
+	case ok := <-s.Confirm("Quit", "No screen."):
+		if ok {
+			t.Fatalf("Expected Confirm to be false")
+		}
+	default:
+		t.Fatalf("Expected Confirm to be resolved")
+	}
+
+	select {
+	case res := <-s.Prompt("Name", "No screen.", "Jo"):
+		if res.Ok || res.Text != "Jo" {
+			t.Fatalf("Expected result to be {Jo false}, but got %v", res)
+		}
+	default:
+		t.Fatalf("Expected Prompt to be resolved")
+	}
+}
@@ -0,0 +1,330 @@
+package screen
+
+import (
+	"sync"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// Dropdown is a popup that shows a list of items below an anchor and lets the user
+// pick one of them.
+//
+// Keys:
+//   - Up, Down: Move the selection.
+//   - Enter: Pick the selected item.
+//   - Esc: Cancel the dropdown.
+//
+// Clicking an item picks it while clicking outside of the dropdown cancels it.
+type Dropdown struct {
+	// items are the items of the dropdown.
+	items []string
+
+	// selected is the index of the selected item.
+	selected int
+
+	// offset is the index of the first visible item.
+	offset int
+
+	// anchor_x is the x coordinate of the anchor.
+	anchor_x int
+
+	// anchor_y is the y coordinate of the anchor.
+	anchor_y int
+
+	// style is the style of the dropdown.
+	style tcell.Style
+
+	// themed is true if the styles are taken from the current theme.
+	themed bool
+
+	// focus_style is the style of the selected item.
+	focus_style tcell.Style
+
+	// on_close is called once the dropdown is closed.
+	on_close func(idx int)
+
+	// screen is the screen the dropdown is open on.
+	screen *Screen
+
+	// rect is the last area of the dropdown.
+	rect Rect
+
+	// mu is the mutex of the dropdown.
+	mu sync.Mutex
+}
+
+// NewDropdown creates a new dropdown.
+//
+// Parameters:
+//   - x: The x coordinate of the anchor.
+//   - y: The y coordinate of the anchor. The dropdown opens right below it if
+//     there is enough space and right above it otherwise.
+//   - items: The items of the dropdown.
+//   - on_close: The function called, from the event loop, once the dropdown is
+//     closed with the index of the picked item (DialogCancelled if cancelled).
+//     Can be nil.
+//
+// Returns:
+//   - *Dropdown: The new dropdown. Never returns nil.
+func NewDropdown(x, y int, items []string, on_close func(idx int)) *Dropdown {
+	return &Dropdown{
+		items:    items,
+		anchor_x: x,
+		anchor_y: y,
+		themed:   true,
+		on_close: on_close,
+	}
+}
+
+// SetStyles changes the styles of the dropdown, which otherwise come from the current
+// theme. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - style: The style of the dropdown.
+//   - focus_style: The style of the selected item.
+func (d *Dropdown) SetStyles(style, focus_style tcell.Style) {
+	if d == nil {
+		return
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	d.style = style
+	d.focus_style = focus_style
+	d.themed = false
+}
+
+// styles is a helper method that returns the styles of the dropdown.
+//
+// Returns:
+//   - tcell.Style: The style of the dropdown.
+//   - tcell.Style: The focus style of the dropdown.
+func (d *Dropdown) styles() (tcell.Style, tcell.Style) {
+	if !d.themed {
+		return d.style, d.focus_style
+	}
+
+	return theme.RoleMenu.Style(), theme.RoleMenuSelected.Style()
+}
+
+// Area implements the Popup interface.
+func (d *Dropdown) Area(width, height int) Rect {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	w := 0
+
+	for _, item := range d.items {
+		w = max(w, len([]rune(item)))
+	}
+
+	w = min(w+2, width)
+
+	below := height - d.anchor_y - 1
+	above := d.anchor_y
+
+	h := len(d.items) + 2
+	y := d.anchor_y + 1
+
+	if h > below && above > below {
+		h = min(h, above)
+		y = d.anchor_y - h
+	} else {
+		h = min(h, below)
+	}
+
+	d.rect = Rect{
+		X:      max(min(d.anchor_x, width-w), 0),
+		Y:      max(y, 0),
+		Width:  w,
+		Height: max(h, 0),
+	}
+
+	return d.rect
+}
+
+// visible is a helper method that returns the number of visible items.
+//
+// Returns:
+//   - int: The number of visible items.
+func (d *Dropdown) visible() int {
+	return max(d.rect.Height-2, 1)
+}
+
+// Draw implements the Drawer interface.
+func (d *Dropdown) Draw(table *dtb.Table, x_coord, y_coord *int) error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	style, focus_style := d.styles()
+
+	fill_table(table, style)
+	draw_border(table, style, "")
+
+	width := table.Width()
+	visible := d.visible()
+
+	if d.selected < d.offset {
+		d.offset = d.selected
+	} else if d.selected >= d.offset+visible {
+		d.offset = d.selected - visible + 1
+	}
+
+	for i := 0; i < visible && d.offset+i < len(d.items); i++ {
+		idx := d.offset + i
+
+		item_style := style
+		if idx == d.selected {
+			item_style = focus_style
+		}
+
+		x, y := 1, 1+i
+
+		line := pad_right(truncate(d.items[idx], width-2), width-2)
+
+		table.WriteLineAt(&x, &y, line, item_style, true)
+	}
+
+	*x_coord = width
+	*y_coord = table.Height()
+
+	return nil
+}
+
+// HandleEvent implements the Handler interface.
+func (d *Dropdown) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		d.mu.Lock()
+
+		switch ev.Key() {
+		case tcell.KeyUp:
+			d.selected = max(d.selected-1, 0)
+		case tcell.KeyDown:
+			d.selected = min(d.selected+1, len(d.items)-1)
+		case tcell.KeyPgUp:
+			d.selected = max(d.selected-d.visible(), 0)
+		case tcell.KeyPgDn:
+			d.selected = min(d.selected+d.visible(), len(d.items)-1)
+		case tcell.KeyEnter:
+			selected := d.selected
+			if len(d.items) == 0 {
+				selected = DialogCancelled
+			}
+
+			d.mu.Unlock()
+			d.close(selected)
+
+			return true
+		case tcell.KeyEscape:
+			d.mu.Unlock()
+			d.close(DialogCancelled)
+
+			return true
+		default:
+			d.mu.Unlock()
+
+			return false
+		}
+
+		d.mu.Unlock()
+
+		return true
+	case *tcell.EventMouse:
+		buttons := ev.Buttons()
+		x, y := ev.Position()
+
+		d.mu.Lock()
+
+		inside := d.rect.Contains(x, y)
+		idx := d.offset + y - d.rect.Y - 1
+
+		switch {
+		case buttons&tcell.WheelUp != 0:
+			d.selected = max(d.selected-1, 0)
+			d.mu.Unlock()
+
+			return true
+		case buttons&tcell.WheelDown != 0:
+			d.selected = min(d.selected+1, len(d.items)-1)
+			d.mu.Unlock()
+
+			return true
+		case buttons&tcell.Button1 == 0:
+			d.mu.Unlock()
+
+			return false
+		}
+
+		d.mu.Unlock()
+
+		if !inside {
+			d.close(DialogCancelled)
+		} else if idx >= 0 && idx < len(d.items) && y < d.rect.Y+d.rect.Height-1 {
+			d.close(idx)
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// close is a helper method that closes the dropdown and reports the result.
+//
+// Parameters:
+//   - idx: The index of the picked item or DialogCancelled.
+func (d *Dropdown) close(idx int) {
+	d.mu.Lock()
+
+	screen := d.screen
+	d.screen = nil
+
+	on_close := d.on_close
+
+	d.mu.Unlock()
+
+	if screen == nil {
+		return
+	}
+
+	screen.ClosePopup(d)
+
+	if on_close != nil {
+		on_close(idx)
+	}
+}
+
+// OpenDropdown opens a dropdown on top of the screen without dimming what is
+// underneath.
+//
+// Parameters:
+//   - x: The x coordinate of the anchor.
+//   - y: The y coordinate of the anchor.
+//   - items: The items of the dropdown.
+//
+// Returns:
+//   - <-chan int: The channel that receives the index of the picked item, or
+//     DialogCancelled if the dropdown was cancelled, once it is closed. Never nil.
+func (s *Screen) OpenDropdown(x, y int, items []string) <-chan int {
+	ch := make(chan int, 1)
+
+	dropdown := NewDropdown(x, y, items, func(idx int) {
+		ch <- idx
+	})
+
+	if s == nil {
+		return ch
+	}
+
+	dropdown.mu.Lock()
+	dropdown.screen = s
+	dropdown.mu.Unlock()
+
+	s.OpenPopup(dropdown, false)
+
+	return ch
+}
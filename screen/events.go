@@ -0,0 +1,37 @@
+package screen
+
+import "github.com/gdamore/tcell"
+
+// EventRedraw is the event posted to the event loop of a screen whenever the
+// component tree needs to be redrawn.
+type EventRedraw struct {
+	tcell.EventTime
+}
+
+// NewEventRedraw creates a new redraw event.
+//
+// Returns:
+//   - *EventRedraw: The new event. Never returns nil.
+func NewEventRedraw() *EventRedraw {
+	ev := &EventRedraw{}
+	ev.SetEventNow()
+
+	return ev
+}
+
+// EventTick is the event posted to the event loop of a screen whenever its animator
+// needs to be stepped.
+type EventTick struct {
+	tcell.EventTime
+}
+
+// NewEventTick creates a new tick event.
+//
+// Returns:
+//   - *EventTick: The new event. Never returns nil.
+func NewEventTick() *EventTick {
+	ev := &EventTick{}
+	ev.SetEventNow()
+
+	return ev
+}
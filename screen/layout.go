@@ -0,0 +1,239 @@
+package screen
+
+import dlo "github.com/PlayerR9/display/layout"
+
+// Layout is an interface that arranges the children of a node.
+type Layout interface {
+	// Arrange assigns an area to each child.
+	//
+	// Parameters:
+	//   - area: The area of the node whose children are arranged.
+	//   - children: The children to arrange. Assumed to not contain nil nodes.
+	//
+	// Returns:
+	//   - []Rect: The area of each child, in the same order as the children.
+	//
+	// NOTE: The returned slice must have the same length as the children. Areas may be
+	// empty if there is not enough space.
+	Arrange(area Rect, children []*Node) []Rect
+}
+
+// VBox is a layout that stacks its children vertically, from top to bottom.
+//
+// Children with a preferred height get (up to) that height while the remaining
+// space is split equally among the other children.
+type VBox struct {
+	// Spacing is the number of rows between two children.
+	Spacing int
+}
+
+// Arrange implements the Layout interface.
+func (l VBox) Arrange(area Rect, children []*Node) []Rect {
+	hints := make([]int, 0, len(children))
+
+	for _, child := range children {
+		_, height := child.Size()
+		hints = append(hints, height)
+	}
+
+	sizes := split_space(area.Height, hints, l.Spacing)
+
+	rects := make([]Rect, 0, len(children))
+	y := area.Y
+
+	for _, size := range sizes {
+		rects = append(rects, Rect{
+			X:      area.X,
+			Y:      y,
+			Width:  area.Width,
+			Height: size,
+		})
+
+		y += size + l.Spacing
+	}
+
+	return rects
+}
+
+// HBox is a layout that places its children horizontally, from left to right.
+//
+// Children with a preferred width get (up to) that width while the remaining
+// space is split equally among the other children.
+type HBox struct {
+	// Spacing is the number of columns between two children.
+	Spacing int
+}
+
+// Arrange implements the Layout interface.
+func (l HBox) Arrange(area Rect, children []*Node) []Rect {
+	hints := make([]int, 0, len(children))
+
+	for _, child := range children {
+		width, _ := child.Size()
+		hints = append(hints, width)
+	}
+
+	sizes := split_space(area.Width, hints, l.Spacing)
+
+	rects := make([]Rect, 0, len(children))
+	x := area.X
+
+	for _, size := range sizes {
+		rects = append(rects, Rect{
+			X:      x,
+			Y:      area.Y,
+			Width:  size,
+			Height: area.Height,
+		})
+
+		x += size + l.Spacing
+	}
+
+	return rects
+}
+
+// Stack is a layout that gives the whole area to every child. Children are drawn
+// in order and, thus, the last child is on top.
+type Stack struct{}
+
+// Arrange implements the Layout interface.
+func (l Stack) Arrange(area Rect, children []*Node) []Rect {
+	rects := make([]Rect, 0, len(children))
+
+	for range children {
+		rects = append(rects, area)
+	}
+
+	return rects
+}
+
+// Grid is a layout that places its children in a grid of equally sized cells, row
+// by row.
+type Grid struct {
+	// Columns is the number of columns of the grid. Values less than 1 are treated
+	// as 1.
+	Columns int
+
+	// Spacing is the number of rows and columns between two cells.
+	Spacing int
+}
+
+// Arrange implements the Layout interface.
+func (l Grid) Arrange(area Rect, children []*Node) []Rect {
+	columns := max(l.Columns, 1)
+	rows := (len(children) + columns - 1) / columns
+
+	widths := split_space(area.Width, make([]int, columns), l.Spacing)
+	heights := split_space(area.Height, make([]int, rows), l.Spacing)
+
+	rects := make([]Rect, 0, len(children))
+	y := area.Y
+
+	for row := 0; row < rows; row++ {
+		x := area.X
+
+		for col := 0; col < columns && len(rects) < len(children); col++ {
+			rects = append(rects, Rect{
+				X:      x,
+				Y:      y,
+				Width:  widths[col],
+				Height: heights[row],
+			})
+
+			x += widths[col] + l.Spacing
+		}
+
+		y += heights[row] + l.Spacing
+	}
+
+	return rects
+}
+
+// split_space is a helper function that splits the given space among elements.
+// Elements with a positive hint get (up to) that size, in order, while the
+// remaining space is split equally among the elements without hints; any remainder
+// is given to the first of them.
+//
+// Parameters:
+//   - total: The space to split.
+//   - hints: The preferred size of each element. 0 means no preference.
+//   - spacing: The space between two elements.
+//
+// Returns:
+//   - []int: The size of each element. Never negative.
+func split_space(total int, hints []int, spacing int) []int {
+	sizes := make([]int, len(hints))
+	if len(hints) == 0 {
+		return sizes
+	}
+
+	free := total - max(spacing, 0)*(len(hints)-1)
+
+	var flexible int
+
+	for i, hint := range hints {
+		if hint <= 0 {
+			flexible++
+
+			continue
+		}
+
+		sizes[i] = max(min(hint, free), 0)
+		free -= sizes[i]
+	}
+
+	if flexible == 0 || free <= 0 {
+		return sizes
+	}
+
+	share := free / flexible
+	remainder := free % flexible
+
+	for i, hint := range hints {
+		if hint > 0 {
+			continue
+		}
+
+		sizes[i] = share
+
+		if remainder > 0 {
+			sizes[i]++
+			remainder--
+		}
+	}
+
+	return sizes
+}
+
+// Constrained is a layout that splits its area along an axis according to the
+// constraint of each child. Children without a constraint fill the leftover space.
+type Constrained struct {
+	// Direction is the axis along which the area is split.
+	Direction dlo.Direction
+
+	// Spacing is the space between two children.
+	Spacing int
+
+	// Constraints are the constraints of the children, in order.
+	Constraints []dlo.Constraint
+}
+
+// Arrange implements the Layout interface.
+func (l Constrained) Arrange(area Rect, children []*Node) []Rect {
+	constraints := make([]dlo.Constraint, 0, len(children))
+	constraints = append(constraints, l.Constraints[:min(len(l.Constraints), len(children))]...)
+
+	for len(constraints) < len(children) {
+		constraints = append(constraints, dlo.Fill(1))
+	}
+
+	areas := dlo.SplitArea(dlo.Area(area), l.Direction, l.Spacing, constraints)
+
+	rects := make([]Rect, 0, len(areas))
+
+	for _, a := range areas {
+		rects = append(rects, Rect(a))
+	}
+
+	return rects
+}
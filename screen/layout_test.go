@@ -0,0 +1,123 @@
+package screen
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	dtb "github.com/PlayerR9/display/table"
+)
+
+func TestSplitSpace(t *testing.T) {
+	type splitTest struct {
+		total    int
+		hints    []int
+		spacing  int
+		expected []int
+	}
+
+	tests := []splitTest{
+		{total: 10, hints: []int{0, 0, 0}, spacing: 0, expected: []int{4, 3, 3}},
+		{total: 10, hints: []int{2, 0, 0}, spacing: 0, expected: []int{2, 4, 4}},
+		{total: 10, hints: []int{0, 0}, spacing: 2, expected: []int{4, 4}},
+		{total: 3, hints: []int{5, 0}, spacing: 0, expected: []int{3, 0}},
+		{total: 0, hints: []int{0, 0}, spacing: 1, expected: []int{0, 0}},
+	}
+
+	for i, test := range tests {
+		sizes := split_space(test.total, test.hints, test.spacing)
+
+		if !slices.Equal(sizes, test.expected) {
+			t.Errorf("At test %d, expected %v, but got %v", i, test.expected, sizes)
+		}
+	}
+}
+
+func TestNodeTree(t *testing.T) {
+	top := NewNode(&mockDrawer{text: "top"})
+	top.SetSize(0, 1)
+
+	left := NewNode(&mockDrawer{text: "left"})
+	right := NewNode(&mockDrawer{text: "right"})
+
+	root := NewContainer(VBox{}, top, NewContainer(HBox{Spacing: 1}, left, right))
+
+	var invalidated int
+
+	root.set_on_invalidate(func() {
+		invalidated++
+	})
+
+	root.Arrange(Rect{Width: 11, Height: 3})
+
+	if right.Rect() != (Rect{X: 6, Y: 1, Width: 5, Height: 2}) {
+		t.Fatalf("Expected right to be at {6 1 5 2}, but got %v", right.Rect())
+	}
+
+	table, err := dtb.NewTable(11, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	err = root.DrawTo(table)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	lines := table.GetLines()
+
+	if lines[0] != "top        " || lines[1] != "left  right" {
+		t.Fatalf("Expected lines to be %q, but got %q", []string{"top        ", "left  right"}, lines[:2])
+	}
+
+	if root.IsDirty() {
+		t.Fatalf("Expected root to not be dirty after drawing")
+	}
+
+	right.SetElement(&mockDrawer{text: "a very long text"})
+
+	if invalidated != 1 || !root.IsDirty() {
+		t.Fatalf("Expected invalidation to reach the root")
+	}
+
+	err = root.DrawTo(table)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if lines := table.GetLines(); lines[1] != "left  a ver" {
+		t.Fatalf("Expected right to be clipped, but got %q", lines[1])
+	}
+}
+
+func TestNodeAppendCycle(t *testing.T) {
+	leaf := NewNode(&mockDrawer{text: "leaf"})
+	middle := NewContainer(VBox{}, leaf)
+	root := NewContainer(VBox{}, middle)
+
+	// Appending an ancestor would make a cycle, so it is ignored. With a cycle,
+	// walking up the parents would never end.
+	done := make(chan struct{})
+
+	go func() {
+		leaf.Append(root)
+		leaf.Append(middle)
+		leaf.Invalidate()
+
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Append and Invalidate to return")
+	}
+
+	if root.Parent() != nil || middle.Parent() != root {
+		t.Fatalf("Expected the ancestors to keep their parents")
+	}
+
+	if len(leaf.Children()) != 0 {
+		t.Fatalf("Expected no children, but got %d", len(leaf.Children()))
+	}
+}
@@ -0,0 +1,526 @@
+package screen
+
+import (
+	"iter"
+	"slices"
+	"sync"
+
+	dtb "github.com/PlayerR9/display/table"
+	gda "github.com/PlayerR9/go-debug/assert"
+)
+
+// Rect is a rectangular area of the screen.
+type Rect struct {
+	// X is the x coordinate of the top-left corner.
+	X int
+
+	// Y is the y coordinate of the top-left corner.
+	Y int
+
+	// Width is the width of the area.
+	Width int
+
+	// Height is the height of the area.
+	Height int
+}
+
+// IsEmpty checks whether the area has no cells.
+//
+// Returns:
+//   - bool: True if the area is empty, false otherwise.
+func (r Rect) IsEmpty() bool {
+	return r.Width <= 0 || r.Height <= 0
+}
+
+// Contains checks whether the given coordinates are inside the area.
+//
+// Parameters:
+//   - x: The x coordinate.
+//   - y: The y coordinate.
+//
+// Returns:
+//   - bool: True if the coordinates are inside the area, false otherwise.
+func (r Rect) Contains(x, y int) bool {
+	return x >= r.X && x < r.X+r.Width && y >= r.Y && y < r.Y+r.Height
+}
+
+// Node is a node of the retained component tree. Each node has an optional element
+// that is drawn in the area assigned to the node and an optional layout that assigns
+// an area to each of its children.
+type Node struct {
+	// parent is the parent of the node.
+	parent *Node
+
+	// children are the children of the node.
+	children []*Node
+
+	// elem is the element drawn in the area of the node.
+	elem Drawer
+
+	// layout is the layout of the children.
+	layout Layout
+
+	// width is the preferred width of the node. 0 means no preference.
+	width int
+
+	// height is the preferred height of the node. 0 means no preference.
+	height int
+
+	// rect is the area that was last assigned to the node.
+	rect Rect
+
+	// dirty is true if the node needs to be redrawn.
+	dirty bool
+
+	// on_invalidate is called whenever a node of the tree is invalidated. Only
+	// used by the root.
+	on_invalidate func()
+
+	// mu is the mutex of the node.
+	mu sync.RWMutex
+}
+
+// NewNode creates a new node.
+//
+// Parameters:
+//   - elem: The element drawn in the area of the node. Can be nil.
+//
+// Returns:
+//   - *Node: The new node. Never returns nil.
+func NewNode(elem Drawer) *Node {
+	return &Node{
+		elem:  elem,
+		dirty: true,
+	}
+}
+
+// NewContainer creates a new node without an element whose children are arranged
+// by the given layout.
+//
+// Parameters:
+//   - layout: The layout of the children.
+//   - children: The children of the node. Nil children are ignored.
+//
+// Returns:
+//   - *Node: The new node. Never returns nil.
+func NewContainer(layout Layout, children ...*Node) *Node {
+	n := &Node{
+		layout: layout,
+		dirty:  true,
+	}
+
+	n.Append(children...)
+
+	return n
+}
+
+// Append appends the given children to the node. Children that already have a
+// parent are detached from it first. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - children: The children to append. Nil children, the node itself and its
+//     ancestors are ignored, as they would make a cycle.
+func (n *Node) Append(children ...*Node) {
+	if n == nil {
+		return
+	}
+
+	for _, child := range children {
+		if child == nil || n.has_ancestor(child) {
+			continue
+		}
+
+		old := child.Parent()
+		if old != nil {
+			old.Remove(child)
+		}
+
+		child.mu.Lock()
+		child.parent = n
+		child.mu.Unlock()
+
+		n.mu.Lock()
+		n.children = append(n.children, child)
+		n.mu.Unlock()
+	}
+
+	n.Invalidate()
+}
+
+// has_ancestor is a helper method that checks whether a node is the node itself or
+// one of its ancestors.
+//
+// Parameters:
+//   - node: The node to look for.
+//
+// Returns:
+//   - bool: True if the node was found, false otherwise.
+func (n *Node) has_ancestor(node *Node) bool {
+	for n != nil {
+		if n == node {
+			return true
+		}
+
+		n = n.Parent()
+	}
+
+	return false
+}
+
+// Remove detaches the given child from the node.
+//
+// Parameters:
+//   - child: The child to remove.
+//
+// Returns:
+//   - bool: True if the child was removed, false otherwise.
+func (n *Node) Remove(child *Node) bool {
+	if n == nil || child == nil {
+		return false
+	}
+
+	n.mu.Lock()
+
+	idx := slices.Index(n.children, child)
+	if idx == -1 {
+		n.mu.Unlock()
+
+		return false
+	}
+
+	n.children = slices.Delete(n.children, idx, idx+1)
+
+	n.mu.Unlock()
+
+	child.mu.Lock()
+	child.parent = nil
+	child.mu.Unlock()
+
+	n.Invalidate()
+
+	return true
+}
+
+// Parent returns the parent of the node.
+//
+// Returns:
+//   - *Node: The parent of the node. Nil if the node is a root.
+func (n *Node) Parent() *Node {
+	if n == nil {
+		return nil
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.parent
+}
+
+// Children returns a copy of the children of the node.
+//
+// Returns:
+//   - []*Node: The children of the node.
+func (n *Node) Children() []*Node {
+	if n == nil {
+		return nil
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return slices.Clone(n.children)
+}
+
+// Element returns the element of the node.
+//
+// Returns:
+//   - Drawer: The element of the node. Nil if the node has no element.
+func (n *Node) Element() Drawer {
+	if n == nil {
+		return nil
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.elem
+}
+
+// SetElement changes the element of the node and invalidates it. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - elem: The new element. Can be nil.
+func (n *Node) SetElement(elem Drawer) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	n.elem = elem
+	n.mu.Unlock()
+
+	n.Invalidate()
+}
+
+// SetLayout changes the layout of the children of the node and invalidates it. Does
+// nothing with a nil receiver.
+//
+// Parameters:
+//   - layout: The new layout. If nil, every child is given the whole area of the node.
+func (n *Node) SetLayout(layout Layout) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	n.layout = layout
+	n.mu.Unlock()
+
+	n.Invalidate()
+}
+
+// SetSize sets the preferred size of the node. Layouts use it as a hint when
+// assigning areas; a value of 0 (or less) means no preference. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - width: The preferred width.
+//   - height: The preferred height.
+func (n *Node) SetSize(width, height int) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+	n.width = max(width, 0)
+	n.height = max(height, 0)
+	n.mu.Unlock()
+
+	n.Invalidate()
+}
+
+// Size returns the preferred size of the node.
+//
+// Returns:
+//   - int: The preferred width. 0 means no preference.
+//   - int: The preferred height. 0 means no preference.
+func (n *Node) Size() (int, int) {
+	if n == nil {
+		return 0, 0
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.width, n.height
+}
+
+// Rect returns the area that was last assigned to the node.
+//
+// Returns:
+//   - Rect: The area of the node.
+func (n *Node) Rect() Rect {
+	if n == nil {
+		return Rect{}
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.rect
+}
+
+// Invalidate marks the node and all of its ancestors as needing to be redrawn and
+// notifies the owner of the tree (e.g., the screen). Does nothing with a nil receiver.
+func (n *Node) Invalidate() {
+	for n != nil {
+		n.mu.Lock()
+
+		n.dirty = true
+		parent := n.parent
+		fn := n.on_invalidate
+
+		n.mu.Unlock()
+
+		if parent == nil && fn != nil {
+			fn()
+		}
+
+		n = parent
+	}
+}
+
+// IsDirty checks whether the node needs to be redrawn.
+//
+// Returns:
+//   - bool: True if the node needs to be redrawn, false otherwise.
+func (n *Node) IsDirty() bool {
+	if n == nil {
+		return false
+	}
+
+	n.mu.RLock()
+	defer n.mu.RUnlock()
+
+	return n.dirty
+}
+
+// set_on_invalidate sets the function called whenever a node of the tree is
+// invalidated.
+//
+// Parameters:
+//   - fn: The function to call. Can be nil.
+func (n *Node) set_on_invalidate(fn func()) {
+	n.mu.Lock()
+	n.on_invalidate = fn
+	n.mu.Unlock()
+}
+
+// Arrange assigns the given area to the node and, recursively, an area to each of
+// its children according to the layout of the node. Elements that implement
+// Arranger are told their area. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - area: The area assigned to the node.
+func (n *Node) Arrange(area Rect) {
+	if n == nil {
+		return
+	}
+
+	n.mu.Lock()
+
+	if n.rect != area {
+		n.rect = area
+		n.dirty = true
+	}
+
+	layout := n.layout
+	elem := n.elem
+	children := slices.Clone(n.children)
+
+	n.mu.Unlock()
+
+	if arranger, ok := elem.(Arranger); ok {
+		arranger.SetArea(area)
+	}
+
+	if len(children) == 0 {
+		return
+	}
+
+	var rects []Rect
+
+	if layout == nil {
+		rects = make([]Rect, 0, len(children))
+
+		for range children {
+			rects = append(rects, area)
+		}
+	} else {
+		rects = layout.Arrange(area, children)
+		gda.AssertF(len(rects) == len(children), "layout returned %d areas for %d children", len(rects), len(children))
+	}
+
+	for i, child := range children {
+		child.Arrange(rects[i])
+	}
+}
+
+// DrawTo draws the node and, then, its children on the given table. The element of
+// each node is clipped to the area of the node and nil cells are treated as
+// transparent. Once drawn, the nodes are no longer dirty.
+//
+// Parameters:
+//   - table: The table to draw on.
+//
+// Returns:
+//   - error: An error if an element could not be drawn.
+func (n *Node) DrawTo(table *dtb.Table) error {
+	if n == nil || table == nil {
+		return nil
+	}
+
+	n.mu.Lock()
+
+	n.dirty = false
+	elem := n.elem
+	rect := n.rect
+	children := slices.Clone(n.children)
+
+	n.mu.Unlock()
+
+	if elem != nil && !rect.IsEmpty() {
+		err := dtb.DrawClipped(table, elem, rect.X, rect.Y, rect.Width, rect.Height)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, child := range children {
+		err := child.DrawTo(table)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// Walk returns an iterator over the node and its descendants, in pre-order (i.e.,
+// in drawing order).
+//
+// Returns:
+//   - iter.Seq[*Node]: The iterator. Never returns nil.
+func (n *Node) Walk() iter.Seq[*Node] {
+	return func(yield func(*Node) bool) {
+		n.walk(yield)
+	}
+}
+
+// walk is a helper method of Walk.
+//
+// Parameters:
+//   - yield: The function to call on each node.
+//
+// Returns:
+//   - bool: False if the iteration was stopped.
+func (n *Node) walk(yield func(*Node) bool) bool {
+	if n == nil {
+		return true
+	}
+
+	if !yield(n) {
+		return false
+	}
+
+	for _, child := range n.Children() {
+		if !child.walk(yield) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// HandlerAt returns the top-most node, in drawing order, whose element is a Handler
+// and whose area contains the given coordinates.
+//
+// Parameters:
+//   - x: The x coordinate.
+//   - y: The y coordinate.
+//
+// Returns:
+//   - *Node: The node. Nil if there is none.
+func (n *Node) HandlerAt(x, y int) *Node {
+	var found *Node
+
+	for node := range n.Walk() {
+		_, ok := node.Element().(Handler)
+		if ok && node.Rect().Contains(x, y) {
+			found = node
+		}
+	}
+
+	return found
+}
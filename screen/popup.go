@@ -0,0 +1,299 @@
+package screen
+
+import (
+	"slices"
+	"strings"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+// Popup is an element drawn above the screen that captures the input while it is
+// open.
+type Popup interface {
+	Drawer
+	Handler
+
+	// Area returns the area in which the popup is drawn.
+	//
+	// Parameters:
+	//   - width: The width of the screen.
+	//   - height: The height of the screen.
+	//
+	// Returns:
+	//   - Rect: The area of the popup.
+	Area(width, height int) Rect
+}
+
+// popup_entry is an entry of the popup stack.
+type popup_entry struct {
+	// popup is the popup.
+	popup Popup
+
+	// dim is true if what is underneath the popup is dimmed.
+	dim bool
+}
+
+// OpenPopup pushes a popup on top of the screen and requests a redraw. While the
+// popup is the top-most one, it receives every key and mouse event. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - popup: The popup to open. Nil popups are ignored.
+//   - dim: Whether what is underneath the popup is dimmed.
+func (s *Screen) OpenPopup(popup Popup, dim bool) {
+	if s == nil || popup == nil {
+		return
+	}
+
+	if dialog, ok := popup.(*Dialog); ok {
+		// The dialog closes itself through the screen it is open on.
+		dialog.mu.Lock()
+		dialog.screen = s
+		dialog.mu.Unlock()
+	}
+
+	s.mu.Lock()
+	s.popups = append(s.popups, &popup_entry{
+		popup: popup,
+		dim:   dim,
+	})
+	s.mu.Unlock()
+
+	s.request_redraw()
+}
+
+// ClosePopup removes a popup (and every popup opened after it) from the screen and
+// requests a redraw, which restores what was underneath.
+//
+// Parameters:
+//   - popup: The popup to close.
+//
+// Returns:
+//   - bool: True if the popup was open, false otherwise.
+func (s *Screen) ClosePopup(popup Popup) bool {
+	if s == nil || popup == nil {
+		return false
+	}
+
+	s.mu.Lock()
+
+	idx := slices.IndexFunc(s.popups, func(e *popup_entry) bool {
+		return e.popup == popup
+	})
+
+	if idx != -1 {
+		s.popups = s.popups[:idx]
+	}
+
+	s.mu.Unlock()
+
+	if idx == -1 {
+		return false
+	}
+
+	s.request_redraw()
+
+	return true
+}
+
+// TopPopup returns the top-most popup.
+//
+// Returns:
+//   - Popup: The top-most popup. Nil if no popup is open.
+func (s *Screen) TopPopup() Popup {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.popups) == 0 {
+		return nil
+	}
+
+	return s.popups[len(s.popups)-1].popup
+}
+
+// dispatch_popup is a helper function that sends the event to the top-most popup,
+// if any.
+//
+// Parameters:
+//   - ev: The event to send.
+//
+// Returns:
+//   - bool: True if a popup is open (and, thus, captured the event), false otherwise.
+func (s *Screen) dispatch_popup(ev tcell.Event) bool {
+	top := s.TopPopup()
+	if top == nil {
+		return false
+	}
+
+	if top.HandleEvent(ev) {
+		s.request_redraw()
+	}
+
+	return true
+}
+
+// compose is a helper function that copies the draw buffer to the frame and draws,
+// in order, the status bar, the popups and the toasts on top of it.
+//
+// Returns:
+//   - error: An error if a popup could not be drawn.
+func (s *Screen) compose() error {
+	s.mu.Lock()
+	popups := slices.Clone(s.popups)
+	status := s.status
+	bg_style := s.background()
+	s.mu.Unlock()
+
+	s.dt.mu.Lock()
+	defer s.dt.mu.Unlock()
+
+	s.dt.frame.Cleanup()
+
+	x, y := 0, 0
+	s.dt.frame.WriteTableAt(s.dt.buffer, &x, &y)
+
+	width, height := s.dt.frame.Width(), s.dt.frame.Height()
+
+	bottom := height
+
+	if status != nil && height > 0 {
+		bottom--
+
+		x, y = 0, bottom
+
+		err := status.Draw(s.dt.frame, &x, &y)
+		if err != nil {
+			return err
+		}
+	}
+
+	for _, entry := range popups {
+		if entry.dim {
+			dim_table(s.dt.frame, bg_style)
+		}
+
+		area := entry.popup.Area(width, height)
+
+		err := dtb.DrawClipped(s.dt.frame, entry.popup, area.X, area.Y, area.Width, area.Height)
+		if err != nil {
+			return err
+		}
+	}
+
+	s.draw_toasts(s.dt.frame, bottom)
+
+	return nil
+}
+
+// dim_table is a helper function that dims every cell of the table.
+//
+// Parameters:
+//   - table: The table to dim.
+//   - bg_style: The style of empty cells.
+func dim_table(table *dtb.Table, bg_style tcell.Style) {
+	for y := 0; y < table.Height(); y++ {
+		for x := 0; x < table.Width(); x++ {
+			cell := table.CellAt(x, y)
+
+			if cell == nil {
+				table.WriteAt(x, y, dtb.NewCell(' ', bg_style.Dim(true)))
+			} else {
+				table.WriteAt(x, y, dtb.NewCell(cell.Char, cell.Style.Dim(true)))
+			}
+		}
+	}
+}
+
+// fill_table is a helper function that fills every cell of the table.
+//
+// Parameters:
+//   - table: The table to fill.
+//   - style: The style of the cells.
+func fill_table(table *dtb.Table, style tcell.Style) {
+	for y := 0; y < table.Height(); y++ {
+		for x := 0; x < table.Width(); x++ {
+			table.WriteAt(x, y, dtb.NewCell(' ', style))
+		}
+	}
+}
+
+// draw_border is a helper function that draws a border around the table with an
+// optional title on the top edge.
+//
+// Parameters:
+//   - table: The table to draw on.
+//   - style: The style of the border.
+//   - title: The title. Empty for no title.
+func draw_border(table *dtb.Table, style tcell.Style, title string) {
+	width, height := table.Width(), table.Height()
+	if width < 2 || height < 2 {
+		return
+	}
+
+	for x := 1; x < width-1; x++ {
+		table.WriteAt(x, 0, dtb.NewCell('─', style))
+		table.WriteAt(x, height-1, dtb.NewCell('─', style))
+	}
+
+	for y := 1; y < height-1; y++ {
+		table.WriteAt(0, y, dtb.NewCell('│', style))
+		table.WriteAt(width-1, y, dtb.NewCell('│', style))
+	}
+
+	table.WriteAt(0, 0, dtb.NewCell('┌', style))
+	table.WriteAt(width-1, 0, dtb.NewCell('┐', style))
+	table.WriteAt(0, height-1, dtb.NewCell('└', style))
+	table.WriteAt(width-1, height-1, dtb.NewCell('┘', style))
+
+	if title == "" || width < 5 {
+		return
+	}
+
+	x, y := 2, 0
+
+	table.WriteLineAt(&x, &y, " "+truncate(title, width-6)+" ", style, true)
+}
+
+// truncate is a helper function that truncates the text to the given number of
+// runes, replacing the last one with an ellipsis if needed.
+//
+// Parameters:
+//   - text: The text to truncate.
+//   - width: The maximum number of runes.
+//
+// Returns:
+//   - string: The truncated text.
+func truncate(text string, width int) string {
+	chars := []rune(text)
+
+	if len(chars) <= width {
+		return text
+	} else if width <= 0 {
+		return ""
+	}
+
+	return string(chars[:width-1]) + "…"
+}
+
+// pad_right is a helper function that pads the text with spaces up to the given
+// number of runes.
+//
+// Parameters:
+//   - text: The text to pad.
+//   - width: The number of runes.
+//
+// Returns:
+//   - string: The padded text.
+func pad_right(text string, width int) string {
+	n := len([]rune(text))
+	if n >= width {
+		return text
+	}
+
+	return text + strings.Repeat(" ", width-n)
+}
@@ -2,8 +2,17 @@ package screen
 
 import (
 	"context"
-
+	"fmt"
+	"io"
+	"os"
+	"sync"
+	"sync/atomic"
+
+	"github.com/PlayerR9/display/anim"
+	"github.com/PlayerR9/display/ansi"
+	"github.com/PlayerR9/display/colors"
 	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
 	gcers "github.com/PlayerR9/go-commons/errors"
 	gda "github.com/PlayerR9/go-debug/assert"
 	"github.com/gdamore/tcell"
@@ -19,6 +28,10 @@ type Screen struct {
 	// screen is the tcell screen.
 	screen tcell.Screen
 
+	// new_screen is the function that creates the tcell screen. It is called
+	// every time the screen is (re-)initialized.
+	new_screen func() (tcell.Screen, error)
+
 	// event_ch is the event channel.
 	event_ch chan tcell.Event
 
@@ -27,9 +40,93 @@ type Screen struct {
 
 	// dt is the draw table.
 	dt *Display
+
+	// stop_ch is closed to stop the event listener.
+	stop_ch chan struct{}
+
+	// listener_wg waits for the event listener to stop.
+	listener_wg sync.WaitGroup
+
+	// quit_ch is closed to stop the event loop. Nil if the event loop is not
+	// running.
+	quit_ch chan struct{}
+
+	// done_ch is closed once the event loop has stopped.
+	done_ch chan struct{}
+
+	// suspended is true if the screen is suspended.
+	suspended bool
+
+	// closed is true once the screen is closed.
+	closed bool
+
+	// workspaces are the component trees hosted by the screen. Never empty.
+	workspaces []*Workspace
+
+	// active is the index of the workspace that is shown.
+	active int
+
+	// on_workspace is called whenever another workspace is shown. Can be nil.
+	on_workspace func(ws *Workspace)
+
+	// workspace_keys is true if the keybindings that switch workspaces are enabled.
+	workspace_keys bool
+
+	// redraw_pending is true if a redraw event was posted but not yet processed.
+	redraw_pending atomic.Bool
+
+	// err_ch is the channel of errors that occurred while drawing.
+	err_ch chan error
+
+	// animator drives the animations and timers of the screen.
+	animator *anim.Animator
+
+	// tick_pending is true if a tick event was posted but not yet processed.
+	tick_pending atomic.Bool
+
+	// popups is the stack of popups drawn above the screen. The last one is on top.
+	popups []*popup_entry
+
+	// status is the status bar pinned to the last row. Can be nil.
+	status *StatusBar
+
+	// toasts are the visible toasts, from the oldest to the newest.
+	toasts []*toast
+
+	// toast_queue are the toasts waiting for a visible one to expire.
+	toast_queue []*toast
+
+	// toast_styles are the styles of the toasts of each severity that override the
+	// ones of the current theme.
+	toast_styles map[Severity]tcell.Style
+
+	// bg_role is the role of the background style in the current theme. If empty,
+	// bg_style is used instead.
+	bg_role theme.Role
+
+	// stop_theme stops listening for changes of the current theme.
+	stop_theme func()
+
+	// depth is the colour depth every style is degraded to when flushed.
+	depth colors.Depth
+
+	// depth_forced is true if the depth was set with SetColorDepth and must not be
+	// detected.
+	depth_forced bool
+
+	// cursor_out is where the escape sequences that change the shape of the cursor
+	// are written. Nil if the output is not a terminal.
+	cursor_out io.Writer
+
+	// cursor_shape is the current shape of the cursor.
+	cursor_shape CursorShape
+
+	// mu is the mutex that guards the tcell screen and the channels.
+	mu sync.Mutex
 }
 
-// NewScreen creates a new screen.
+// NewScreen creates a new screen. If the standard output is not a terminal, the
+// screen falls back to line mode (see ansi.LineScreen).
 //
 // Parameters:
 //   - bg_style: The background style of the screen.
@@ -38,7 +135,7 @@ type Screen struct {
 //   - *Screen: The new screen.
 //   - error: The error if any.
 func NewScreen(bg_style tcell.Style) (*Screen, error) {
-	screen, err := tcell.NewScreen()
+	screen, err := ansi.NewScreen()
 	if err != nil {
 		return nil, err
 	}
@@ -54,46 +151,80 @@ func NewScreen(bg_style tcell.Style) (*Screen, error) {
 		frame:  frame,
 	}
 
-	return &Screen{
-		bg_style: bg_style,
-		screen:   screen,
-		event_ch: make(chan tcell.Event, 1),
-		key_ch:   make(chan *tcell.EventKey),
-		dt:       dt,
-	}, nil
+	s := &Screen{
+		bg_style:   bg_style,
+		depth:      colors.DepthTrueColor,
+		screen:     screen,
+		new_screen: ansi.NewScreen,
+		event_ch:   make(chan tcell.Event, 1),
+		key_ch:     make(chan *tcell.EventKey),
+		dt:         dt,
+		err_ch:     make(chan error, 1),
+
+		workspace_keys: true,
+	}
+
+	if _, ok := screen.(*ansi.LineScreen); !ok && ansi.IsTerminal(os.Stdout) {
+		s.cursor_out = os.Stdout
+	}
+
+	s.workspaces = []*Workspace{
+		{
+			name:   DefaultWorkspace,
+			screen: s,
+		},
+	}
+
+	s.SetAnimator(anim.NewAnimator(anim.RealClock{}, anim.DefaultFrameRate))
+
+	s.stop_theme = theme.Default.OnChange(func(*theme.Theme) {
+		s.request_redraw()
+	})
+
+	return s, nil
 }
 
 // event_listener is a helper function that listens for events.
-func (s *Screen) event_listener() {
+//
+// Parameters:
+//   - screen: The tcell screen to poll.
+//   - stop_ch: The channel that, once closed, stops the listener.
+func (s *Screen) event_listener(screen tcell.Screen, stop_ch chan struct{}) {
+	defer s.listener_wg.Done()
+
 	for {
-		ev := s.screen.PollEvent()
+		ev := screen.PollEvent()
 		if ev == nil {
 			break
 		}
 
-		s.event_ch <- ev
+		select {
+		case s.event_ch <- ev:
+		case <-stop_ch:
+			return
+		}
 	}
 }
 
-// Start starts the screen.
+// init_screen is a helper function that initializes the tcell screen, resizes the
+// draw table to the size of the terminal and starts listening for events.
 //
 // Returns:
 //   - error: The error if any.
-func (s *Screen) Start() (context.Context, error) {
-	k := DisplayKey("display")
-
-	ctx := context.WithValue(context.Background(), k, dt)
-
-	if s == nil {
-		return gcers.NilReceiver
-	}
-
+//
+// Assertions:
+//   - s.mu is locked.
+func (s *Screen) init_screen() error {
 	err := s.screen.Init()
 	if err != nil {
 		return err
 	}
 
-	s.screen.SetStyle(s.bg_style)
+	if !s.depth_forced {
+		s.depth = colors.Detect(s.screen)
+	}
+
+	s.screen.SetStyle(s.background())
 
 	s.screen.EnableMouse()
 
@@ -106,20 +237,87 @@ func (s *Screen) Start() (context.Context, error) {
 		return err
 	}
 
-	go s.event_listener()
+	s.stop_ch = make(chan struct{})
 
-	go s.run()
+	s.listener_wg.Add(1)
+
+	go s.event_listener(s.screen, s.stop_ch)
 
 	return nil
 }
 
+// Start starts the screen.
+//
+// Returns:
+//   - context.Context: The context that holds the display of the screen.
+//   - error: The error if any.
+func (s *Screen) Start() (context.Context, error) {
+	if s == nil {
+		return nil, gcers.NilReceiver
+	}
+
+	k := DisplayKey("display")
+
+	ctx := context.WithValue(context.Background(), k, s.dt)
+
+	s.mu.Lock()
+
+	err := s.init_screen()
+	if err != nil {
+		s.mu.Unlock()
+
+		return nil, err
+	}
+
+	s.quit_ch = make(chan struct{})
+	s.done_ch = make(chan struct{})
+
+	go s.run(s.event_ch, s.quit_ch, s.done_ch)
+
+	s.mu.Unlock()
+
+	s.request_redraw()
+
+	return ctx, nil
+}
+
 // Close closes the screen.
 func (s *Screen) Close() {
 	if s == nil {
 		return
 	}
 
-	s.screen.Fini()
+	s.mu.Lock()
+
+	if !s.suspended {
+		s.set_cursor_shape(CursorDefault)
+		s.screen.Fini()
+		s.suspended = true
+	}
+
+	s.closed = true
+
+	if s.stop_ch != nil {
+		close(s.stop_ch)
+		s.stop_ch = nil
+	}
+
+	quit_ch, done_ch := s.quit_ch, s.done_ch
+	s.quit_ch = nil
+
+	s.mu.Unlock()
+
+	s.listener_wg.Wait()
+
+	if quit_ch != nil {
+		// The event loop may be sending a key or an error, so it must stop before
+		// the channels are closed.
+		close(quit_ch)
+		<-done_ch
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
 	if s.event_ch != nil {
 		close(s.event_ch)
@@ -130,15 +328,61 @@ func (s *Screen) Close() {
 		close(s.key_ch)
 		s.key_ch = nil
 	}
+
+	if s.err_ch != nil {
+		close(s.err_ch)
+		s.err_ch = nil
+	}
+
+	if s.stop_theme != nil {
+		s.stop_theme()
+		s.stop_theme = nil
+	}
 }
 
 // run runs the screen.
-func (s *Screen) run() {
-	for ev := range s.event_ch {
+//
+// Parameters:
+//   - event_ch: The channel of events to process.
+//   - quit_ch: The channel that, once closed, stops the event loop.
+//   - done_ch: The channel closed once the event loop has stopped.
+func (s *Screen) run(event_ch chan tcell.Event, quit_ch, done_ch chan struct{}) {
+	defer close(done_ch)
+
+	for {
+		var ev tcell.Event
+
+		select {
+		case ev = <-event_ch:
+		case <-quit_ch:
+			return
+		}
+
 		switch ev := ev.(type) {
 		case *tcell.EventKey:
+			if s.dispatch_popup(ev) || s.ActiveWorkspace().dispatch(ev) || s.workspace_key(ev) {
+				continue
+			}
+
+			// Ctrl+Z only suspends the process if the focused handler declined it;
+			// for instance, an editor uses it to undo.
+			if ev.Key() == tcell.KeyCtrlZ && JobControl {
+				err := s.stop()
+				if err != nil {
+					s.send_err(fmt.Errorf("error suspending screen: %w", err))
+				}
+
+				continue
+			}
+
+			s.mu.Lock()
+			key_ch := s.key_ch
+			s.mu.Unlock()
+
 			select {
-			case s.key_ch <- ev:
+			case key_ch <- ev:
+			case <-quit_ch:
+				return
 			}
 		case *tcell.EventResize:
 			// s.screen.Sync()
@@ -148,7 +392,23 @@ func (s *Screen) run() {
 			err := s.dt.resize(width, height)
 			gda.AssertErr(err, "s.dt.ResizeWidth(%d, %d)", width, height)
 
-			s.show_display()
+			s.render()
+		case *EventRedraw:
+			s.render()
+		case *EventTick:
+			s.tick_pending.Store(false)
+
+			s.mu.Lock()
+			animator := s.animator
+			s.mu.Unlock()
+
+			if animator.Step() {
+				s.render()
+			}
+		case *tcell.EventMouse:
+			if !s.dispatch_popup(ev) {
+				s.ActiveWorkspace().dispatch(ev)
+			}
 			// case *tcell.EventMouse:
 			// 	button := ev.Buttons()
 
@@ -165,24 +425,244 @@ func (s *Screen) run() {
 	}
 }
 
+// render is a helper function that draws the component tree, if any, on the draw
+// table and shows the display. Errors are sent to the error channel.
+func (s *Screen) render() {
+	s.redraw_pending.Store(false)
+
+	s.mu.Lock()
+	ws := s.workspaces[s.active]
+	status := s.status
+	s.mu.Unlock()
+
+	s.dt.mu.Lock()
+
+	height := s.dt.buffer.Height()
+	if status != nil {
+		height--
+	}
+
+	root := ws.arrange(s.dt.buffer.Width(), max(height, 0))
+
+	s.dt.mu.Unlock()
+
+	if root != nil {
+		s.dt.mu.Lock()
+
+		s.dt.buffer.Cleanup()
+
+		err := root.DrawTo(s.dt.buffer)
+
+		s.dt.mu.Unlock()
+
+		if err != nil {
+			s.send_err(fmt.Errorf("error drawing component tree: %w", err))
+		}
+	}
+
+	err := s.compose()
+	if err != nil {
+		s.send_err(fmt.Errorf("error drawing popups: %w", err))
+	}
+
+	s.show_display(s.cursor_target())
+}
+
+// send_err is a helper function that sends an error to the error channel without
+// blocking. If an error is already pending, the new one is dropped.
+//
+// Parameters:
+//   - err: The error to send.
+func (s *Screen) send_err(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	select {
+	case s.err_ch <- err:
+	default:
+	}
+}
+
+// request_redraw is a helper function that posts a redraw event to the event loop.
+// Multiple requests made before the event is processed result in a single redraw.
+func (s *Screen) request_redraw() {
+	if !s.redraw_pending.CompareAndSwap(false, true) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.suspended {
+		// The tree is redrawn when the screen is resumed.
+		return
+	}
+
+	err := s.screen.PostEvent(NewEventRedraw())
+	if err != nil {
+		s.redraw_pending.Store(false)
+	}
+}
+
+// request_tick is a helper function that posts a tick event to the event loop.
+// Multiple requests made before the event is processed result in a single tick.
+func (s *Screen) request_tick() {
+	if !s.tick_pending.CompareAndSwap(false, true) {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.suspended {
+		s.tick_pending.Store(false)
+
+		return
+	}
+
+	err := s.screen.PostEvent(NewEventTick())
+	if err != nil {
+		s.tick_pending.Store(false)
+	}
+}
+
+// Animator returns the animator that drives the animations and timers of the
+// screen. Animations added to it are stepped from the event loop and the screen is
+// redrawn after each step.
+//
+// Returns:
+//   - *anim.Animator: The animator. Nil only if the receiver is nil.
+func (s *Screen) Animator() *anim.Animator {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.animator
+}
+
+// SetAnimator replaces the animator of the screen. Useful to drive the screen with a
+// virtual clock. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - animator: The new animator. Nil animators are ignored.
+func (s *Screen) SetAnimator(animator *anim.Animator) {
+	if s == nil || animator == nil {
+		return
+	}
+
+	animator.SetWake(s.request_tick)
+
+	s.mu.Lock()
+	old := s.animator
+	s.animator = animator
+	s.mu.Unlock()
+
+	if old != nil && old != animator {
+		old.SetWake(nil)
+	}
+}
+
+// SetRoot sets the root of the component tree of the active workspace. See
+// Workspace.SetRoot.
+//
+// Parameters:
+//   - root: The root of the component tree. If nil, the tree is removed.
+func (s *Screen) SetRoot(root *Node) {
+	if s == nil {
+		return
+	}
+
+	s.ActiveWorkspace().SetRoot(root)
+}
+
+// Root returns the root of the component tree of the active workspace.
+//
+// Returns:
+//   - *Node: The root of the component tree. Nil if no tree was set.
+func (s *Screen) Root() *Node {
+	if s == nil {
+		return nil
+	}
+
+	return s.ActiveWorkspace().Root()
+}
+
+// Invalidate requests the screen to be redrawn. Does nothing with a nil receiver.
+func (s *Screen) Invalidate() {
+	if s == nil {
+		return
+	}
+
+	s.request_redraw()
+}
+
+// ReceiveErr receives an error that occurred while drawing the component tree.
+//
+// Returns:
+//   - error: The error.
+//   - bool: True if the error was received, false if the screen was closed.
+func (s *Screen) ReceiveErr() (error, bool) {
+	if s == nil {
+		return nil, false
+	}
+
+	s.mu.Lock()
+	err_ch := s.err_ch
+	s.mu.Unlock()
+
+	if err_ch == nil {
+		return nil, false
+	}
+
+	err, ok := <-err_ch
+	if !ok {
+		return nil, false
+	}
+
+	return err, true
+}
+
 // show_display is a helper function that shows the display.
-func (s *Screen) show_display() {
+//
+// Parameters:
+//   - cursor: The cursor of the frame.
+func (s *Screen) show_display(cursor cursor_state) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.suspended {
+		return
+	}
+
+	bg_style := colors.Degrade(s.background(), s.depth)
+
+	s.screen.SetStyle(bg_style)
 	s.screen.Clear()
 
+	s.dt.mu.RLock()
+	defer s.dt.mu.RUnlock()
+
 	y := 0
 
-	for row := range s.dt.Row() {
+	for row := range s.dt.frame.Row() {
 		for x := 0; x < len(row); x++ {
 			cell := row[x]
 
 			if cell == nil {
-				s.screen.SetContent(x, y, ' ', nil, s.bg_style)
+				s.screen.SetContent(x, y, ' ', nil, bg_style)
 			} else {
-				s.screen.SetContent(x, y, cell.Char, nil, cell.Style)
+				s.screen.SetContent(x, y, cell.Char, nil, colors.Degrade(cell.Style, s.depth))
 			}
 		}
+
+		y++
 	}
 
+	s.place_cursor(cursor)
+
 	s.screen.Show()
 }
 
@@ -232,13 +712,15 @@ func (s *Screen) Show(elem Drawer, x, y int) (int, int, error) {
 		return x, y, nil
 	}
 
-	s.screen.Clear()
-	defer s.screen.Show()
+	s.dt.buffer.Cleanup()
+	defer s.render()
 
 	var err error
 
 	if elem != nil {
-		err = elem.Draw(s, &x, &y)
+		s.dt.mu.Lock()
+		err = elem.Draw(s.dt.buffer, &x, &y)
+		s.dt.mu.Unlock()
 	}
 
 	return x, y, err
@@ -250,8 +732,9 @@ func (s *Screen) Clear() {
 		return
 	}
 
-	s.screen.Clear()
-	s.screen.Show()
+	s.dt.buffer.Cleanup()
+
+	s.render()
 }
 
 // ListenForKey listens for a key press event on the screen.
@@ -263,11 +746,19 @@ func (s *Screen) Clear() {
 //   - *tcell.EventKey: The key press event.
 //   - bool: Whether the channel is still open.
 func (s *Screen) ListenForKey() (*tcell.EventKey, bool) {
-	if s == nil || s.key_ch == nil {
+	if s == nil {
 		return nil, false
 	}
 
-	ev, ok := <-s.key_ch
+	s.mu.Lock()
+	key_ch := s.key_ch
+	s.mu.Unlock()
+
+	if key_ch == nil {
+		return nil, false
+	}
+
+	ev, ok := <-key_ch
 	if !ok {
 		return nil, false
 	}
@@ -284,7 +775,7 @@ func (s *Screen) Height() int {
 		return 0
 	}
 
-	return s.dt.Height()
+	return s.dt.buffer.Height()
 }
 
 // Width returns the width of the screen.
@@ -292,11 +783,11 @@ func (s *Screen) Height() int {
 // Returns:
 //   - int: The width of the screen.
 func (s *Screen) Width() int {
-	if s == nil {
+	if s == nil || s.dt == nil {
 		return 0
 	}
 
-	return s.dt.Width()
+	return s.dt.buffer.Width()
 }
 
 // BgStyle returns the background style.
@@ -304,9 +795,80 @@ func (s *Screen) Width() int {
 // Returns:
 //   - tcell.Style: The background style.
 func (d *Screen) BgStyle() tcell.Style {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	return d.background()
+}
+
+// background is a helper method that returns the background style.
+//
+// Returns:
+//   - tcell.Style: The background style.
+//
+// Assertions:
+//   - d.mu is locked.
+func (d *Screen) background() tcell.Style {
+	if d.bg_role != "" {
+		return d.bg_role.Style()
+	}
+
 	return d.bg_style
 }
 
+// SetBgRole makes the background style follow the given role of the current theme
+// and requests a redraw. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - role: The role. If empty, the background style given to NewScreen is used.
+func (s *Screen) SetBgRole(role theme.Role) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.bg_role = role
+	s.mu.Unlock()
+
+	s.request_redraw()
+}
+
+// ColorDepth returns the colour depth every style is degraded to before being
+// displayed.
+//
+// Returns:
+//   - colors.Depth: The colour depth. colors.DepthTrueColor if the receiver is nil.
+func (s *Screen) ColorDepth() colors.Depth {
+	if s == nil {
+		return colors.DepthTrueColor
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.depth
+}
+
+// SetColorDepth forces the colour depth instead of detecting it from the terminal
+// and the environment (see colors.Detect), and requests a redraw. This is mostly
+// useful for tests and for users that want to override the detection. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - depth: The colour depth.
+func (s *Screen) SetColorDepth(depth colors.Depth) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.depth = depth
+	s.depth_forced = true
+	s.mu.Unlock()
+
+	s.request_redraw()
+}
+
 func (s *Screen) Table() *Display {
 	return s.dt
 }
@@ -0,0 +1,717 @@
+package screen
+
+import (
+	"fmt"
+	"math"
+	"sync"
+
+	dlo "github.com/PlayerR9/display/layout"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/gdamore/tcell"
+)
+
+// PaneLimits are the minimum and maximum sizes of a pane of a split, along the
+// axis of the split.
+type PaneLimits struct {
+	// Min is the minimum size. 0 means no minimum.
+	Min int
+
+	// Max is the maximum size. 0 means no maximum.
+	Max int
+}
+
+// clamp returns the size within the limits.
+//
+// Parameters:
+//   - size: The size.
+//
+// Returns:
+//   - int: The clamped size.
+func (pl PaneLimits) clamp(size int) int {
+	if pl.Max > 0 {
+		size = min(size, pl.Max)
+	}
+
+	return max(size, pl.Min)
+}
+
+// SplitState is the serialisable arrangement of a split and of the splits nested
+// in its panes (e.g., to save and restore the pane arrangement of the user).
+type SplitState struct {
+	// Direction is the direction of the split: "horizontal" or "vertical".
+	Direction string `json:"direction"`
+
+	// Weights is the share of the space of each pane.
+	Weights []float64 `json:"weights"`
+
+	// Panes are the states of the splits nested in each pane. Nil for panes that
+	// are not splits.
+	Panes []*SplitState `json:"panes,omitempty"`
+}
+
+// Split is a container that divides its area between its panes along an axis,
+// with a one-cell divider between two panes. Dividers can be dragged with the
+// mouse or, when the split has the focus, moved with the arrow keys along the axis
+// of the split; the arrow keys across the axis select the divider to move. Splits
+// can be nested by using a split as a pane of another.
+//
+// Panes keep their share of the space when the split is resized and are kept
+// within their limits.
+type Split struct {
+	// node is the node of the split. Its children are the panes.
+	node *Node
+
+	// direction is the axis along which the area is divided.
+	direction dlo.Direction
+
+	// weights is the share of the space of each pane.
+	weights []float64
+
+	// limits are the limits of each pane.
+	limits []PaneLimits
+
+	// area is the area last assigned to the split.
+	area Rect
+
+	// sizes are the sizes of the panes last assigned.
+	sizes []int
+
+	// active is the index of the selected divider.
+	active int
+
+	// dragging is the index of the divider being dragged. -1 if none.
+	dragging int
+
+	// focused is true if the split has the focus.
+	focused bool
+
+	// mu is the mutex of the split.
+	mu sync.Mutex
+}
+
+// NewSplit creates a new split whose panes share the space equally.
+//
+// Parameters:
+//   - direction: The axis along which the area is divided: dlo.Horizontal places the
+//     panes side by side and dlo.Vertical stacks them.
+//   - panes: The panes. Nil panes are ignored.
+//
+// Returns:
+//   - *Split: The new split. Never returns nil.
+func NewSplit(direction dlo.Direction, panes ...*Node) *Split {
+	s := &Split{
+		direction: direction,
+		dragging:  -1,
+	}
+
+	s.node = NewContainer(s, panes...)
+	s.node.SetElement(s)
+
+	return s
+}
+
+// Node returns the node of the split, to be inserted in a component tree.
+//
+// Returns:
+//   - *Node: The node. Never returns nil.
+func (s *Split) Node() *Node {
+	return s.node
+}
+
+// SetLimits changes the limits of a pane. Does nothing with a nil receiver or an
+// invalid index.
+//
+// Parameters:
+//   - pane: The index of the pane.
+//   - limits: The limits of the pane.
+func (s *Split) SetLimits(pane int, limits PaneLimits) {
+	if s == nil || pane < 0 {
+		return
+	}
+
+	s.mu.Lock()
+
+	for len(s.limits) <= pane {
+		s.limits = append(s.limits, PaneLimits{})
+	}
+
+	s.limits[pane] = limits
+
+	s.mu.Unlock()
+
+	s.node.Invalidate()
+}
+
+// SetWeights changes the share of the space of each pane. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - weights: The weight of each pane. Non-positive weights are treated as 1.
+func (s *Split) SetWeights(weights ...float64) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+
+	s.weights = s.weights[:0]
+
+	for _, w := range weights {
+		if w <= 0 {
+			w = 1
+		}
+
+		s.weights = append(s.weights, w)
+	}
+
+	s.mu.Unlock()
+
+	s.node.Invalidate()
+}
+
+// Sizes returns the sizes of the panes, along the axis of the split, as last drawn.
+//
+// Returns:
+//   - []int: The sizes.
+func (s *Split) Sizes() []int {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return append([]int(nil), s.sizes...)
+}
+
+// length returns the length of an area along the axis of the split.
+//
+// Parameters:
+//   - area: The area.
+//
+// Returns:
+//   - int: The length.
+func (s *Split) length(area Rect) int {
+	if s.direction == dlo.Horizontal {
+		return area.Width
+	}
+
+	return area.Height
+}
+
+// fit_weights is a helper method that makes sure there is a weight and limits for
+// each of the n panes. New panes get the average weight.
+//
+// Parameters:
+//   - n: The number of panes.
+//
+// Assertions:
+//   - s.mu is locked.
+func (s *Split) fit_weights(n int) {
+	if len(s.weights) > n {
+		s.weights = s.weights[:n]
+	}
+
+	avg := 1.0
+
+	if len(s.weights) > 0 {
+		var sum float64
+
+		for _, w := range s.weights {
+			sum += w
+		}
+
+		avg = sum / float64(len(s.weights))
+	}
+
+	for len(s.weights) < n {
+		s.weights = append(s.weights, avg)
+	}
+
+	for len(s.limits) < n {
+		s.limits = append(s.limits, PaneLimits{})
+	}
+}
+
+// distribute splits the space among the panes in proportion to their weights
+// while respecting their limits. Panes whose limit is hit are fixed and the rest
+// of the space is split again among the others. If the minimum sizes do not fit,
+// the last panes are shrunk first.
+//
+// Parameters:
+//   - total: The space to split.
+//   - weights: The weight of each pane.
+//   - limits: The limits of each pane.
+//
+// Returns:
+//   - []int: The size of each pane. Never negative.
+func distribute(total int, weights []float64, limits []PaneLimits) []int {
+	n := len(weights)
+	sizes := make([]int, n)
+	fixed := make([]bool, n)
+
+	for range n {
+		free := total
+
+		var sum float64
+
+		for i := range n {
+			if fixed[i] {
+				free -= sizes[i]
+			} else {
+				sum += weights[i]
+			}
+		}
+
+		if sum == 0 {
+			break
+		}
+
+		// Largest remainder rounding.
+		var used int
+
+		fracs := make([]float64, n)
+
+		for i := range n {
+			if fixed[i] {
+				continue
+			}
+
+			exact := float64(max(free, 0)) * weights[i] / sum
+			sizes[i] = int(math.Floor(exact))
+			fracs[i] = exact - float64(sizes[i])
+			used += sizes[i]
+		}
+
+		for rest := max(free, 0) - used; rest > 0; rest-- {
+			best := -1
+
+			for i := range n {
+				if !fixed[i] && (best == -1 || fracs[i] > fracs[best]) {
+					best = i
+				}
+			}
+
+			sizes[best]++
+			fracs[best] = -1
+		}
+
+		changed := false
+
+		for i := range n {
+			if fixed[i] {
+				continue
+			}
+
+			clamped := limits[i].clamp(sizes[i])
+			if clamped != sizes[i] {
+				sizes[i] = clamped
+				fixed[i] = true
+				changed = true
+			}
+		}
+
+		if !changed {
+			break
+		}
+	}
+
+	excess := -total
+
+	for _, size := range sizes {
+		excess += size
+	}
+
+	for i := n - 1; i >= 0 && excess > 0; i-- {
+		cut := min(sizes[i], excess)
+		sizes[i] -= cut
+		excess -= cut
+	}
+
+	return sizes
+}
+
+// Arrange implements the Layout interface.
+func (s *Split) Arrange(area Rect, children []*Node) []Rect {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(children)
+
+	s.fit_weights(n)
+	s.area = area
+	s.active = min(s.active, max(n-2, 0))
+
+	total := max(s.length(area)-max(n-1, 0), 0)
+	s.sizes = distribute(total, s.weights, s.limits[:n])
+
+	rects := make([]Rect, 0, n)
+	offset := 0
+
+	for _, size := range s.sizes {
+		rect := area
+
+		if s.direction == dlo.Horizontal {
+			rect.X += offset
+			rect.Width = size
+		} else {
+			rect.Y += offset
+			rect.Height = size
+		}
+
+		rects = append(rects, rect)
+		offset += size + 1
+	}
+
+	return rects
+}
+
+// dividers is a helper method that returns the offset of each divider from the
+// start of the area of the split.
+//
+// Returns:
+//   - []int: The offsets.
+//
+// Assertions:
+//   - s.mu is locked.
+func (s *Split) dividers() []int {
+	if len(s.sizes) < 2 {
+		return nil
+	}
+
+	offsets := make([]int, 0, len(s.sizes)-1)
+	offset := 0
+
+	for _, size := range s.sizes[:len(s.sizes)-1] {
+		offset += size
+		offsets = append(offsets, offset)
+		offset++
+	}
+
+	return offsets
+}
+
+// Draw implements the Drawer interface. It draws the dividers; the panes draw
+// themselves.
+func (s *Split) Draw(table *dtb.Table, x, y *int) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	style := theme.RoleBorder.Style()
+	focus_style := theme.RoleBorderFocused.Style()
+
+	for i, offset := range s.dividers() {
+		st := style
+		if i == s.dragging || (s.focused && i == s.active) {
+			st = focus_style
+		}
+
+		if s.direction == dlo.Horizontal {
+			for row := 0; row < table.Height(); row++ {
+				table.WriteAt(offset, row, dtb.NewCell('│', st))
+			}
+		} else {
+			for col := 0; col < table.Width(); col++ {
+				table.WriteAt(col, offset, dtb.NewCell('─', st))
+			}
+		}
+	}
+
+	return nil
+}
+
+// MoveDivider moves a divider, resizing the panes on both of its sides within
+// their limits. Does nothing with a nil receiver or an invalid index.
+//
+// Parameters:
+//   - divider: The index of the divider; the divider i is after the pane i.
+//   - delta: The number of cells to move the divider by. Positive values move it
+//     right or down.
+func (s *Split) MoveDivider(divider, delta int) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	ok := s.move_divider(divider, delta)
+	s.mu.Unlock()
+
+	if ok {
+		s.node.Invalidate()
+	}
+}
+
+// move_divider is a helper method of MoveDivider.
+//
+// Parameters:
+//   - divider: The index of the divider.
+//   - delta: The number of cells to move the divider by.
+//
+// Returns:
+//   - bool: True if the divider moved, false otherwise.
+//
+// Assertions:
+//   - s.mu is locked.
+func (s *Split) move_divider(divider, delta int) bool {
+	if divider < 0 || divider+1 >= len(s.sizes) || delta == 0 {
+		return false
+	}
+
+	before, after := s.sizes[divider], s.sizes[divider+1]
+	pair := before + after
+
+	new_before := s.limits[divider].clamp(before + delta)
+	new_before = max(min(new_before, pair), 0)
+	new_before = pair - s.limits[divider+1].clamp(pair-new_before)
+	new_before = max(min(new_before, pair), 0)
+
+	if new_before == before {
+		return false
+	}
+
+	s.sizes[divider] = new_before
+	s.sizes[divider+1] = pair - new_before
+
+	// The weights follow the sizes so that the arrangement survives resizes.
+	for i, size := range s.sizes {
+		s.weights[i] = float64(max(size, 0))
+	}
+
+	return true
+}
+
+// SetFocused implements the Focuser interface.
+func (s *Split) SetFocused(focused bool) {
+	s.mu.Lock()
+	s.focused = focused
+	s.mu.Unlock()
+}
+
+// HandleEvent implements the Handler interface.
+func (s *Split) HandleEvent(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventMouse:
+		return s.handle_mouse(ev)
+	case *tcell.EventKey:
+		return s.handle_key(ev)
+	}
+
+	return false
+}
+
+// handle_mouse is a helper method that drags the dividers.
+//
+// Parameters:
+//   - ev: The mouse event.
+//
+// Returns:
+//   - bool: True if the event was consumed, false otherwise.
+func (s *Split) handle_mouse(ev *tcell.EventMouse) bool {
+	x, y := ev.Position()
+
+	s.mu.Lock()
+
+	pos := y - s.area.Y
+	if s.direction == dlo.Horizontal {
+		pos = x - s.area.X
+	}
+
+	if ev.Buttons()&tcell.Button1 == 0 {
+		dragging := s.dragging
+		s.dragging = -1
+		s.mu.Unlock()
+
+		if dragging != -1 {
+			s.node.Invalidate()
+		}
+
+		return dragging != -1
+	}
+
+	if s.dragging == -1 {
+		for i, offset := range s.dividers() {
+			if offset == pos {
+				s.dragging = i
+				s.active = i
+			}
+		}
+
+		s.mu.Unlock()
+
+		if s.dragging == -1 {
+			return false
+		}
+
+		s.node.Invalidate()
+
+		return true
+	}
+
+	offsets := s.dividers()
+	s.move_divider(s.dragging, pos-offsets[s.dragging])
+
+	s.mu.Unlock()
+
+	s.node.Invalidate()
+
+	return true
+}
+
+// handle_key is a helper method that moves and selects the dividers.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the event was consumed, false otherwise.
+func (s *Split) handle_key(ev *tcell.EventKey) bool {
+	s.mu.Lock()
+
+	if !s.focused || len(s.sizes) < 2 {
+		s.mu.Unlock()
+
+		return false
+	}
+
+	backward, forward := tcell.KeyUp, tcell.KeyDown
+	prev, next := tcell.KeyLeft, tcell.KeyRight
+
+	if s.direction == dlo.Horizontal {
+		backward, forward = tcell.KeyLeft, tcell.KeyRight
+		prev, next = tcell.KeyUp, tcell.KeyDown
+	}
+
+	step := 1
+	if ev.Modifiers()&tcell.ModShift != 0 {
+		step = 5
+	}
+
+	switch ev.Key() {
+	case backward:
+		s.move_divider(s.active, -step)
+	case forward:
+		s.move_divider(s.active, step)
+	case prev:
+		s.active = max(s.active-1, 0)
+	case next:
+		s.active = min(s.active+1, len(s.sizes)-2)
+	default:
+		s.mu.Unlock()
+
+		return false
+	}
+
+	s.mu.Unlock()
+
+	s.node.Invalidate()
+
+	return true
+}
+
+// State returns the serialisable arrangement of the split and of the splits nested
+// in its panes.
+//
+// Returns:
+//   - *SplitState: The state. Nil only if the receiver is nil.
+func (s *Split) State() *SplitState {
+	if s == nil {
+		return nil
+	}
+
+	children := s.node.Children()
+
+	s.mu.Lock()
+
+	s.fit_weights(len(children))
+
+	state := &SplitState{
+		Direction: s.direction.String(),
+		Weights:   append([]float64(nil), s.weights...),
+	}
+
+	s.mu.Unlock()
+
+	nested := false
+	panes := make([]*SplitState, 0, len(children))
+
+	for _, child := range children {
+		sub, ok := child.Element().(*Split)
+		if ok {
+			panes = append(panes, sub.State())
+			nested = true
+		} else {
+			panes = append(panes, nil)
+		}
+	}
+
+	if nested {
+		state.Panes = panes
+	}
+
+	return state
+}
+
+// Restore applies a saved arrangement to the split and to the splits nested in its
+// panes. The panes themselves are not created: the structure of the state must
+// match the one of the split.
+//
+// Parameters:
+//   - state: The state to restore.
+//
+// Returns:
+//   - error: An error if the state does not match the split.
+//
+// Errors:
+//   - gcers.NilReceiver: If the receiver is nil.
+//   - *gcers.ErrInvalidParameter: If the state is nil.
+//   - any other error: If the structure of the state does not match the split.
+func (s *Split) Restore(state *SplitState) error {
+	if s == nil {
+		return gcers.NilReceiver
+	} else if state == nil {
+		return gcers.NewErrNilParameter("state")
+	}
+
+	var direction dlo.Direction
+
+	switch state.Direction {
+	case dlo.Horizontal.String():
+		direction = dlo.Horizontal
+	case dlo.Vertical.String():
+		direction = dlo.Vertical
+	default:
+		return fmt.Errorf("unknown split direction %q", state.Direction)
+	}
+
+	children := s.node.Children()
+
+	if len(state.Weights) != len(children) {
+		return fmt.Errorf("expected %d weights, got %d", len(children), len(state.Weights))
+	}
+
+	for i, sub := range state.Panes {
+		if i >= len(children) || sub == nil {
+			continue
+		}
+
+		split, ok := children[i].Element().(*Split)
+		if !ok {
+			return fmt.Errorf("pane %d is not a split", i)
+		}
+
+		err := split.Restore(sub)
+		if err != nil {
+			return fmt.Errorf("pane %d: %w", i, err)
+		}
+	}
+
+	s.mu.Lock()
+	s.direction = direction
+	s.mu.Unlock()
+
+	s.SetWeights(state.Weights...)
+
+	return nil
+}
@@ -0,0 +1,69 @@
+package screen
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+
+	dlo "github.com/PlayerR9/display/layout"
+	"github.com/gdamore/tcell"
+)
+
+func TestSplit(t *testing.T) {
+	inner := NewSplit(dlo.Vertical, NewNode(nil), NewNode(nil))
+	split := NewSplit(dlo.Horizontal, NewNode(nil), inner.Node(), NewNode(nil))
+
+	split.SetLimits(0, PaneLimits{Min: 5})
+	split.SetLimits(2, PaneLimits{Max: 4})
+
+	split.Node().Arrange(Rect{Width: 32, Height: 10})
+
+	if got, want := split.Sizes(), []int{13, 13, 4}; !slices.Equal(got, want) {
+		t.Fatalf("Expected sizes %v, but got %v", want, got)
+	}
+
+	// Drag the first divider, at x = 13, to x = 3: the first pane stops at its minimum.
+	split.HandleEvent(tcell.NewEventMouse(13, 0, tcell.Button1, tcell.ModNone))
+	split.HandleEvent(tcell.NewEventMouse(3, 0, tcell.Button1, tcell.ModNone))
+	split.HandleEvent(tcell.NewEventMouse(3, 0, tcell.ButtonNone, tcell.ModNone))
+
+	if got, want := split.Sizes(), []int{5, 21, 4}; !slices.Equal(got, want) {
+		t.Fatalf("Expected sizes %v, but got %v", want, got)
+	}
+
+	split.SetFocused(true)
+	split.HandleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+
+	split.Node().Arrange(Rect{Width: 64, Height: 10})
+
+	sizes := split.Sizes()
+	if sizes[2] != 4 || sizes[0] >= sizes[1] {
+		t.Fatalf("Expected the arrangement to survive a resize, but got %v", sizes)
+	}
+
+	data, err := json.Marshal(split.State())
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	var state SplitState
+
+	err = json.Unmarshal(data, &state)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	other := NewSplit(dlo.Horizontal, NewNode(nil), NewSplit(dlo.Vertical, NewNode(nil), NewNode(nil)).Node(), NewNode(nil))
+	other.SetLimits(2, PaneLimits{Max: 4})
+
+	err = other.Restore(&state)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	other.Node().Arrange(Rect{Width: 64, Height: 10})
+
+	if got := other.Sizes(); !slices.Equal(got, sizes) {
+		t.Fatalf("Expected restored sizes %v, but got %v", sizes, got)
+	}
+}
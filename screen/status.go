@@ -0,0 +1,237 @@
+package screen
+
+import (
+	"sync"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// StatusBar is a one-line bar, pinned to the last row of the screen, that shows a
+// left-aligned, a centered and a right-aligned segment. When the segments do not
+// fit, the right segment has priority over the left one, which has priority over
+// the center one.
+type StatusBar struct {
+	// left is the left-aligned segment.
+	left string
+
+	// center is the centered segment.
+	center string
+
+	// right is the right-aligned segment.
+	right string
+
+	// style is the style of the bar.
+	style tcell.Style
+
+	// role is the role of the style of the bar in the current theme. If empty,
+	// style is used instead.
+	role theme.Role
+
+	// on_change is called whenever the bar changes.
+	on_change func()
+
+	// mu is the mutex of the status bar.
+	mu sync.RWMutex
+}
+
+// NewStatusBar creates a new status bar.
+//
+// Parameters:
+//   - style: The style of the bar.
+//
+// Returns:
+//   - *StatusBar: The new status bar. Never returns nil.
+func NewStatusBar(style tcell.Style) *StatusBar {
+	return &StatusBar{
+		style: style,
+	}
+}
+
+// changed is a helper method that notifies the owner of the bar of a change.
+func (sb *StatusBar) changed() {
+	sb.mu.RLock()
+	fn := sb.on_change
+	sb.mu.RUnlock()
+
+	if fn != nil {
+		fn()
+	}
+}
+
+// SetLeft changes the left-aligned segment. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - text: The new text of the segment.
+func (sb *StatusBar) SetLeft(text string) {
+	if sb == nil {
+		return
+	}
+
+	sb.mu.Lock()
+	sb.left = text
+	sb.mu.Unlock()
+
+	sb.changed()
+}
+
+// SetCenter changes the centered segment. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - text: The new text of the segment.
+func (sb *StatusBar) SetCenter(text string) {
+	if sb == nil {
+		return
+	}
+
+	sb.mu.Lock()
+	sb.center = text
+	sb.mu.Unlock()
+
+	sb.changed()
+}
+
+// SetRight changes the right-aligned segment. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - text: The new text of the segment.
+func (sb *StatusBar) SetRight(text string) {
+	if sb == nil {
+		return
+	}
+
+	sb.mu.Lock()
+	sb.right = text
+	sb.mu.Unlock()
+
+	sb.changed()
+}
+
+// SetStyle changes the style of the bar. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - style: The new style.
+func (sb *StatusBar) SetStyle(style tcell.Style) {
+	if sb == nil {
+		return
+	}
+
+	sb.mu.Lock()
+	sb.style = style
+	sb.role = ""
+	sb.mu.Unlock()
+
+	sb.changed()
+}
+
+// SetRole makes the style of the bar follow the given role of the current theme
+// (e.g., theme.RoleStatus). Does nothing with a nil receiver.
+//
+// Parameters:
+//   - role: The role. If empty, the last style set is used.
+func (sb *StatusBar) SetRole(role theme.Role) {
+	if sb == nil {
+		return
+	}
+
+	sb.mu.Lock()
+	sb.role = role
+	sb.mu.Unlock()
+
+	sb.changed()
+}
+
+// Draw implements the Drawer interface.
+//
+// The bar is drawn on the row at the given y coordinate and spans the whole width
+// of the table.
+func (sb *StatusBar) Draw(table *dtb.Table, x_coord, y_coord *int) error {
+	sb.mu.RLock()
+	defer sb.mu.RUnlock()
+
+	width := table.Width()
+	y := *y_coord
+
+	style := sb.style
+	if sb.role != "" {
+		style = sb.role.Style()
+	}
+
+	for x := 0; x < width; x++ {
+		table.WriteAt(x, y, dtb.NewCell(' ', style))
+	}
+
+	right := truncate(sb.right, width)
+	right_len := len([]rune(right))
+
+	left := truncate(sb.left, max(width-right_len-1, 0))
+	left_len := len([]rune(left))
+
+	x := 0
+	table.WriteLineAt(&x, &y, left, style, true)
+
+	x = width - right_len
+	table.WriteLineAt(&x, &y, right, style, true)
+
+	// The center segment only uses the space between the other two.
+	free := width - left_len - right_len - 2
+	if free > 0 && sb.center != "" {
+		center := truncate(sb.center, free)
+		x = (width - len([]rune(center))) / 2
+
+		x = min(max(x, left_len+1), width-right_len-1-len([]rune(center)))
+		table.WriteLineAt(&x, &y, center, style, true)
+	}
+
+	*x_coord = width
+	*y_coord = y + 1
+
+	return nil
+}
+
+// SetStatusBar pins a status bar to the last row of the screen and requests a
+// redraw. While a status bar is set, the component tree is given every row but the
+// last one. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - sb: The status bar. If nil, the status bar is removed.
+func (s *Screen) SetStatusBar(sb *StatusBar) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	old := s.status
+	s.status = sb
+	s.mu.Unlock()
+
+	if old != nil && old != sb {
+		old.mu.Lock()
+		old.on_change = nil
+		old.mu.Unlock()
+	}
+
+	if sb != nil {
+		sb.mu.Lock()
+		sb.on_change = s.request_redraw
+		sb.mu.Unlock()
+	}
+
+	s.request_redraw()
+}
+
+// StatusBar returns the status bar of the screen.
+//
+// Returns:
+//   - *StatusBar: The status bar. Nil if no status bar is set.
+func (s *Screen) StatusBar() *StatusBar {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.status
+}
@@ -0,0 +1,62 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+func TestStatusBar(t *testing.T) {
+	s := new_test_screen(t, 20, 5)
+
+	err := s.dt.resize(20, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	s.SetRoot(NewNode(&mockDrawer{text: "tree"}))
+
+	sb := NewStatusBar(tcell.StyleDefault)
+	sb.SetLeft("ws:main")
+	sb.SetCenter("mid")
+	sb.SetRight("12:00")
+
+	s.SetStatusBar(sb)
+	s.render()
+
+	want := "ws:main mid    12:00"
+
+	if got := s.dt.frame.GetLines()[4]; got != want {
+		t.Fatalf("Expected %q, but got %q", want, got)
+	}
+
+	// The status bar follows the last row when the screen is resized.
+	err = s.dt.resize(30, 8)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	s.render()
+
+	lines := s.dt.frame.GetLines()
+
+	want = "ws:main      mid         12:00"
+
+	if lines[7] != want {
+		t.Fatalf("Expected %q, but got %q", want, lines[7])
+	}
+
+	if lines[4] == want {
+		t.Fatalf("Expected the old last row to be cleared, but got %q", lines[4])
+	}
+
+	// The right segment has priority over the others.
+	sb.SetRight("a very long right segment")
+	s.render()
+
+	want = "ws:… a very long right segment"
+
+	if got := s.dt.frame.GetLines()[7]; got != want {
+		t.Fatalf("Expected %q, but got %q", want, got)
+	}
+}
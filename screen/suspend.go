@@ -0,0 +1,237 @@
+package screen
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+
+	"github.com/PlayerR9/display/ansi"
+	gcers "github.com/PlayerR9/go-commons/errors"
+)
+
+var (
+	// ErrNotRunning occurs when suspending or resuming a screen that was not
+	// started or that was closed.
+	ErrNotRunning = errors.New("screen is not running")
+)
+
+// Suspend suspends the screen. This restores the terminal to the state it was in
+// before the screen was started and stops listening for events until Resume is
+// called. Does nothing if the screen is already suspended.
+//
+// Returns:
+//   - error: An error if the screen could not be suspended.
+//
+// Errors:
+//   - gcers.NilReceiver: If the receiver is nil.
+//   - ErrNotRunning: If the screen was not started or was closed.
+func (s *Screen) Suspend() error {
+	if s == nil {
+		return gcers.NilReceiver
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return ErrNotRunning
+	} else if s.suspended {
+		return nil
+	} else if s.stop_ch == nil {
+		return ErrNotRunning
+	}
+
+	close(s.stop_ch)
+	s.stop_ch = nil
+
+	s.set_cursor_shape(CursorDefault)
+	s.screen.Fini()
+	s.suspended = true
+
+	s.listener_wg.Wait()
+
+	return nil
+}
+
+// Resume resumes a suspended screen. The terminal screen is initialized again, the
+// draw table is resized to the (possibly new) size of the terminal and the last
+// frame is redrawn. Does nothing if the screen is not suspended.
+//
+// Returns:
+//   - error: An error if the screen could not be resumed.
+//
+// Errors:
+//   - gcers.NilReceiver: If the receiver is nil.
+//   - ErrNotRunning: If the screen was closed.
+//   - any other error: If the terminal screen could not be initialized.
+func (s *Screen) Resume() error {
+	if s == nil {
+		return gcers.NilReceiver
+	}
+
+	s.mu.Lock()
+
+	if s.closed {
+		s.mu.Unlock()
+
+		return ErrNotRunning
+	} else if !s.suspended {
+		s.mu.Unlock()
+
+		return nil
+	}
+
+	// A line screen keeps reading the input across suspends, so it is initialized
+	// again. tcell's terminal screens cannot be initialized again once finalized,
+	// so a new one is created.
+	if _, ok := s.screen.(*ansi.LineScreen); !ok {
+		screen, err := s.new_screen()
+		if err != nil {
+			s.mu.Unlock()
+
+			return err
+		}
+
+		s.screen = screen
+	}
+
+	err := s.init_screen()
+	if err != nil {
+		s.mu.Unlock()
+
+		return err
+	}
+
+	s.suspended = false
+
+	s.mu.Unlock()
+
+	s.render()
+
+	// Timers that became due while suspended are still queued; process them now.
+	s.request_tick()
+
+	return nil
+}
+
+// Exec suspends the screen, runs the given command attached to the terminal and
+// resumes the screen once the command exits. Any of the standard streams of the
+// command that are nil are set to the ones of the current process.
+//
+// Parameters:
+//   - cmd: The command to run.
+//
+// Returns:
+//   - error: An error if the command failed or if the screen could not be
+//     suspended or resumed.
+//
+// Errors:
+//   - gcers.NilReceiver: If the receiver is nil.
+//   - *gcers.ErrInvalidParameter: If cmd is nil.
+//   - any other error: If the command failed or the screen could not be resumed.
+func (s *Screen) Exec(cmd *exec.Cmd) error {
+	if s == nil {
+		return gcers.NilReceiver
+	} else if cmd == nil {
+		return gcers.NewErrNilParameter("cmd")
+	}
+
+	if cmd.Stdin == nil {
+		cmd.Stdin = os.Stdin
+	}
+
+	if cmd.Stdout == nil {
+		cmd.Stdout = os.Stdout
+	}
+
+	if cmd.Stderr == nil {
+		cmd.Stderr = os.Stderr
+	}
+
+	err := s.Suspend()
+	if err != nil {
+		return err
+	}
+
+	run_err := cmd.Run()
+
+	err = s.Resume()
+
+	return errors.Join(run_err, err)
+}
+
+// Shell runs the given command line through the user's shell (as given by the
+// SHELL environment variable) while the screen is suspended.
+//
+// Parameters:
+//   - command: The command line to run. If empty, an interactive shell is started.
+//
+// Returns:
+//   - error: An error if the command failed or if the screen could not be
+//     suspended or resumed.
+func (s *Screen) Shell(command string) error {
+	if s == nil {
+		return gcers.NilReceiver
+	}
+
+	shell := os.Getenv("SHELL")
+	if shell == "" {
+		shell = DefaultShell
+	}
+
+	var cmd *exec.Cmd
+
+	if command == "" {
+		cmd = exec.Command(shell)
+	} else {
+		cmd = exec.Command(shell, ShellFlag, command)
+	}
+
+	return s.Exec(cmd)
+}
+
+// Edit opens the given file in the user's editor (as given by the VISUAL or EDITOR
+// environment variables) while the screen is suspended.
+//
+// Parameters:
+//   - path: The path of the file to edit.
+//
+// Returns:
+//   - error: An error if the editor failed or if the screen could not be
+//     suspended or resumed.
+func (s *Screen) Edit(path string) error {
+	if s == nil {
+		return gcers.NilReceiver
+	}
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+
+	if editor == "" {
+		editor = DefaultEditor
+	}
+
+	// The editor variable may contain arguments (e.g., "code -w").
+	return s.Shell(editor + " " + shell_quote(path))
+}
+
+// stop suspends the screen, stops the process as if the terminal sent a job-control
+// stop signal and resumes the screen once the process is continued.
+//
+// Returns:
+//   - error: An error if the screen could not be suspended or resumed.
+func (s *Screen) stop() error {
+	err := s.Suspend()
+	if err != nil {
+		return err
+	}
+
+	err = stop_process()
+	if err != nil {
+		return errors.Join(err, s.Resume())
+	}
+
+	return s.Resume()
+}
@@ -0,0 +1,36 @@
+//go:build !unix
+
+package screen
+
+const (
+	// JobControl is true if Ctrl+Z suspends the process.
+	JobControl bool = false
+
+	// DefaultShell is the shell used when the SHELL environment variable is not set.
+	DefaultShell string = "cmd.exe"
+
+	// ShellFlag is the flag that makes the shell run a command line.
+	ShellFlag string = "/C"
+
+	// DefaultEditor is the editor used when neither VISUAL nor EDITOR is set.
+	DefaultEditor string = "notepad"
+)
+
+// stop_process does nothing as job control is not supported on this platform.
+//
+// Returns:
+//   - error: Always nil.
+func stop_process() error {
+	return nil
+}
+
+// shell_quote quotes a string so that the shell treats it as a single word.
+//
+// Parameters:
+//   - str: The string to quote.
+//
+// Returns:
+//   - string: The quoted string.
+func shell_quote(str string) string {
+	return `"` + str + `"`
+}
@@ -0,0 +1,257 @@
+package screen
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PlayerR9/display/ansi"
+	"github.com/PlayerR9/display/colors"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+// sim_screen is a simulation screen with a fixed size.
+type sim_screen struct {
+	tcell.SimulationScreen
+
+	width, height int
+}
+
+// Init implements the tcell.Screen interface.
+func (s *sim_screen) Init() error {
+	err := s.SimulationScreen.Init()
+	if err != nil {
+		return err
+	}
+
+	s.SetSize(s.width, s.height)
+
+	return nil
+}
+
+// new_test_screen is a helper function that creates a screen backed by tcell's
+// simulation screen.
+func new_test_screen(t *testing.T, width, height int) *Screen {
+	t.Helper()
+
+	s, err := NewScreen(tcell.StyleDefault)
+	if err != nil {
+		t.Skipf("Could not create screen: %s", err.Error())
+	}
+
+	s.new_screen = func() (tcell.Screen, error) {
+		sim := &sim_screen{
+			SimulationScreen: tcell.NewSimulationScreen("UTF-8"),
+			width:            width,
+			height:           height,
+		}
+
+		return sim, nil
+	}
+
+	s.screen, err = s.new_screen()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	return s
+}
+
+type mockDrawer struct {
+	text string
+}
+
+func (m *mockDrawer) Draw(table *dtb.Table, x, y *int) error {
+	table.WriteLineAt(x, y, m.text, tcell.StyleDefault, true)
+
+	return nil
+}
+
+func TestSuspendResume(t *testing.T) {
+	s := new_test_screen(t, 20, 5)
+
+	_, err := s.Start()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+	defer s.Close()
+
+	_, _, err = s.Show(&mockDrawer{text: "Hello"}, 0, 0)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	err = s.Suspend()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	old := s.screen
+
+	err = s.Resume()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if s.screen == old {
+		t.Fatalf("Expected a new terminal screen after resuming")
+	}
+
+	if s.Width() != 20 || s.Height() != 5 {
+		t.Fatalf("Expected size to be 20x5, but got %dx%d", s.Width(), s.Height())
+	}
+
+	c, _, _, _ := s.screen.GetContent(0, 0)
+	if c != 'H' {
+		t.Fatalf("Expected 'H' to be redrawn, but got %q", c)
+	}
+}
+
+// undo_handler is a handler that consumes Ctrl+Z, as an editor does to undo.
+type undo_handler struct {
+	mockDrawer
+
+	undos chan struct{}
+}
+
+func (u *undo_handler) HandleEvent(ev tcell.Event) bool {
+	key, ok := ev.(*tcell.EventKey)
+	if !ok || key.Key() != tcell.KeyCtrlZ {
+		return false
+	}
+
+	u.undos <- struct{}{}
+
+	return true
+}
+
+func TestCtrlZFocusedHandler(t *testing.T) {
+	s := new_test_screen(t, 20, 5)
+
+	handler := &undo_handler{
+		mockDrawer: mockDrawer{text: "editor"},
+		undos:      make(chan struct{}, 1),
+	}
+
+	node := NewNode(handler)
+
+	s.SetRoot(node)
+	s.ActiveWorkspace().SetFocus(node)
+
+	_, err := s.Start()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+	defer s.Close()
+
+	// If the screen handled Ctrl+Z as job control, the test process would be
+	// stopped instead.
+	s.screen.(*sim_screen).InjectKey(tcell.KeyCtrlZ, 0, tcell.ModCtrl)
+
+	select {
+	case <-handler.undos:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Ctrl+Z to reach the focused handler")
+	}
+}
+
+func TestCloseWhileSendingKey(t *testing.T) {
+	s := new_test_screen(t, 20, 5)
+
+	_, err := s.Start()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	// Nobody listens for keys, so the event loop blocks while sending this one.
+	s.screen.(*sim_screen).InjectKey(tcell.KeyRune, 'a', tcell.ModNone)
+	time.Sleep(50 * time.Millisecond)
+
+	closed := make(chan struct{})
+
+	go func() {
+		s.Close()
+		close(closed)
+	}()
+
+	select {
+	case <-closed:
+	case <-time.After(time.Second):
+		t.Fatalf("Expected Close to return")
+	}
+
+	if _, ok := s.ListenForKey(); ok {
+		t.Fatalf("Expected the key channel to be closed")
+	}
+}
+
+func TestSuspendNotRunning(t *testing.T) {
+	s := new_test_screen(t, 20, 5)
+
+	err := s.Suspend()
+	if !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("Expected ErrNotRunning before Start, but got %v", err)
+	}
+
+	_, err = s.Start()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	s.Close()
+
+	err = s.Resume()
+	if !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("Expected ErrNotRunning after Close, but got %v", err)
+	}
+}
+
+func TestSuspendResumeLineScreen(t *testing.T) {
+	s := new_test_screen(t, 20, 5)
+
+	ls := ansi.NewLineScreen(strings.NewReader("12\n"), io.Discard, colors.DepthMono)
+
+	s.screen = ls
+	s.new_screen = func() (tcell.Screen, error) {
+		return nil, errors.New("a second line screen would read the input again")
+	}
+
+	_, err := s.Start()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+	defer s.Close()
+
+	err = s.Suspend()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	err = s.Resume()
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if s.screen != ls {
+		t.Fatalf("Expected the line screen to be initialized again")
+	}
+
+	// The input is still delivered after resuming.
+	var got []rune
+
+	for range 3 {
+		ev, ok := s.ListenForKey()
+		if !ok {
+			t.Fatalf("Expected a key, but the screen was closed")
+		}
+
+		got = append(got, ev.Rune())
+	}
+
+	if string(got) != "12\r" {
+		t.Fatalf("Expected %q, but got %q", "12\r", string(got))
+	}
+}
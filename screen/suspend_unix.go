@@ -0,0 +1,42 @@
+//go:build unix
+
+package screen
+
+import (
+	"strings"
+	"syscall"
+)
+
+const (
+	// JobControl is true if Ctrl+Z suspends the process.
+	JobControl bool = true
+
+	// DefaultShell is the shell used when the SHELL environment variable is not set.
+	DefaultShell string = "/bin/sh"
+
+	// ShellFlag is the flag that makes the shell run a command line.
+	ShellFlag string = "-c"
+
+	// DefaultEditor is the editor used when neither VISUAL nor EDITOR is set.
+	DefaultEditor string = "vi"
+)
+
+// stop_process sends SIGTSTP to the process group of the current process. The
+// call returns once the process group receives SIGCONT (e.g., after "fg").
+//
+// Returns:
+//   - error: An error if the signal could not be sent.
+func stop_process() error {
+	return syscall.Kill(0, syscall.SIGTSTP)
+}
+
+// shell_quote quotes a string so that the shell treats it as a single word.
+//
+// Parameters:
+//   - str: The string to quote.
+//
+// Returns:
+//   - string: The quoted string.
+func shell_quote(str string) string {
+	return "'" + strings.ReplaceAll(str, "'", `'\''`) + "'"
+}
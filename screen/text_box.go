@@ -1,17 +1,106 @@
 package screen
 
 import (
+	"math"
+
+	"github.com/PlayerR9/display/theme"
 	gcch "github.com/PlayerR9/go-commons/runes"
 	"github.com/gdamore/tcell"
 )
 
+// WrapMode is how a text box breaks lines that are wider than its bounds.
+type WrapMode int
+
+const (
+	// WrapNone does not break lines; they are cut at the bounds.
+	WrapNone WrapMode = iota
+
+	// WrapChar breaks lines at the bounds, in the middle of words if needed.
+	WrapChar
+
+	// WrapWord breaks lines at the last space before the bounds. Words wider than
+	// the bounds are broken as with WrapChar.
+	WrapWord
+)
+
+// String implements the fmt.Stringer interface.
+func (wm WrapMode) String() string {
+	return [...]string{
+		"none",
+		"char",
+		"word",
+	}[wm]
+}
+
+// Alignment is the horizontal alignment of the lines of a text box.
+type Alignment int
+
+const (
+	// AlignLeft aligns the lines to the left of the bounds.
+	AlignLeft Alignment = iota
+
+	// AlignCenter centres the lines within the bounds.
+	AlignCenter
+
+	// AlignRight aligns the lines to the right of the bounds.
+	AlignRight
+)
+
+// String implements the fmt.Stringer interface.
+func (a Alignment) String() string {
+	return [...]string{
+		"left",
+		"center",
+		"right",
+	}[a]
+}
+
+const (
+	// DefaultTabWidth is the distance between two tab stops of a text box, after
+	// its explicit tab stops.
+	DefaultTabWidth int = 4
+
+	// DefaultOverflow is the indicator drawn in the last cell of a text box whose
+	// text does not fit its bounds.
+	DefaultOverflow rune = '…'
+)
+
 // TextBox is a text box.
+//
+// Without bounds, the text is drawn from the given coordinates without any limit.
+// With bounds (see SetBounds), it is wrapped, aligned and clipped to them, and it
+// can be scrolled vertically.
 type TextBox struct {
 	// chars is the text of the text box.
 	chars []rune
 
 	// style is the style of the text box.
 	style tcell.Style
+
+	// role is the role of the style of the text box in the current theme. If
+	// empty, style is used instead.
+	role theme.Role
+
+	// bounds is the area the text is drawn in. Empty for no bounds.
+	bounds Rect
+
+	// wrap is how lines wider than the bounds are broken.
+	wrap WrapMode
+
+	// align is the alignment of the lines.
+	align Alignment
+
+	// tab_stops are the explicit tab stops, in increasing order.
+	tab_stops []int
+
+	// tab_width is the distance between two tab stops after the explicit ones.
+	tab_width int
+
+	// scroll is the index of the first line drawn.
+	scroll int
+
+	// overflow is the overflow indicator. 0 for none.
+	overflow rune
 }
 
 // Draw implements the Drawable interface.
@@ -20,25 +109,68 @@ func (tb TextBox) Draw(screen Drawable, x_coord, y_coord *int) error {
 		return nil
 	}
 
-	x := *x_coord
-	y := *y_coord
+	style := tb.style
+	if tb.role != "" {
+		style = tb.role.Style()
+	}
 
-	bg_style := screen.BgStyle()
+	bounds := tb.bounds
+	if bounds.IsEmpty() {
+		bounds = Rect{
+			X:      *x_coord,
+			Y:      *y_coord,
+			Width:  math.MaxInt / 2,
+			Height: math.MaxInt / 2,
+		}
+	}
 
-	for i := 0; i < len(tb.chars); i++ {
-		switch tb.chars[i] {
-		case '\n':
-			x = 0
-			y++
-		case '\t':
-			for j := 0; j < 3; j++ {
-				screen.DrawCell(x, y, ' ', bg_style)
-				x++
-			}
-		default:
-			screen.DrawCell(x, y, tb.chars[i], tb.style)
+	lines := tb.layout(bounds.Width)
+
+	// Without bounds, the lines are aligned against the widest one.
+	align_width := bounds.Width
+
+	if tb.bounds.IsEmpty() {
+		align_width = 0
+
+		for _, line := range lines {
+			align_width = max(align_width, len(line.chars))
+		}
+	}
+
+	scroll := max(min(tb.scroll, len(lines)-1), 0)
+	visible := lines[scroll:]
+
+	hidden := false
+
+	if len(visible) > bounds.Height {
+		visible = visible[:bounds.Height]
+		hidden = true
+	}
+
+	x, y := bounds.X, bounds.Y
+
+	for i, line := range visible {
+		y = bounds.Y + i
+
+		offset := 0
+
+		switch tb.align {
+		case AlignCenter:
+			offset = (align_width - len(line.chars)) / 2
+		case AlignRight:
+			offset = align_width - len(line.chars)
+		}
+
+		x = bounds.X + max(offset, 0)
+
+		for _, c := range line.chars {
+			screen.DrawCell(x, y, c, style)
 			x++
 		}
+
+		if tb.overflow != 0 && (line.cut || (hidden && i == len(visible)-1)) {
+			screen.DrawCell(bounds.X+bounds.Width-1, y, tb.overflow, style)
+		}
 	}
 
 	*x_coord = x
@@ -47,13 +179,117 @@ func (tb TextBox) Draw(screen Drawable, x_coord, y_coord *int) error {
 	return nil
 }
 
+// text_line is a line of a text box, once laid out.
+type text_line struct {
+	// chars are the characters of the line.
+	chars []rune
+
+	// cut is true if the line was cut at the bounds.
+	cut bool
+}
+
+// layout is a helper method that splits the text into lines that fit the given
+// width: lines are wrapped or cut (see WrapRows) and tabs are expanded.
+//
+// Parameters:
+//   - width: The width of the bounds. Assumed to be positive.
+//
+// Returns:
+//   - []text_line: The lines. Never empty.
+func (tb TextBox) layout(width int) []text_line {
+	var lines []text_line
+
+	start := 0
+
+	for i := 0; i <= len(tb.chars); i++ {
+		if i < len(tb.chars) && tb.chars[i] != '\n' {
+			continue
+		}
+
+		paragraph := tb.chars[start:i]
+		rows := WrapRows(paragraph, width, tb.wrap, tb.next_tab_stop)
+
+		for j, row := range rows {
+			chars := paragraph[row.Start:row.End]
+
+			if tb.wrap == WrapWord && j < len(rows)-1 && len(chars) > 0 && chars[len(chars)-1] == ' ' {
+				// The space at the break is dropped.
+				chars = chars[:len(chars)-1]
+			}
+
+			lines = append(lines, tb.expand(chars, width))
+		}
+
+		start = i + 1
+	}
+
+	return lines
+}
+
+// expand is a helper method that expands the tabs of a row and cuts it at the
+// given width.
+//
+// Parameters:
+//   - chars: The characters of the row.
+//   - width: The width of the bounds.
+//
+// Returns:
+//   - text_line: The line.
+func (tb TextBox) expand(chars []rune, width int) text_line {
+	line := make([]rune, 0, len(chars))
+
+	for _, c := range chars {
+		if c != '\t' {
+			line = append(line, c)
+			continue
+		}
+
+		next := tb.next_tab_stop(len(line))
+
+		for len(line) < next {
+			line = append(line, ' ')
+		}
+	}
+
+	if len(line) > width {
+		return text_line{chars: line[:width], cut: true}
+	}
+
+	return text_line{chars: line}
+}
+
+// next_tab_stop is a helper method that returns the column of the tab stop after
+// the given column.
+//
+// Parameters:
+//   - col: The current column.
+//
+// Returns:
+//   - int: The column of the next tab stop.
+func (tb TextBox) next_tab_stop(col int) int {
+	for _, stop := range tb.tab_stops {
+		if stop > col {
+			return stop
+		}
+	}
+
+	width := tb.tab_width
+	if width <= 0 {
+		width = DefaultTabWidth
+	}
+
+	return (col/width + 1) * width
+}
+
 // NewTextBox creates a new text box.
 //
 // Returns:
 //   - *TextBox: The new text box. Never returns nil.
 func NewTextBox() *TextBox {
 	return &TextBox{
-		style: tcell.StyleDefault,
+		style:     tcell.StyleDefault,
+		tab_width: DefaultTabWidth,
+		overflow:  DefaultOverflow,
 	}
 }
 
@@ -89,4 +325,165 @@ func (tb *TextBox) ChangeStyle(style tcell.Style) {
 	}
 
 	tb.style = style
+	tb.role = ""
+}
+
+// ChangeRole makes the style of the text box follow the given role of the current
+// theme. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - role: The role. If empty, the last style set is used.
+func (tb *TextBox) ChangeRole(role theme.Role) {
+	if tb == nil {
+		return
+	}
+
+	tb.role = role
+}
+
+// SetBounds changes the area the text is drawn in. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - bounds: The area. If empty, the text is drawn without bounds from the
+//     coordinates given to Draw.
+func (tb *TextBox) SetBounds(bounds Rect) {
+	if tb == nil {
+		return
+	}
+
+	tb.bounds = bounds
+}
+
+// SetWrap changes how lines wider than the bounds are broken. Does nothing with a
+// nil receiver.
+//
+// Parameters:
+//   - mode: The wrap mode.
+func (tb *TextBox) SetWrap(mode WrapMode) {
+	if tb == nil {
+		return
+	}
+
+	tb.wrap = mode
+}
+
+// SetAlign changes the horizontal alignment of the lines within the bounds. Does
+// nothing with a nil receiver.
+//
+// Parameters:
+//   - align: The alignment.
+func (tb *TextBox) SetAlign(align Alignment) {
+	if tb == nil {
+		return
+	}
+
+	tb.align = align
+}
+
+// SetTabStops changes the explicit tab stops. After the last one, tab stops are
+// every tab width columns (see SetTabWidth). Does nothing with a nil receiver.
+//
+// Parameters:
+//   - stops: The columns of the tab stops. Non-increasing stops are ignored.
+func (tb *TextBox) SetTabStops(stops ...int) {
+	if tb == nil {
+		return
+	}
+
+	tb.tab_stops = tb.tab_stops[:0]
+
+	for _, stop := range stops {
+		if stop > 0 && (len(tb.tab_stops) == 0 || stop > tb.tab_stops[len(tb.tab_stops)-1]) {
+			tb.tab_stops = append(tb.tab_stops, stop)
+		}
+	}
+}
+
+// SetTabWidth changes the distance between two tab stops after the explicit ones.
+// Does nothing with a nil receiver.
+//
+// Parameters:
+//   - width: The distance. Non-positive values mean DefaultTabWidth.
+func (tb *TextBox) SetTabWidth(width int) {
+	if tb == nil {
+		return
+	}
+
+	tb.tab_width = width
+}
+
+// SetOverflow changes the indicator drawn in the last cell of a line that is cut
+// and of the last visible line when lines are hidden below. Does nothing with a
+// nil receiver.
+//
+// Parameters:
+//   - indicator: The indicator. 0 for none.
+func (tb *TextBox) SetOverflow(indicator rune) {
+	if tb == nil {
+		return
+	}
+
+	tb.overflow = indicator
+}
+
+// Scroll returns the index of the first line drawn.
+//
+// Returns:
+//   - int: The index of the first line.
+func (tb *TextBox) Scroll() int {
+	if tb == nil {
+		return 0
+	}
+
+	return tb.scroll
+}
+
+// ScrollTo changes the index of the first line drawn, clamped to the lines of the
+// text within the bounds. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - line: The index of the first line.
+func (tb *TextBox) ScrollTo(line int) {
+	if tb == nil {
+		return
+	}
+
+	max_scroll := 0
+
+	if !tb.bounds.IsEmpty() {
+		max_scroll = max(tb.LineCount()-tb.bounds.Height, 0)
+	}
+
+	tb.scroll = max(min(line, max_scroll), 0)
+}
+
+// ScrollBy moves the first line drawn. See ScrollTo.
+//
+// Parameters:
+//   - delta: The number of lines to move by. Positive values scroll down.
+func (tb *TextBox) ScrollBy(delta int) {
+	if tb == nil {
+		return
+	}
+
+	tb.ScrollTo(tb.scroll + delta)
+}
+
+// LineCount returns the number of lines of the text once laid out within the
+// bounds.
+//
+// Returns:
+//   - int: The number of lines.
+func (tb *TextBox) LineCount() int {
+	if tb == nil {
+		return 0
+	}
+
+	width := tb.bounds.Width
+	if tb.bounds.IsEmpty() {
+		width = math.MaxInt / 2
+	}
+
+	return len(tb.layout(width))
 }
@@ -0,0 +1,112 @@
+package screen
+
+import (
+	"strings"
+	"testing"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+func draw_text_box(t *testing.T, tb *TextBox, width, height int) string {
+	t.Helper()
+
+	table, err := dtb.NewTable(width, height)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	x, y := 0, 0
+
+	err = tb.Draw(NewTableDrawable(table, tcell.StyleDefault), &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	lines := table.GetLines()
+	for i, line := range lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func TestTextBox(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		setup func(tb *TextBox)
+		want  string
+	}{
+		{
+			name: "word wrap",
+			text: "the quick brown fox",
+			setup: func(tb *TextBox) {
+				tb.SetBounds(Rect{X: 1, Y: 0, Width: 10, Height: 3})
+				tb.SetWrap(WrapWord)
+			},
+			want: " the quick\n brown fox\n",
+		},
+		{
+			name: "unbounded alignment",
+			text: "a\nabc\nab",
+			setup: func(tb *TextBox) {
+				tb.SetAlign(AlignRight)
+			},
+			want: "  a\nabc\n ab",
+		},
+		{
+			name: "newline keeps x",
+			text: "ab\ncd",
+			setup: func(tb *TextBox) {
+				tb.SetBounds(Rect{X: 2, Y: 1, Width: 5, Height: 2})
+			},
+			want: "\n  ab\n  cd",
+		},
+		{
+			name: "right align and tab stops",
+			text: "a\tb",
+			setup: func(tb *TextBox) {
+				tb.SetBounds(Rect{Width: 8, Height: 1})
+				tb.SetAlign(AlignRight)
+				tb.SetTabStops(3)
+			},
+			want: "    a  b\n\n",
+		},
+		{
+			name: "scroll and overflow",
+			text: "1\n2\n3\n4",
+			setup: func(tb *TextBox) {
+				tb.SetBounds(Rect{Width: 3, Height: 2})
+				tb.ScrollBy(1)
+			},
+			want: "2\n3 …\n",
+		},
+		{
+			name: "cut line",
+			text: "abcdef",
+			setup: func(tb *TextBox) {
+				tb.SetBounds(Rect{Width: 4, Height: 1})
+			},
+			want: "abc…\n\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tb := NewTextBox()
+
+			err := tb.ChangeText(tt.text)
+			if err != nil {
+				t.Fatalf("Expected no error, but got %s", err.Error())
+			}
+
+			tt.setup(tb)
+
+			got := draw_text_box(t, tb, 12, 3)
+			if got != tt.want {
+				t.Fatalf("Expected %q, but got %q", tt.want, got)
+			}
+		})
+	}
+}
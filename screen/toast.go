@@ -0,0 +1,226 @@
+package screen
+
+import (
+	"slices"
+	"time"
+
+	"github.com/PlayerR9/display/anim"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// DefaultToastDuration is the default duration for which a toast is shown.
+	DefaultToastDuration time.Duration = 3 * time.Second
+
+	// MaxVisibleToasts is the maximum number of toasts shown at once. Further toasts
+	// wait until older ones expire.
+	MaxVisibleToasts int = 5
+)
+
+// Severity is the severity of a notification.
+type Severity int
+
+const (
+	// SeverityInfo is the severity of informative notifications.
+	SeverityInfo Severity = iota
+
+	// SeveritySuccess is the severity of notifications about a successful operation.
+	SeveritySuccess
+
+	// SeverityWarning is the severity of warnings.
+	SeverityWarning
+
+	// SeverityError is the severity of errors.
+	SeverityError
+)
+
+// String implements the errors.Enumer interface.
+func (s Severity) String() string {
+	return [...]string{
+		"info",
+		"success",
+		"warning",
+		"error",
+	}[s]
+}
+
+// icon is a helper method that returns the symbol shown before the message of a
+// toast of the severity.
+//
+// Returns:
+//   - rune: The symbol.
+func (s Severity) icon() rune {
+	return [...]rune{'ℹ', '✔', '⚠', '✖'}[s]
+}
+
+// toast is a timed notification.
+type toast struct {
+	// severity is the severity of the notification.
+	severity Severity
+
+	// message is the message of the notification.
+	message string
+
+	// duration is the duration for which the toast is shown.
+	duration time.Duration
+}
+
+// Notify shows a timed notification in the bottom-right corner of the screen. When
+// several notifications are shown at once, they are stacked with the newest at the
+// bottom; if there are already MaxVisibleToasts of them, the notification waits
+// until an older one expires. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - severity: The severity of the notification, which determines its style.
+//   - message: The message of the notification.
+//   - duration: The duration for which the notification is shown. If not positive,
+//     DefaultToastDuration is used.
+func (s *Screen) Notify(severity Severity, message string, duration time.Duration) {
+	if s == nil {
+		return
+	}
+
+	if duration <= 0 {
+		duration = DefaultToastDuration
+	}
+
+	t := &toast{
+		severity: severity,
+		message:  message,
+		duration: duration,
+	}
+
+	s.mu.Lock()
+
+	if len(s.toasts) >= MaxVisibleToasts {
+		s.toast_queue = append(s.toast_queue, t)
+		s.mu.Unlock()
+
+		return
+	}
+
+	s.toasts = append(s.toasts, t)
+	animator := s.animator
+
+	s.mu.Unlock()
+
+	s.schedule_toast(animator, t)
+	s.request_redraw()
+}
+
+// schedule_toast is a helper function that schedules the expiration of a toast.
+//
+// Parameters:
+//   - animator: The animator to schedule the expiration on.
+//   - t: The toast.
+func (s *Screen) schedule_toast(animator *anim.Animator, t *toast) {
+	animator.After(t.duration, func() {
+		s.expire_toast(t)
+	})
+}
+
+// expire_toast is a helper function that removes an expired toast and shows the
+// next waiting one, if any.
+//
+// Parameters:
+//   - t: The expired toast.
+func (s *Screen) expire_toast(t *toast) {
+	s.mu.Lock()
+
+	idx := slices.Index(s.toasts, t)
+	if idx != -1 {
+		s.toasts = slices.Delete(s.toasts, idx, idx+1)
+	}
+
+	var next *toast
+
+	if len(s.toast_queue) > 0 && len(s.toasts) < MaxVisibleToasts {
+		next = s.toast_queue[0]
+		s.toast_queue = s.toast_queue[1:]
+
+		s.toasts = append(s.toasts, next)
+	}
+
+	animator := s.animator
+
+	s.mu.Unlock()
+
+	if next != nil {
+		s.schedule_toast(animator, next)
+	}
+
+	s.request_redraw()
+}
+
+// SetToastStyle changes the style of the toasts of the given severity, which
+// otherwise comes from the "toast.<severity>" role of the current theme. Does
+// nothing with a nil receiver.
+//
+// Parameters:
+//   - severity: The severity.
+//   - style: The new style.
+func (s *Screen) SetToastStyle(severity Severity, style tcell.Style) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.toast_styles == nil {
+		s.toast_styles = make(map[Severity]tcell.Style)
+	}
+
+	s.toast_styles[severity] = style
+}
+
+// toast_style is a helper function that returns the style of the toasts of the given
+// severity.
+//
+// Parameters:
+//   - severity: The severity.
+//
+// Returns:
+//   - tcell.Style: The style.
+//
+// Assertions:
+//   - s.mu is locked.
+func (s *Screen) toast_style(severity Severity) tcell.Style {
+	style, ok := s.toast_styles[severity]
+	if ok {
+		return style
+	}
+
+	return theme.RoleToast.Child(severity.String()).Style()
+}
+
+// draw_toasts is a helper function that draws the visible toasts in the bottom-right
+// corner of the table, above the given bottom row.
+//
+// Parameters:
+//   - table: The table to draw on.
+//   - bottom: The row right below the last toast.
+func (s *Screen) draw_toasts(table *dtb.Table, bottom int) {
+	s.mu.Lock()
+	toasts := slices.Clone(s.toasts)
+
+	styles := make([]tcell.Style, 0, len(toasts))
+	for _, t := range toasts {
+		styles = append(styles, s.toast_style(t.severity))
+	}
+
+	s.mu.Unlock()
+
+	width := table.Width()
+	y := bottom - len(toasts)
+
+	for i, t := range toasts {
+		text := " " + string(t.severity.icon()) + " " + truncate(t.message, max(width-6, 1)) + " "
+		x := max(width-len([]rune(text))-1, 0)
+
+		row := y + i
+		table.WriteLineAt(&x, &row, text, styles[i], true)
+	}
+}
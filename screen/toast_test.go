@@ -0,0 +1,77 @@
+package screen
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/PlayerR9/display/anim"
+)
+
+func TestToasts(t *testing.T) {
+	s := new_test_screen(t, 20, 8)
+
+	err := s.dt.resize(20, 8)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	clock := anim.NewVirtualClock(time.Unix(0, 0))
+	animator := anim.NewAnimator(clock, anim.DefaultFrameRate)
+
+	s.SetAnimator(animator)
+
+	// check is a helper function that checks the toasts drawn above the last row,
+	// from the top one to the bottom one.
+	check := func(want ...string) {
+		t.Helper()
+
+		s.render()
+
+		lines := s.dt.frame.GetLines()
+		top := len(lines) - len(want)
+
+		for i, line := range lines {
+			if i < top {
+				if strings.TrimSpace(line) != "" {
+					t.Fatalf("Expected row %d to be empty, but got %q", i, line)
+				}
+
+				continue
+			}
+
+			if !strings.HasSuffix(line, want[i-top]+" ") {
+				t.Fatalf("Expected row %d to end with %q, but got %q", i, want[i-top], line)
+			}
+		}
+	}
+
+	s.Notify(SeverityInfo, "one", time.Second)
+	s.Notify(SeverityError, "two", 2*time.Second)
+
+	// The newest toast is at the bottom.
+	check(" ℹ one ", " ✖ two ")
+
+	clock.Advance(time.Second)
+	animator.Step()
+
+	check(" ✖ two ")
+
+	for i := range MaxVisibleToasts + 1 {
+		s.Notify(SeverityWarning, fmt.Sprintf("w%d", i), time.Second)
+	}
+
+	// Only MaxVisibleToasts toasts are shown; the last one waits.
+	check(" ✖ two ", " ⚠ w0 ", " ⚠ w1 ", " ⚠ w2 ", " ⚠ w3 ")
+
+	clock.Advance(time.Second)
+	animator.Step()
+
+	check(" ⚠ w4 ", " ⚠ w5 ")
+
+	clock.Advance(time.Second)
+	animator.Step()
+
+	check()
+}
@@ -0,0 +1,727 @@
+package screen
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	"github.com/gdamore/tcell"
+)
+
+// DefaultWorkspace is the name of the workspace every screen starts with.
+const DefaultWorkspace string = "main"
+
+var (
+	// ErrUnknownWorkspace occurs when no workspace has the given name.
+	ErrUnknownWorkspace = errors.New("unknown workspace")
+
+	// ErrLastWorkspace occurs when trying to remove the only workspace of a screen.
+	ErrLastWorkspace = errors.New("cannot remove the last workspace")
+)
+
+// Workspace is one of the independent component trees hosted by a screen (e.g., a
+// tab). Each workspace has its own focus and scroll state. Only the active
+// workspace is drawn and receives input; the others keep their state and can
+// still be updated, but invalidating them does not redraw the screen until they
+// are shown.
+type Workspace struct {
+	// name is the name of the workspace.
+	name string
+
+	// screen is the screen that hosts the workspace.
+	screen *Screen
+
+	// root is the root of the component tree of the workspace.
+	root *Node
+
+	// focus is the node that receives the keys. Nil if no node has the focus.
+	focus *Node
+
+	// scroll_x and scroll_y are the offset of the view within the tree.
+	scroll_x, scroll_y int
+
+	// captured is the node that consumed the last button press. It receives every
+	// mouse event until the buttons are released (e.g., while dragging).
+	captured *Node
+
+	// mu is the mutex of the workspace.
+	mu sync.RWMutex
+}
+
+// Name returns the name of the workspace.
+//
+// Returns:
+//   - string: The name of the workspace.
+func (ws *Workspace) Name() string {
+	if ws == nil {
+		return ""
+	}
+
+	return ws.name
+}
+
+// Root returns the root of the component tree of the workspace.
+//
+// Returns:
+//   - *Node: The root. Nil if no tree was set.
+func (ws *Workspace) Root() *Node {
+	if ws == nil {
+		return nil
+	}
+
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	return ws.root
+}
+
+// SetRoot sets the root of the component tree of the workspace. The focus and the
+// scroll offset are reset. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - root: The root. Can be nil.
+func (ws *Workspace) SetRoot(root *Node) {
+	if ws == nil {
+		return
+	}
+
+	ws.mu.Lock()
+
+	old := ws.root
+	ws.root = root
+	ws.scroll_x, ws.scroll_y = 0, 0
+
+	ws.mu.Unlock()
+
+	if old != nil && old != root {
+		old.set_on_invalidate(nil)
+	}
+
+	if root != nil {
+		root.set_on_invalidate(ws.invalidated)
+	}
+
+	ws.SetFocus(nil)
+
+	ws.invalidated()
+}
+
+// IsActive checks whether the workspace is the one shown by its screen.
+//
+// Returns:
+//   - bool: True if the workspace is active, false otherwise.
+func (ws *Workspace) IsActive() bool {
+	if ws == nil || ws.screen == nil {
+		return false
+	}
+
+	return ws.screen.ActiveWorkspace() == ws
+}
+
+// invalidated is a helper method that requests a redraw if the workspace is shown.
+func (ws *Workspace) invalidated() {
+	if ws.IsActive() {
+		ws.screen.request_redraw()
+	}
+}
+
+// Focus returns the node that has the focus.
+//
+// Returns:
+//   - *Node: The focused node. Nil if no node has the focus.
+func (ws *Workspace) Focus() *Node {
+	if ws == nil {
+		return nil
+	}
+
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	return ws.focus
+}
+
+// SetFocus gives the focus to the given node. If the elements of the previous and
+// new focused nodes implement Focuser, they are told. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - node: The node to focus. If nil, no node has the focus.
+func (ws *Workspace) SetFocus(node *Node) {
+	if ws == nil {
+		return
+	}
+
+	ws.mu.Lock()
+
+	old := ws.focus
+	ws.focus = node
+
+	ws.mu.Unlock()
+
+	if old == node {
+		return
+	}
+
+	if f, ok := old.Element().(Focuser); ok {
+		f.SetFocused(false)
+	}
+
+	if f, ok := node.Element().(Focuser); ok {
+		f.SetFocused(true)
+	}
+
+	ws.invalidated()
+}
+
+// handlers is a helper method that returns the nodes of the tree whose element is
+// a Handler, in drawing order.
+//
+// Returns:
+//   - []*Node: The nodes.
+func (ws *Workspace) handlers() []*Node {
+	var nodes []*Node
+
+	for node := range ws.Root().Walk() {
+		if _, ok := node.Element().(Handler); ok {
+			nodes = append(nodes, node)
+		}
+	}
+
+	return nodes
+}
+
+// FocusNext gives the focus to the next node, in drawing order, whose element is a
+// Handler, wrapping around at the end.
+//
+// Returns:
+//   - bool: True if a node got the focus, false if there is none to focus.
+func (ws *Workspace) FocusNext() bool {
+	return ws.cycle_focus(1)
+}
+
+// FocusPrev gives the focus to the previous node, in drawing order, whose element
+// is a Handler, wrapping around at the start.
+//
+// Returns:
+//   - bool: True if a node got the focus, false if there is none to focus.
+func (ws *Workspace) FocusPrev() bool {
+	return ws.cycle_focus(-1)
+}
+
+// cycle_focus is a helper method that moves the focus among the handlers.
+//
+// Parameters:
+//   - step: 1 to move forward, -1 to move backward.
+//
+// Returns:
+//   - bool: True if a node got the focus, false if there is none to focus.
+func (ws *Workspace) cycle_focus(step int) bool {
+	if ws == nil {
+		return false
+	}
+
+	nodes := ws.handlers()
+	if len(nodes) == 0 {
+		return false
+	}
+
+	idx := slices.Index(nodes, ws.Focus())
+
+	if idx == -1 && step < 0 {
+		idx = 0
+	}
+
+	idx = (idx + step + len(nodes)) % len(nodes)
+
+	ws.SetFocus(nodes[idx])
+
+	return true
+}
+
+// Scroll returns the offset of the view within the component tree.
+//
+// Returns:
+//   - int: The horizontal offset.
+//   - int: The vertical offset.
+func (ws *Workspace) Scroll() (int, int) {
+	if ws == nil {
+		return 0, 0
+	}
+
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+
+	return ws.scroll_x, ws.scroll_y
+}
+
+// ScrollTo changes the offset of the view within the component tree. The tree is
+// as large as the preferred size of its root (see Node.SetSize), or the size of the
+// screen if larger, and the offset is clamped when drawn. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - x: The horizontal offset. Negative values are treated as 0.
+//   - y: The vertical offset. Negative values are treated as 0.
+func (ws *Workspace) ScrollTo(x, y int) {
+	if ws == nil {
+		return
+	}
+
+	ws.mu.Lock()
+	ws.scroll_x, ws.scroll_y = max(x, 0), max(y, 0)
+	ws.mu.Unlock()
+
+	ws.invalidated()
+}
+
+// ScrollBy moves the view within the component tree. See ScrollTo.
+//
+// Parameters:
+//   - dx: The horizontal movement.
+//   - dy: The vertical movement.
+func (ws *Workspace) ScrollBy(dx, dy int) {
+	x, y := ws.Scroll()
+
+	ws.ScrollTo(x+dx, y+dy)
+}
+
+// arrange is a helper method that assigns an area to the tree of the workspace
+// for a view of the given size and clamps the scroll offset.
+//
+// Parameters:
+//   - width: The width of the view.
+//   - height: The height of the view.
+//
+// Returns:
+//   - *Node: The root of the tree. Nil if no tree was set.
+func (ws *Workspace) arrange(width, height int) *Node {
+	ws.mu.Lock()
+
+	root := ws.root
+	if root == nil {
+		ws.mu.Unlock()
+
+		return nil
+	}
+
+	tree_width, tree_height := root.Size()
+	tree_width, tree_height = max(tree_width, width), max(tree_height, height)
+
+	ws.scroll_x = min(ws.scroll_x, tree_width-width)
+	ws.scroll_y = min(ws.scroll_y, tree_height-height)
+
+	area := Rect{
+		X:      -ws.scroll_x,
+		Y:      -ws.scroll_y,
+		Width:  tree_width,
+		Height: tree_height,
+	}
+
+	ws.mu.Unlock()
+
+	root.Arrange(area)
+
+	return root
+}
+
+// dispatch is a helper method that sends an input event to the tree of the
+// workspace: keys go to the focused node and mouse events to the top-most handler
+// under the pointer, which gets the focus on click. A handler that consumes a
+// button press captures the mouse until the buttons are released. Unhandled Tab
+// and Backtab cycle the focus and unhandled wheel events scroll the view.
+//
+// Parameters:
+//   - ev: The event to send.
+//
+// Returns:
+//   - bool: True if the event was consumed, false otherwise.
+func (ws *Workspace) dispatch(ev tcell.Event) bool {
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		if h, ok := ws.Focus().Element().(Handler); ok && h.HandleEvent(ev) {
+			ws.invalidated()
+
+			return true
+		}
+
+		switch ev.Key() {
+		case tcell.KeyTab:
+			return ws.FocusNext()
+		case tcell.KeyBacktab:
+			return ws.FocusPrev()
+		}
+	case *tcell.EventMouse:
+		x, y := ev.Position()
+		pressed := ev.Buttons()&(tcell.Button1|tcell.Button2|tcell.Button3) != 0
+
+		ws.mu.Lock()
+		node := ws.captured
+
+		if !pressed {
+			ws.captured = nil
+		}
+
+		ws.mu.Unlock()
+
+		if node == nil {
+			node = ws.Root().HandlerAt(x, y)
+
+			if node != nil && ev.Buttons()&tcell.Button1 != 0 {
+				ws.SetFocus(node)
+			}
+		}
+
+		var handler Handler
+
+		if node != nil {
+			var ok bool
+
+			handler, ok = node.Element().(Handler)
+			if !ok {
+				// The element of the captured node was replaced by one that does not
+				// handle events.
+				ws.mu.Lock()
+				ws.captured = nil
+				ws.mu.Unlock()
+			}
+		}
+
+		if handler != nil && handler.HandleEvent(ev) {
+			if pressed {
+				ws.mu.Lock()
+				ws.captured = node
+				ws.mu.Unlock()
+			}
+
+			ws.invalidated()
+
+			return true
+		}
+
+		switch {
+		case ev.Buttons()&tcell.WheelUp != 0:
+			ws.ScrollBy(0, -1)
+		case ev.Buttons()&tcell.WheelDown != 0:
+			ws.ScrollBy(0, 1)
+		default:
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// NewWorkspace adds a new, empty workspace to the screen. It is not shown until
+// switched to.
+//
+// Parameters:
+//   - name: The name of the workspace.
+//
+// Returns:
+//   - *Workspace: The new workspace, or the existing one if a workspace already
+//     has that name. Nil only if the receiver is nil.
+func (s *Screen) NewWorkspace(name string) *Workspace {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ws := range s.workspaces {
+		if ws.name == name {
+			return ws
+		}
+	}
+
+	ws := &Workspace{
+		name:   name,
+		screen: s,
+	}
+
+	s.workspaces = append(s.workspaces, ws)
+
+	return ws
+}
+
+// Workspace returns the workspace with the given name.
+//
+// Parameters:
+//   - name: The name of the workspace.
+//
+// Returns:
+//   - *Workspace: The workspace. Nil if there is none with that name.
+func (s *Screen) Workspace(name string) *Workspace {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, ws := range s.workspaces {
+		if ws.name == name {
+			return ws
+		}
+	}
+
+	return nil
+}
+
+// Workspaces returns the workspaces of the screen, in order.
+//
+// Returns:
+//   - []*Workspace: The workspaces.
+func (s *Screen) Workspaces() []*Workspace {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.workspaces)
+}
+
+// ActiveWorkspace returns the workspace that is shown.
+//
+// Returns:
+//   - *Workspace: The active workspace. Nil only if the receiver is nil.
+func (s *Screen) ActiveWorkspace() *Workspace {
+	if s == nil {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.workspaces[s.active]
+}
+
+// SwitchWorkspace shows the workspace with the given name.
+//
+// Parameters:
+//   - name: The name of the workspace.
+//
+// Returns:
+//   - error: An error if the workspace could not be shown.
+//
+// Errors:
+//   - ErrUnknownWorkspace: If no workspace has that name.
+func (s *Screen) SwitchWorkspace(name string) error {
+	if s == nil {
+		return ErrUnknownWorkspace
+	}
+
+	s.mu.Lock()
+
+	idx := slices.IndexFunc(s.workspaces, func(ws *Workspace) bool {
+		return ws.name == name
+	})
+
+	s.mu.Unlock()
+
+	if idx == -1 {
+		return ErrUnknownWorkspace
+	}
+
+	s.switch_to(idx)
+
+	return nil
+}
+
+// NextWorkspace shows the workspace after the active one, wrapping around at the
+// end. Does nothing with a nil receiver.
+func (s *Screen) NextWorkspace() {
+	if s == nil {
+		return
+	}
+
+	s.switch_to(s.relative(1))
+}
+
+// PrevWorkspace shows the workspace before the active one, wrapping around at the
+// start. Does nothing with a nil receiver.
+func (s *Screen) PrevWorkspace() {
+	if s == nil {
+		return
+	}
+
+	s.switch_to(s.relative(-1))
+}
+
+// relative is a helper method that returns the index of the workspace at the given
+// distance from the active one, wrapping around.
+//
+// Parameters:
+//   - delta: The distance. Positive values are after the active workspace.
+//
+// Returns:
+//   - int: The index of the workspace.
+func (s *Screen) relative(delta int) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	n := len(s.workspaces)
+
+	return ((s.active+delta)%n + n) % n
+}
+
+// switch_to is a helper method that shows the workspace at the given index and
+// calls the change listener.
+//
+// Parameters:
+//   - idx: The index of the workspace. Out of range indices are ignored.
+//
+// Returns:
+//   - bool: True if another workspace is shown, false otherwise.
+func (s *Screen) switch_to(idx int) bool {
+	s.mu.Lock()
+
+	if idx < 0 || idx >= len(s.workspaces) || idx == s.active {
+		s.mu.Unlock()
+
+		return false
+	}
+
+	s.active = idx
+	ws := s.workspaces[idx]
+	fn := s.on_workspace
+
+	s.mu.Unlock()
+
+	s.request_redraw()
+
+	if fn != nil {
+		fn(ws)
+	}
+
+	return true
+}
+
+// RemoveWorkspace removes the workspace with the given name. If it was the active
+// one, the previous workspace is shown instead, or the next one if it was the
+// first.
+//
+// Parameters:
+//   - name: The name of the workspace.
+//
+// Returns:
+//   - error: An error if the workspace could not be removed.
+//
+// Errors:
+//   - ErrUnknownWorkspace: If no workspace has that name.
+//   - ErrLastWorkspace: If it is the only workspace of the screen.
+func (s *Screen) RemoveWorkspace(name string) error {
+	if s == nil {
+		return ErrUnknownWorkspace
+	}
+
+	s.mu.Lock()
+
+	idx := slices.IndexFunc(s.workspaces, func(ws *Workspace) bool {
+		return ws.name == name
+	})
+
+	if idx == -1 {
+		s.mu.Unlock()
+
+		return ErrUnknownWorkspace
+	} else if len(s.workspaces) == 1 {
+		s.mu.Unlock()
+
+		return ErrLastWorkspace
+	}
+
+	was_active := idx == s.active
+
+	s.workspaces = slices.Delete(s.workspaces, idx, idx+1)
+
+	if s.active > idx || s.active == len(s.workspaces) {
+		s.active--
+	}
+
+	ws := s.workspaces[s.active]
+	fn := s.on_workspace
+
+	s.mu.Unlock()
+
+	if was_active {
+		s.request_redraw()
+
+		if fn != nil {
+			fn(ws)
+		}
+	}
+
+	return nil
+}
+
+// OnWorkspaceChange sets the function called whenever another workspace is shown;
+// for instance, to update the status bar. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - fn: The function to call with the workspace that is shown. Can be nil.
+func (s *Screen) OnWorkspaceChange(fn func(ws *Workspace)) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.on_workspace = fn
+	s.mu.Unlock()
+}
+
+// EnableWorkspaceKeys enables or disables the keybindings that switch workspaces:
+// Alt+1 to Alt+9 show the workspace at that position, and Alt+Right and Alt+Left
+// show the next and previous ones. They are enabled by default, and only used if
+// the focused handler does not consume the key. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - enabled: True to enable the keybindings, false to disable them.
+func (s *Screen) EnableWorkspaceKeys(enabled bool) {
+	if s == nil {
+		return
+	}
+
+	s.mu.Lock()
+	s.workspace_keys = enabled
+	s.mu.Unlock()
+}
+
+// workspace_key is a helper method that handles the keybindings that switch
+// workspaces.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the key switched workspaces, false otherwise; for instance,
+//     if there is no workspace at the position of Alt+digit.
+func (s *Screen) workspace_key(ev *tcell.EventKey) bool {
+	s.mu.Lock()
+	enabled := s.workspace_keys
+	s.mu.Unlock()
+
+	if !enabled || ev.Modifiers()&tcell.ModAlt == 0 {
+		return false
+	}
+
+	switch ev.Key() {
+	case tcell.KeyRight:
+		return s.switch_to(s.relative(1))
+	case tcell.KeyLeft:
+		return s.switch_to(s.relative(-1))
+	case tcell.KeyRune:
+		r := ev.Rune()
+		if r < '1' || r > '9' {
+			return false
+		}
+
+		return s.switch_to(int(r - '1'))
+	default:
+		return false
+	}
+}
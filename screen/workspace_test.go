@@ -0,0 +1,90 @@
+package screen
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+type mockHandler struct {
+	mockDrawer
+
+	keys    []rune
+	focused bool
+}
+
+func (m *mockHandler) HandleEvent(ev tcell.Event) bool {
+	key, ok := ev.(*tcell.EventKey)
+	if !ok || key.Key() != tcell.KeyRune {
+		return false
+	}
+
+	m.keys = append(m.keys, key.Rune())
+
+	return true
+}
+
+func (m *mockHandler) SetFocused(focused bool) {
+	m.focused = focused
+}
+
+func TestWorkspaces(t *testing.T) {
+	s := new_test_screen(t, 20, 5)
+
+	first := &mockHandler{mockDrawer: mockDrawer{text: "first"}}
+	second := &mockHandler{mockDrawer: mockDrawer{text: "second"}}
+
+	s.SetRoot(NewContainer(VBox{}, NewNode(first), NewNode(second)))
+
+	logs := s.NewWorkspace("logs")
+	logs.SetRoot(NewNode(&mockDrawer{text: "logs"}))
+
+	s.render()
+
+	if c := s.dt.frame.CellAt(0, 0); c == nil || c.Char != 'f' {
+		t.Fatalf("Expected the main workspace to be drawn")
+	}
+
+	if !s.workspace_key(tcell.NewEventKey(tcell.KeyRune, '2', tcell.ModAlt)) {
+		t.Fatalf("Expected Alt+2 to switch workspaces")
+	}
+
+	if s.ActiveWorkspace() != logs {
+		t.Fatalf("Expected the logs workspace to be active")
+	}
+
+	if s.workspace_key(tcell.NewEventKey(tcell.KeyRune, '3', tcell.ModAlt)) {
+		t.Fatalf("Expected Alt+3 to be declined without a third workspace")
+	}
+
+	s.render()
+
+	if c := s.dt.frame.CellAt(0, 0); c == nil || c.Char != 'l' {
+		t.Fatalf("Expected the logs workspace to be drawn")
+	}
+
+	s.PrevWorkspace()
+
+	main := s.ActiveWorkspace()
+
+	main.dispatch(tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+	main.dispatch(tcell.NewEventKey(tcell.KeyTab, 0, tcell.ModNone))
+	main.dispatch(tcell.NewEventKey(tcell.KeyRune, 'x', tcell.ModNone))
+
+	if first.focused || !second.focused {
+		t.Fatalf("Expected the focus to move to the second handler")
+	}
+
+	if len(second.keys) != 1 || second.keys[0] != 'x' {
+		t.Fatalf("Expected the focused handler to receive the key, but got %q", second.keys)
+	}
+
+	err := s.RemoveWorkspace(DefaultWorkspace)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if err := s.RemoveWorkspace("logs"); err != ErrLastWorkspace {
+		t.Fatalf("Expected ErrLastWorkspace, but got %v", err)
+	}
+}
@@ -0,0 +1,147 @@
+package screen
+
+import (
+	"strings"
+)
+
+// wrap_words is a helper function that splits the text into lines of at most the
+// given number of runes. Lines are broken at spaces when possible and words longer
+// than a line are broken at the width. Newlines in the text always start a new line.
+//
+// Parameters:
+//   - text: The text to wrap.
+//   - width: The maximum number of runes per line. Values less than 1 are treated
+//     as 1.
+//
+// Returns:
+//   - []string: The lines.
+func wrap_words(text string, width int) []string {
+	width = max(width, 1)
+
+	var lines []string
+
+	for _, paragraph := range strings.Split(text, "\n") {
+		words := strings.Fields(paragraph)
+		if len(words) == 0 {
+			lines = append(lines, "")
+			continue
+		}
+
+		var line []rune
+
+		for _, word := range words {
+			chars := []rune(word)
+
+			if len(line) > 0 && len(line)+1+len(chars) <= width {
+				line = append(line, ' ')
+				line = append(line, chars...)
+
+				continue
+			}
+
+			if len(line) > 0 {
+				lines = append(lines, string(line))
+				line = nil
+			}
+
+			for len(chars) > width {
+				lines = append(lines, string(chars[:width]))
+				chars = chars[width:]
+			}
+
+			line = append(line, chars...)
+		}
+
+		lines = append(lines, string(line))
+	}
+
+	return lines
+}
+
+// WrapRow is a row of a line once wrapped: the characters of the line in
+// [Start, End).
+type WrapRow struct {
+	// Start is the index of the first character of the row.
+	Start int
+
+	// End is the index after the last character of the row.
+	End int
+}
+
+// WrapRows splits a line into rows of at most the given number of cells, as a
+// TextBox does. The columns of tabs are counted from the start of their row.
+//
+// With WrapWord, rows are broken after the last space that fits and a space that
+// does not fit stays at the end of its row, so callers that draw the rows may drop
+// it. Characters wider than a whole row get a row of their own.
+//
+// Parameters:
+//   - chars: The line, without newlines.
+//   - width: The maximum number of cells of a row. Non-positive values, like
+//     WrapNone, do not break the line.
+//   - mode: The wrap mode.
+//   - next_tab: The function that returns the column of the tab stop after the
+//     given column. Assumed to return a greater column.
+//
+// Returns:
+//   - []WrapRow: The rows, in order. Never empty.
+func WrapRows(chars []rune, width int, mode WrapMode, next_tab func(col int) int) []WrapRow {
+	if mode == WrapNone || width <= 0 {
+		return []WrapRow{{Start: 0, End: len(chars)}}
+	}
+
+	cell_width := func(c rune, col int) int {
+		if c == '\t' {
+			return next_tab(col) - col
+		}
+
+		return 1
+	}
+
+	var rows []WrapRow
+
+	row_start, col := 0, 0
+	last_space := -1
+
+	for i := 0; i < len(chars); i++ {
+		c := chars[i]
+		w := cell_width(c, col)
+
+		if col+w > width && i > row_start {
+			if mode == WrapWord && c == ' ' {
+				// The space hangs at the end of the row.
+				rows = append(rows, WrapRow{Start: row_start, End: i + 1})
+
+				row_start, col = i+1, 0
+				last_space = -1
+
+				continue
+			}
+
+			brk := i
+
+			if mode == WrapWord && last_space >= row_start {
+				brk = last_space + 1
+			}
+
+			rows = append(rows, WrapRow{Start: row_start, End: brk})
+
+			row_start, col = brk, 0
+
+			for _, prev := range chars[brk:i] {
+				col += cell_width(prev, col)
+			}
+
+			last_space = -1
+			w = cell_width(c, col)
+		}
+
+		if c == ' ' {
+			last_space = i
+		}
+
+		col += w
+	}
+
+	return append(rows, WrapRow{Start: row_start, End: len(chars)})
+}
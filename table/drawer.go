@@ -17,3 +17,41 @@ type Displayer interface {
 	//   - Assumes that the table is not nil.
 	Draw(table *Table, x, y *int) error
 }
+
+// DrawClipped draws the displayer in the given area of the table such that nothing
+// is drawn outside of it. The displayer sees a table of the size of the area and
+// starts drawing at its top-left corner. Nil cells are treated as transparent.
+//
+// Parameters:
+//   - table: The table to draw on.
+//   - elem: The displayer to draw.
+//   - x: The x coordinate of the top-left corner of the area.
+//   - y: The y coordinate of the top-left corner of the area.
+//   - width: The width of the area.
+//   - height: The height of the area.
+//
+// Returns:
+//   - error: An error if the displayer could not be drawn.
+//
+// If the table or the displayer are nil, or the area is empty, nothing is drawn.
+func DrawClipped(table *Table, elem Displayer, x, y, width, height int) error {
+	if table == nil || elem == nil || width <= 0 || height <= 0 {
+		return nil
+	}
+
+	sub, err := NewTable(width, height)
+	if err != nil {
+		return err
+	}
+
+	x_coord, y_coord := 0, 0
+
+	err = elem.Draw(sub, &x_coord, &y_coord)
+	if err != nil {
+		return err
+	}
+
+	table.OverlayTableAt(sub, x, y)
+
+	return nil
+}
@@ -485,7 +485,9 @@ func (t *Table) ResizeHeight(new_height int) error {
 	} else if new_height < t.height {
 		t.table = t.table[:new_height]
 	} else {
-		t.table = append(t.table, make([][]*Cell, new_height-t.height)...)
+		for i := t.height; i < new_height; i++ {
+			t.table = append(t.table, make([]*Cell, t.width))
+		}
 	}
 
 	t.height = new_height
@@ -561,3 +563,48 @@ func (t *Table) WriteLineAt(x, y *int, line string, style tcell.Style, isHorizon
 		t.WriteVerticalSequence(x, y, sequence)
 	}
 }
+
+// OverlayTableAt copies the non-nil cells of the given table to the table starting
+// at the given coordinates. Unlike WriteTableAt, nil cells are treated as transparent
+// and, thus, do not overwrite the cells below them. Out-of-bounds cells are ignored.
+//
+// Parameters:
+//   - table: The table to overlay.
+//   - x: The x-coordinate to overlay the table at.
+//   - y: The y-coordinate to overlay the table at.
+func (t *Table) OverlayTableAt(table *Table, x, y int) {
+	if t == nil || table == nil || t == table {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	table.mu.RLock()
+	defer table.mu.RUnlock()
+
+	for offsetY := 0; offsetY < table.height; offsetY++ {
+		Y := y + offsetY
+
+		if Y < 0 {
+			continue
+		} else if Y >= t.height {
+			break
+		}
+
+		for offsetX := 0; offsetX < table.width; offsetX++ {
+			X := x + offsetX
+
+			if X < 0 {
+				continue
+			} else if X >= t.width {
+				break
+			}
+
+			cell := table.table[offsetY][offsetX]
+			if cell != nil {
+				t.table[Y][X] = cell
+			}
+		}
+	}
+}
@@ -0,0 +1,147 @@
+package theme
+
+import "github.com/gdamore/tcell"
+
+const (
+	// DarkName is the name of the built-in dark theme.
+	DarkName string = "dark"
+
+	// LightName is the name of the built-in light theme.
+	LightName string = "light"
+
+	// HighContrastName is the name of the built-in high-contrast theme.
+	HighContrastName string = "high-contrast"
+)
+
+// Builtin returns new copies of the built-in themes.
+//
+// Returns:
+//   - []*Theme: The built-in themes: dark, light and high-contrast.
+func Builtin() []*Theme {
+	return []*Theme{
+		dark_theme(),
+		light_theme(),
+		high_contrast_theme(),
+	}
+}
+
+// dark_theme is a helper function that creates the built-in dark theme.
+//
+// Returns:
+//   - *Theme: The theme.
+func dark_theme() *Theme {
+	base := tcell.StyleDefault.Foreground(tcell.ColorSilver).Background(tcell.ColorBlack)
+
+	return NewTheme(DarkName, map[Role]tcell.Style{
+		RoleDefault:       base,
+		RoleTitle:         base.Foreground(tcell.ColorWhite).Bold(true),
+		RoleMuted:         base.Foreground(tcell.ColorGray),
+		RoleError:         base.Foreground(tcell.ColorRed).Bold(true),
+		RoleWarning:       base.Foreground(tcell.ColorYellow),
+		RoleSuccess:       base.Foreground(tcell.ColorGreen),
+		RoleSelection:     base.Foreground(tcell.ColorBlack).Background(tcell.ColorTeal),
+		RoleBorder:        base.Foreground(tcell.ColorGray),
+		RoleBorderFocused: base.Foreground(tcell.ColorAqua),
+		RoleStatus:        base.Foreground(tcell.ColorWhite).Background(tcell.ColorNavy),
+		RoleDialog:        base.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkSlateGray),
+		RoleDialogFocused: base.Foreground(tcell.ColorBlack).Background(tcell.ColorAqua),
+		RoleMenu:          base.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkSlateGray),
+		RoleMenuSelected:  base.Foreground(tcell.ColorBlack).Background(tcell.ColorAqua),
+		RoleInput:         base.Foreground(tcell.ColorWhite).Background(tcell.ColorDarkSlateGray),
+		RoleProgress:      base.Foreground(tcell.ColorAqua).Background(tcell.ColorDarkSlateGray),
+		"toast.info":      base.Foreground(tcell.ColorWhite).Background(tcell.ColorNavy),
+		"toast.success":   base.Foreground(tcell.ColorBlack).Background(tcell.ColorGreen),
+		"toast.warning":   base.Foreground(tcell.ColorBlack).Background(tcell.ColorYellow),
+		"toast.error":     base.Foreground(tcell.ColorWhite).Background(tcell.ColorMaroon).Bold(true),
+		"token.keyword":   base.Foreground(tcell.ColorFuchsia).Bold(true),
+		"token.string":    base.Foreground(tcell.ColorGreen),
+		"token.number":    base.Foreground(tcell.ColorAqua),
+		"token.comment":   base.Foreground(tcell.ColorGray).Italic(true),
+		"token.operator":  base.Foreground(tcell.ColorYellow),
+		"chart.0":         base.Foreground(tcell.ColorAqua),
+		"chart.1":         base.Foreground(tcell.ColorYellow),
+		"chart.2":         base.Foreground(tcell.ColorFuchsia),
+		"chart.3":         base.Foreground(tcell.ColorLime),
+	})
+}
+
+// light_theme is a helper function that creates the built-in light theme.
+//
+// Returns:
+//   - *Theme: The theme.
+func light_theme() *Theme {
+	base := tcell.StyleDefault.Foreground(tcell.ColorBlack).Background(tcell.ColorWhite)
+
+	return NewTheme(LightName, map[Role]tcell.Style{
+		RoleDefault:       base,
+		RoleTitle:         base.Foreground(tcell.ColorNavy).Bold(true),
+		RoleMuted:         base.Foreground(tcell.ColorGray),
+		RoleError:         base.Foreground(tcell.ColorMaroon).Bold(true),
+		RoleWarning:       base.Foreground(tcell.ColorOlive),
+		RoleSuccess:       base.Foreground(tcell.ColorGreen),
+		RoleSelection:     base.Foreground(tcell.ColorWhite).Background(tcell.ColorBlue),
+		RoleBorder:        base.Foreground(tcell.ColorGray),
+		RoleBorderFocused: base.Foreground(tcell.ColorBlue),
+		RoleStatus:        base.Foreground(tcell.ColorBlack).Background(tcell.ColorSilver),
+		RoleDialog:        base.Foreground(tcell.ColorBlack).Background(tcell.ColorLightGray),
+		RoleDialogFocused: base.Foreground(tcell.ColorWhite).Background(tcell.ColorBlue),
+		RoleMenu:          base.Foreground(tcell.ColorBlack).Background(tcell.ColorLightGray),
+		RoleMenuSelected:  base.Foreground(tcell.ColorWhite).Background(tcell.ColorBlue),
+		RoleInput:         base.Foreground(tcell.ColorBlack).Background(tcell.ColorLightGray),
+		RoleProgress:      base.Foreground(tcell.ColorBlue).Background(tcell.ColorLightGray),
+		"toast.info":      base.Foreground(tcell.ColorWhite).Background(tcell.ColorBlue),
+		"toast.success":   base.Foreground(tcell.ColorWhite).Background(tcell.ColorGreen),
+		"toast.warning":   base.Foreground(tcell.ColorBlack).Background(tcell.ColorGold),
+		"toast.error":     base.Foreground(tcell.ColorWhite).Background(tcell.ColorRed).Bold(true),
+		"token.keyword":   base.Foreground(tcell.ColorPurple).Bold(true),
+		"token.string":    base.Foreground(tcell.ColorGreen),
+		"token.number":    base.Foreground(tcell.ColorTeal),
+		"token.comment":   base.Foreground(tcell.ColorGray).Italic(true),
+		"token.operator":  base.Foreground(tcell.ColorMaroon),
+		"chart.0":         base.Foreground(tcell.ColorBlue),
+		"chart.1":         base.Foreground(tcell.ColorMaroon),
+		"chart.2":         base.Foreground(tcell.ColorPurple),
+		"chart.3":         base.Foreground(tcell.ColorGreen),
+	})
+}
+
+// high_contrast_theme is a helper function that creates the built-in high-contrast
+// theme. It only uses black, white and yellow and relies on attributes rather than
+// colors to tell roles apart.
+//
+// Returns:
+//   - *Theme: The theme.
+func high_contrast_theme() *Theme {
+	base := tcell.StyleDefault.Foreground(tcell.ColorWhite).Background(tcell.ColorBlack)
+	inverse := base.Reverse(true)
+	accent := base.Foreground(tcell.ColorYellow)
+
+	return NewTheme(HighContrastName, map[Role]tcell.Style{
+		RoleDefault:       base,
+		RoleTitle:         base.Bold(true).Underline(true),
+		RoleMuted:         base,
+		RoleError:         inverse.Bold(true),
+		RoleWarning:       accent.Bold(true),
+		RoleSuccess:       base.Bold(true),
+		RoleSelection:     accent.Reverse(true).Bold(true),
+		RoleBorder:        base,
+		RoleBorderFocused: accent.Bold(true),
+		RoleStatus:        inverse,
+		RoleDialog:        base,
+		RoleDialogFocused: accent.Reverse(true).Bold(true),
+		RoleMenu:          base,
+		RoleMenuSelected:  accent.Reverse(true).Bold(true),
+		RoleInput:         base.Underline(true),
+		RoleProgress:      accent,
+		RoleToast:         inverse.Bold(true),
+		"toast.error":     accent.Reverse(true).Bold(true),
+		RoleToken:         base,
+		"token.keyword":   base.Bold(true),
+		"token.string":    accent,
+		"token.comment":   base.Italic(true),
+		RoleChart:         base,
+		"chart.1":         accent,
+		"chart.2":         base.Bold(true),
+		"chart.3":         accent.Bold(true),
+	})
+}
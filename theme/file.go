@@ -0,0 +1,246 @@
+package theme
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/gdamore/tcell"
+)
+
+// StyleSpec is the description of a style in a theme file.
+//
+// Colors are either names known to tcell (e.g., "navy", "darkorange"), hexadecimal
+// values (e.g., "#ff8800") or "default" for the default color of the terminal.
+type StyleSpec struct {
+	// Fg is the foreground color. Empty for the default color.
+	Fg string `json:"fg,omitempty" toml:"fg"`
+
+	// Bg is the background color. Empty for the default color.
+	Bg string `json:"bg,omitempty" toml:"bg"`
+
+	// Bold is true if the text is bold.
+	Bold bool `json:"bold,omitempty" toml:"bold"`
+
+	// Dim is true if the text is dimmed.
+	Dim bool `json:"dim,omitempty" toml:"dim"`
+
+	// Italic is true if the text is in italics.
+	Italic bool `json:"italic,omitempty" toml:"italic"`
+
+	// Underline is true if the text is underlined.
+	Underline bool `json:"underline,omitempty" toml:"underline"`
+
+	// Reverse is true if the foreground and background colors are swapped.
+	Reverse bool `json:"reverse,omitempty" toml:"reverse"`
+
+	// Blink is true if the text blinks.
+	Blink bool `json:"blink,omitempty" toml:"blink"`
+}
+
+// Style converts the description to a style.
+//
+// Returns:
+//   - tcell.Style: The style.
+//   - error: An error if a color is not valid.
+func (s StyleSpec) Style() (tcell.Style, error) {
+	fg, err := parse_color(s.Fg)
+	if err != nil {
+		return tcell.StyleDefault, fmt.Errorf("invalid foreground: %w", err)
+	}
+
+	bg, err := parse_color(s.Bg)
+	if err != nil {
+		return tcell.StyleDefault, fmt.Errorf("invalid background: %w", err)
+	}
+
+	style := tcell.StyleDefault.
+		Foreground(fg).
+		Background(bg).
+		Bold(s.Bold).
+		Dim(s.Dim).
+		Italic(s.Italic).
+		Underline(s.Underline).
+		Reverse(s.Reverse).
+		Blink(s.Blink)
+
+	return style, nil
+}
+
+// parse_color is a helper function that parses a color of a theme file.
+//
+// Parameters:
+//   - name: The name of the color.
+//
+// Returns:
+//   - tcell.Color: The color.
+//   - error: An error if the color is not valid.
+func parse_color(name string) (tcell.Color, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+
+	if name == "" || name == "default" {
+		return tcell.ColorDefault, nil
+	}
+
+	color := tcell.GetColor(name)
+	if color == tcell.ColorDefault {
+		return tcell.ColorDefault, fmt.Errorf("unknown color %q", name)
+	}
+
+	return color, nil
+}
+
+// FileSpec is the content of a theme file.
+//
+// Example (TOML):
+//
+//	name = "solarized"
+//	extends = "dark"
+//
+//	[styles.title]
+//	fg = "#b58900"
+//	bold = true
+//
+//	[styles."border.focused"]
+//	fg = "#268bd2"
+type FileSpec struct {
+	// Name is the name of the theme.
+	Name string `json:"name" toml:"name"`
+
+	// Extends is the name of a registered theme whose styles are used for the roles
+	// not defined by this theme. Empty for none.
+	Extends string `json:"extends,omitempty" toml:"extends"`
+
+	// Styles are the styles of each role.
+	Styles map[string]StyleSpec `json:"styles" toml:"styles"`
+}
+
+// Theme converts the description to a theme.
+//
+// Parameters:
+//   - base: The registry in which the extended theme, if any, is looked up. Can be
+//     nil if the theme does not extend another.
+//
+// Returns:
+//   - *Theme: The theme.
+//   - error: An error if the description is not valid.
+func (fs FileSpec) Theme(base *Registry) (*Theme, error) {
+	if fs.Name == "" {
+		return nil, gcers.NewErrInvalidParameter("name", gcers.NewErrEmpty(fs.Name))
+	}
+
+	styles := make(map[Role]tcell.Style)
+
+	if fs.Extends != "" {
+		parent := base.Get(fs.Extends)
+		if parent == nil {
+			return nil, fmt.Errorf("theme %q extends %q: %w", fs.Name, fs.Extends, ErrUnknownTheme)
+		}
+
+		parent.mu.RLock()
+
+		for role, style := range parent.styles {
+			styles[role] = style
+		}
+
+		parent.mu.RUnlock()
+	}
+
+	for role, spec := range fs.Styles {
+		style, err := spec.Style()
+		if err != nil {
+			return nil, fmt.Errorf("role %q: %w", role, err)
+		}
+
+		styles[Role(role)] = style
+	}
+
+	return NewTheme(fs.Name, styles), nil
+}
+
+// ParseJSON parses a theme from JSON data.
+//
+// Parameters:
+//   - data: The JSON data.
+//   - base: The registry in which the extended theme, if any, is looked up.
+//
+// Returns:
+//   - *Theme: The theme.
+//   - error: An error if the data is not a valid theme.
+func ParseJSON(data []byte, base *Registry) (*Theme, error) {
+	var fs FileSpec
+
+	err := json.Unmarshal(data, &fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.Theme(base)
+}
+
+// ParseTOML parses a theme from TOML data.
+//
+// Parameters:
+//   - data: The TOML data.
+//   - base: The registry in which the extended theme, if any, is looked up.
+//
+// Returns:
+//   - *Theme: The theme.
+//   - error: An error if the data is not a valid theme.
+func ParseTOML(data []byte, base *Registry) (*Theme, error) {
+	var fs FileSpec
+
+	err := toml.Unmarshal(data, &fs)
+	if err != nil {
+		return nil, err
+	}
+
+	return fs.Theme(base)
+}
+
+// LoadFile loads a theme file and registers the theme. The format of the file is
+// given by its extension: ".json" or ".toml".
+//
+// Parameters:
+//   - path: The path of the file.
+//
+// Returns:
+//   - *Theme: The loaded theme.
+//   - error: An error if the file could not be loaded.
+//
+// Errors:
+//   - gcers.NilReceiver: If the receiver is nil.
+//   - any other error: If the file could not be read or is not a valid theme.
+func (r *Registry) LoadFile(path string) (*Theme, error) {
+	if r == nil {
+		return nil, gcers.NilReceiver
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var t *Theme
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".json":
+		t, err = ParseJSON(data, r)
+	case ".toml":
+		t, err = ParseTOML(data, r)
+	default:
+		return nil, fmt.Errorf("unsupported theme file extension %q", ext)
+	}
+
+	if err != nil {
+		return nil, fmt.Errorf("could not load theme %q: %w", path, err)
+	}
+
+	r.Register(t)
+
+	return t, nil
+}
@@ -0,0 +1,236 @@
+package theme
+
+import (
+	"errors"
+	"slices"
+	"sync"
+
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/gdamore/tcell"
+)
+
+var (
+	// Default is the default registry. It contains the built-in themes and uses the
+	// "dark" one.
+	Default *Registry
+)
+
+func init() {
+	Default = NewRegistry()
+
+	for _, t := range Builtin() {
+		Default.Register(t)
+	}
+
+	err := Default.Use(DarkName)
+	if err != nil {
+		panic(err)
+	}
+}
+
+// ErrUnknownTheme is the error returned when a theme is not registered.
+var ErrUnknownTheme = errors.New("unknown theme")
+
+// Registry is a set of themes, one of which is the current one.
+type Registry struct {
+	// themes are the registered themes.
+	themes map[string]*Theme
+
+	// current is the current theme.
+	current *Theme
+
+	// listeners are the functions called whenever the current theme changes.
+	listeners []*listener
+
+	// mu is the mutex of the registry.
+	mu sync.RWMutex
+}
+
+// listener is a function called whenever the current theme changes.
+type listener struct {
+	// fn is the function to call.
+	fn func(t *Theme)
+}
+
+// NewRegistry creates a new registry without themes.
+//
+// Returns:
+//   - *Registry: The new registry. Never returns nil.
+func NewRegistry() *Registry {
+	return &Registry{
+		themes: make(map[string]*Theme),
+	}
+}
+
+// Register adds a theme to the registry, replacing any theme with the same name. If
+// the replaced theme is the current one, the new theme becomes the current one.
+// Does nothing with a nil receiver.
+//
+// Parameters:
+//   - t: The theme to add. Nil themes are ignored.
+func (r *Registry) Register(t *Theme) {
+	if r == nil || t == nil {
+		return
+	}
+
+	r.mu.Lock()
+
+	old := r.themes[t.name]
+	r.themes[t.name] = t
+
+	replaced := old != nil && old == r.current
+	if replaced {
+		r.current = t
+	}
+
+	r.mu.Unlock()
+
+	if replaced {
+		r.notify(t)
+	}
+}
+
+// Names returns the names of the registered themes.
+//
+// Returns:
+//   - []string: The names, sorted alphabetically.
+func (r *Registry) Names() []string {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	names := make([]string, 0, len(r.themes))
+
+	for name := range r.themes {
+		names = append(names, name)
+	}
+
+	slices.Sort(names)
+
+	return names
+}
+
+// Get returns a registered theme.
+//
+// Parameters:
+//   - name: The name of the theme.
+//
+// Returns:
+//   - *Theme: The theme. Nil if no theme has that name.
+func (r *Registry) Get(name string) *Theme {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.themes[name]
+}
+
+// Use makes a registered theme the current one and notifies the listeners.
+//
+// Parameters:
+//   - name: The name of the theme.
+//
+// Returns:
+//   - error: An error if the theme could not be used.
+//
+// Errors:
+//   - gcers.NilReceiver: If the receiver is nil.
+//   - ErrUnknownTheme: If no theme has that name.
+func (r *Registry) Use(name string) error {
+	if r == nil {
+		return gcers.NilReceiver
+	}
+
+	r.mu.Lock()
+
+	t, ok := r.themes[name]
+	if !ok {
+		r.mu.Unlock()
+
+		return ErrUnknownTheme
+	}
+
+	r.current = t
+
+	r.mu.Unlock()
+
+	r.notify(t)
+
+	return nil
+}
+
+// Current returns the current theme.
+//
+// Returns:
+//   - *Theme: The current theme. Nil if no theme is used.
+func (r *Registry) Current() *Theme {
+	if r == nil {
+		return nil
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	return r.current
+}
+
+// Style returns the style of a role in the current theme. See Theme.Style.
+//
+// Parameters:
+//   - role: The role.
+//
+// Returns:
+//   - tcell.Style: The style. tcell.StyleDefault if no theme is used.
+func (r *Registry) Style(role Role) tcell.Style {
+	return r.Current().Style(role)
+}
+
+// OnChange registers a function that is called whenever the current theme changes.
+//
+// Parameters:
+//   - fn: The function to call with the new theme. Nil functions are ignored.
+//
+// Returns:
+//   - func(): The function that unregisters fn. Never nil.
+func (r *Registry) OnChange(fn func(t *Theme)) func() {
+	if r == nil || fn == nil {
+		return func() {}
+	}
+
+	l := &listener{
+		fn: fn,
+	}
+
+	r.mu.Lock()
+	r.listeners = append(r.listeners, l)
+	r.mu.Unlock()
+
+	return func() {
+		r.mu.Lock()
+		defer r.mu.Unlock()
+
+		r.listeners = slices.DeleteFunc(r.listeners, func(other *listener) bool {
+			return other == l
+		})
+	}
+}
+
+// notify is a helper method that calls the listeners.
+//
+// Parameters:
+//   - t: The new current theme.
+func (r *Registry) notify(t *Theme) {
+	r.mu.RLock()
+	listeners := slices.Clone(r.listeners)
+	r.mu.RUnlock()
+
+	for _, l := range listeners {
+		l.fn(t)
+	}
+}
@@ -0,0 +1,252 @@
+package theme
+
+import (
+	"maps"
+	"strings"
+	"sync"
+
+	"github.com/gdamore/tcell"
+)
+
+// Role is the semantic role of a style (e.g., "title" or "border.focused"). Roles
+// are hierarchical: the parts of a role are separated by dots and a role that is not
+// defined by a theme falls back to its parent (e.g., "border.focused" falls back to
+// "border" and, then, to RoleDefault).
+type Role string
+
+const (
+	// RoleDefault is the role of the default style. Every other role falls back to it.
+	RoleDefault Role = "default"
+
+	// RoleTitle is the role of titles.
+	RoleTitle Role = "title"
+
+	// RoleText is the role of regular text.
+	RoleText Role = "text"
+
+	// RoleMuted is the role of secondary text, such as placeholders.
+	RoleMuted Role = "text.muted"
+
+	// RoleError is the role of error messages.
+	RoleError Role = "error"
+
+	// RoleWarning is the role of warnings.
+	RoleWarning Role = "warning"
+
+	// RoleSuccess is the role of success messages.
+	RoleSuccess Role = "success"
+
+	// RoleSelection is the role of selected items.
+	RoleSelection Role = "selection"
+
+	// RoleBorder is the role of borders.
+	RoleBorder Role = "border"
+
+	// RoleBorderFocused is the role of the border of the focused element.
+	RoleBorderFocused Role = "border.focused"
+
+	// RoleStatus is the role of status bars.
+	RoleStatus Role = "status"
+
+	// RoleDialog is the role of dialogs.
+	RoleDialog Role = "dialog"
+
+	// RoleDialogFocused is the role of the focused part of a dialog (e.g., the
+	// selected button).
+	RoleDialogFocused Role = "dialog.focused"
+
+	// RoleMenu is the role of menus and dropdowns.
+	RoleMenu Role = "menu"
+
+	// RoleMenuSelected is the role of the selected item of a menu.
+	RoleMenuSelected Role = "menu.selected"
+
+	// RoleInput is the role of input fields.
+	RoleInput Role = "input"
+
+	// RoleProgress is the role of progress bars and gauges: the foreground is the
+	// color of the filled part and the background the color of the track.
+	RoleProgress Role = "progress"
+
+	// RoleChart is the role of the data of charts. The series of a chart use the
+	// "chart.<n>" roles by default, n cycling through ChartColors.
+	RoleChart Role = "chart"
+
+	// RoleToast is the role of notifications. Notifications of a given severity use
+	// the "toast.<severity>" role (e.g., "toast.error").
+	RoleToast Role = "toast"
+
+	// RoleToken is the role of highlighted tokens. Tokens of a given kind use the
+	// "token.<kind>" role (e.g., "token.keyword").
+	RoleToken Role = "token"
+)
+
+// ChartColors is the number of "chart.<n>" roles the built-in themes define.
+const ChartColors int = 4
+
+// Child returns the role that is a child of the role.
+//
+// Parameters:
+//   - name: The name of the child.
+//
+// Returns:
+//   - Role: The child role.
+//
+// Example:
+//
+//	RoleToken.Child("keyword") // "token.keyword"
+func (r Role) Child(name string) Role {
+	return r + "." + Role(name)
+}
+
+// Parent returns the role the role falls back to.
+//
+// Returns:
+//   - Role: The parent role. RoleDefault if the role has no parent.
+//   - bool: False if the role is RoleDefault (or empty), true otherwise.
+func (r Role) Parent() (Role, bool) {
+	if r == RoleDefault || r == "" {
+		return "", false
+	}
+
+	idx := strings.LastIndexByte(string(r), '.')
+	if idx == -1 {
+		return RoleDefault, true
+	}
+
+	return r[:idx], true
+}
+
+// Style returns the style of the role in the current theme of the default registry.
+//
+// Returns:
+//   - tcell.Style: The style.
+func (r Role) Style() tcell.Style {
+	return Default.Style(r)
+}
+
+// Theme is a named mapping from roles to styles.
+type Theme struct {
+	// name is the name of the theme.
+	name string
+
+	// styles are the styles of each role.
+	styles map[Role]tcell.Style
+
+	// mu is the mutex of the theme.
+	mu sync.RWMutex
+}
+
+// NewTheme creates a new theme.
+//
+// Parameters:
+//   - name: The name of the theme.
+//   - styles: The styles of each role. The map is copied.
+//
+// Returns:
+//   - *Theme: The new theme. Never returns nil.
+func NewTheme(name string, styles map[Role]tcell.Style) *Theme {
+	m := make(map[Role]tcell.Style, len(styles))
+	maps.Copy(m, styles)
+
+	return &Theme{
+		name:   name,
+		styles: m,
+	}
+}
+
+// Name returns the name of the theme.
+//
+// Returns:
+//   - string: The name of the theme.
+func (t *Theme) Name() string {
+	if t == nil {
+		return ""
+	}
+
+	return t.name
+}
+
+// Set changes the style of a role. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - role: The role.
+//   - style: The new style.
+func (t *Theme) Set(role Role, style tcell.Style) {
+	if t == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.styles[role] = style
+}
+
+// Lookup returns the style of a role without falling back to its parents.
+//
+// Parameters:
+//   - role: The role.
+//
+// Returns:
+//   - tcell.Style: The style.
+//   - bool: True if the theme defines the role, false otherwise.
+func (t *Theme) Lookup(role Role) (tcell.Style, bool) {
+	if t == nil {
+		return tcell.StyleDefault, false
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	style, ok := t.styles[role]
+	return style, ok
+}
+
+// Style returns the style of a role. If the theme does not define the role, the
+// style of its closest defined parent is returned instead.
+//
+// Parameters:
+//   - role: The role.
+//
+// Returns:
+//   - tcell.Style: The style. tcell.StyleDefault if neither the role nor any of its
+//     parents are defined.
+func (t *Theme) Style(role Role) tcell.Style {
+	if t == nil {
+		return tcell.StyleDefault
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	for ok := true; ok; role, ok = role.Parent() {
+		style, found := t.styles[role]
+		if found {
+			return style
+		}
+	}
+
+	return tcell.StyleDefault
+}
+
+// Roles returns the roles defined by the theme.
+//
+// Returns:
+//   - []Role: The roles, in no particular order.
+func (t *Theme) Roles() []Role {
+	if t == nil {
+		return nil
+	}
+
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+
+	roles := make([]Role, 0, len(t.styles))
+
+	for role := range t.styles {
+		roles = append(roles, role)
+	}
+
+	return roles
+}
@@ -0,0 +1,71 @@
+package theme
+
+import (
+	"testing"
+
+	"github.com/gdamore/tcell"
+)
+
+// TestRoleFallback tests that undefined roles fall back to their parents.
+func TestRoleFallback(t *testing.T) {
+	border := tcell.StyleDefault.Foreground(tcell.ColorRed)
+
+	th := NewTheme("test", map[Role]tcell.Style{
+		RoleDefault: tcell.StyleDefault,
+		RoleBorder:  border,
+	})
+
+	if got := th.Style(RoleBorderFocused); got != border {
+		t.Errorf("want %v, got %v", border, got)
+	}
+
+	if got := th.Style(RoleToken.Child("keyword")); got != tcell.StyleDefault {
+		t.Errorf("want %v, got %v", tcell.StyleDefault, got)
+	}
+}
+
+// TestParseFiles tests that JSON and TOML theme files extend the registered themes.
+func TestParseFiles(t *testing.T) {
+	r := NewRegistry()
+
+	r.Register(NewTheme("base", map[Role]tcell.Style{
+		RoleTitle: tcell.StyleDefault.Bold(true),
+	}))
+
+	tests := []struct {
+		name  string
+		parse func([]byte, *Registry) (*Theme, error)
+		data  string
+	}{
+		{
+			name:  "json",
+			parse: ParseJSON,
+			data:  `{"name": "j", "extends": "base", "styles": {"error": {"fg": "red", "bold": true}}}`,
+		},
+		{
+			name:  "toml",
+			parse: ParseTOML,
+			data:  "name = \"t\"\nextends = \"base\"\n\n[styles.error]\nfg = \"#ff0000\"\nbold = true\n",
+		},
+	}
+
+	wfg, _, wattrs := tcell.StyleDefault.Foreground(tcell.ColorRed).Bold(true).Decompose()
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			th, err := tt.parse([]byte(tt.data), r)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+
+			if got := th.Style(RoleTitle); got != tcell.StyleDefault.Bold(true) {
+				t.Errorf("want the extended title style, got %v", got)
+			}
+
+			fg, _, attrs := th.Style(RoleError).Decompose()
+			if fg.Hex() != wfg.Hex() || attrs != wattrs {
+				t.Errorf("want red bold, got %v", th.Style(RoleError))
+			}
+		})
+	}
+}
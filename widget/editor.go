@@ -0,0 +1,1362 @@
+package widget
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"unicode"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// DefaultUndoLimit is the number of edit groups an editor can undo.
+const DefaultUndoLimit int = 1000
+
+// Highlighter colours the visible lines of an editor. It is called on every draw
+// with the lines that are, at least partly, visible.
+type Highlighter interface {
+	// Highlight returns the style of each character of the given lines.
+	//
+	// Parameters:
+	//   - first: The index of the first line in the text.
+	//   - lines: The lines, without their newline.
+	//
+	// Returns:
+	//   - [][]tcell.Style: One slice per line and one style per character. Missing
+	//     lines or characters keep the style of the editor.
+	Highlight(first int, lines [][]rune) [][]tcell.Style
+}
+
+// HighlighterFunc is a function that implements the Highlighter interface.
+type HighlighterFunc func(first int, lines [][]rune) [][]tcell.Style
+
+// Highlight implements the Highlighter interface.
+func (fn HighlighterFunc) Highlight(first int, lines [][]rune) [][]tcell.Style {
+	return fn(first, lines)
+}
+
+// edit_kind is the kind of an edit, used to group consecutive edits of the same
+// kind into a single undo step.
+type edit_kind int
+
+const (
+	// kind_none is an edit that is never grouped with others.
+	kind_none edit_kind = iota
+
+	// kind_insert is a typed character.
+	kind_insert
+
+	// kind_delete is a character deleted with Backspace or Delete.
+	kind_delete
+)
+
+// edit is a replacement of part of the text.
+type edit struct {
+	// pos is the index where the replacement happened.
+	pos int
+
+	// deleted are the characters that were removed.
+	deleted []rune
+
+	// inserted are the characters that were added.
+	inserted []rune
+}
+
+// edit_group is a sequence of edits that are undone and redone together.
+type edit_group struct {
+	// edits are the edits, in the order they were made.
+	edits []edit
+
+	// before is the cursor before the first edit.
+	before int
+
+	// after is the cursor after the last edit.
+	after int
+}
+
+// visual_row is a row of the editor once the text is laid out.
+type visual_row struct {
+	// start is the index of the first character of the row.
+	start int
+
+	// end is the index after the last character of the row, newline excluded.
+	end int
+
+	// line is the index of the line the row belongs to.
+	line int
+
+	// line_start is the index of the first character of that line.
+	line_start int
+}
+
+// Editor is an embeddable multi-line text editor, meant to be the element of a node
+// of a screen.Screen. The text is kept in a gap buffer and drawn with the same wrap
+// modes and tab stops as screen.TextBox; a Highlighter can re-colour the visible
+// lines (see HighlightWith to use the highlight package).
+//
+// Keys:
+//   - Arrows, Home/End and PgUp/PgDn move the cursor; Ctrl+Left/Right jump by
+//     words and Ctrl+Home/End go to the start or end of the text. With Shift, they
+//     extend the selection. Ctrl+A selects all the text.
+//   - Enter, Tab, Backspace and Delete edit the text.
+//   - Ctrl+C, Ctrl+X and Ctrl+V copy, cut and paste within the editor.
+//   - Ctrl+Z undoes and Ctrl+Y redoes. Consecutive typed or deleted characters are
+//     undone together; see also BeginGroup.
+//
+// With the mouse, a click moves the cursor, a drag selects and the wheel scrolls.
+type Editor struct {
+	// buf is the text.
+	buf gap_buffer
+
+	// cursor is the index of the cursor in the text.
+	cursor int
+
+	// anchor is the index where the selection starts. -1 if there is no selection.
+	anchor int
+
+	// goal is the column kept by vertical moves. -1 for the column of the cursor.
+	goal int
+
+	// wrap is how lines wider than the editor are broken.
+	wrap ds.WrapMode
+
+	// tab_width is the distance between two tab stops.
+	tab_width int
+
+	// top is the index of the first row drawn.
+	top int
+
+	// left is the first column drawn, when lines are not wrapped.
+	left int
+
+	// follow is true if the next draw must scroll to the cursor.
+	follow bool
+
+	// width and height are the size of the last draw.
+	width, height int
+
+	// area is the area of the node of the editor.
+	area ds.Rect
+
+	// cursor_x and cursor_y are the position of the cursor in the last draw. -1
+	// if the cursor was not visible.
+	cursor_x, cursor_y int
+
+	// undo and redo are the undo and redo histories, from the oldest to the newest.
+	undo, redo []edit_group
+
+	// undo_limit is the maximum number of groups in the undo history.
+	undo_limit int
+
+	// group_depth is the number of BeginGroup calls without a matching EndGroup.
+	group_depth int
+
+	// pending is the group being built while group_depth is positive.
+	pending *edit_group
+
+	// last_kind is the kind of the last edit, or kind_none if the cursor moved
+	// since.
+	last_kind edit_kind
+
+	// clipboard is the text of the last copy or cut.
+	clipboard []rune
+
+	// highlighter colours the visible lines. Nil for none.
+	highlighter Highlighter
+
+	// on_change is called whenever the text changes.
+	on_change func(text string)
+
+	// dragging is true while a mouse selection is in progress.
+	dragging bool
+
+	// focused is true if the editor has the focus.
+	focused bool
+
+	// mu is the mutex of the editor.
+	mu sync.Mutex
+}
+
+// NewEditor creates a new, empty editor that wraps lines at words.
+//
+// Returns:
+//   - *Editor: The new editor. Never returns nil.
+func NewEditor() *Editor {
+	return &Editor{
+		anchor:     -1,
+		goal:       -1,
+		wrap:       ds.WrapWord,
+		tab_width:  ds.DefaultTabWidth,
+		undo_limit: DefaultUndoLimit,
+		cursor_x:   -1,
+		cursor_y:   -1,
+	}
+}
+
+// Text returns the text of the editor.
+//
+// Returns:
+//   - string: The text.
+func (e *Editor) Text() string {
+	if e == nil {
+		return ""
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return string(e.buf.Runes())
+}
+
+// SetText replaces the text of the editor, moves the cursor to its start and clears
+// the undo history. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - text: The new text.
+func (e *Editor) SetText(text string) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+
+	e.buf = gap_buffer{}
+	e.buf.Insert(0, []rune(text))
+
+	e.cursor = 0
+	e.anchor = -1
+	e.goal = -1
+	e.top = 0
+	e.left = 0
+	e.follow = true
+
+	e.undo = nil
+	e.redo = nil
+	e.pending = nil
+	e.group_depth = 0
+	e.last_kind = kind_none
+
+	fn := e.changed()
+
+	e.mu.Unlock()
+
+	fn()
+}
+
+// InsertText replaces the selection, if any, with the given text at the cursor.
+// The insertion can be undone. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - text: The text to insert.
+func (e *Editor) InsertText(text string) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+
+	from, to := e.cursor, e.cursor
+
+	if start, end, ok := e.selection(); ok {
+		from, to = start, end
+	}
+
+	e.replace(from, to, []rune(text), kind_none)
+	fn := e.changed()
+
+	e.mu.Unlock()
+
+	fn()
+}
+
+// Selection returns the selected text.
+//
+// Returns:
+//   - string: The selected text. Empty if there is no selection.
+func (e *Editor) Selection() string {
+	if e == nil {
+		return ""
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	from, to, ok := e.selection()
+	if !ok {
+		return ""
+	}
+
+	return string(e.buf.Slice(from, to))
+}
+
+// Position returns the position of the cursor in the text.
+//
+// Returns:
+//   - int: The index of the line, starting from 0.
+//   - int: The index of the character in the line, starting from 0.
+func (e *Editor) Position() (int, int) {
+	if e == nil {
+		return 0, 0
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	line, col := 0, 0
+
+	for i := 0; i < e.cursor; i++ {
+		if e.buf.At(i) == '\n' {
+			line++
+			col = 0
+		} else {
+			col++
+		}
+	}
+
+	return line, col
+}
+
+// SetWrap changes how lines wider than the editor are broken. Does nothing with a
+// nil receiver.
+//
+// Parameters:
+//   - mode: The wrap mode. With screen.WrapNone, the editor scrolls horizontally.
+func (e *Editor) SetWrap(mode ds.WrapMode) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+
+	e.wrap = mode
+	e.left = 0
+	e.follow = true
+
+	e.mu.Unlock()
+}
+
+// SetTabWidth changes the distance between two tab stops. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - width: The distance. Non-positive values mean screen.DefaultTabWidth.
+func (e *Editor) SetTabWidth(width int) {
+	if e == nil {
+		return
+	}
+
+	if width <= 0 {
+		width = ds.DefaultTabWidth
+	}
+
+	e.mu.Lock()
+	e.tab_width = width
+	e.mu.Unlock()
+}
+
+// SetHighlighter sets the highlighter that colours the visible lines. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - h: The highlighter. Nil for none.
+func (e *Editor) SetHighlighter(h Highlighter) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	e.highlighter = h
+	e.mu.Unlock()
+}
+
+// SetUndoLimit changes the number of edit groups that can be undone. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - limit: The number of groups. Non-positive values disable the history.
+func (e *Editor) SetUndoLimit(limit int) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+
+	e.undo_limit = max(limit, 0)
+	e.trim_undo()
+
+	e.mu.Unlock()
+}
+
+// OnChange sets the function called whenever the text changes. Does nothing with a
+// nil receiver.
+//
+// Parameters:
+//   - fn: The function. Nil for none.
+func (e *Editor) OnChange(fn func(text string)) {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	e.on_change = fn
+	e.mu.Unlock()
+}
+
+// BeginGroup starts a group of edits that are undone and redone together, until the
+// matching EndGroup. Groups can be nested; only the outermost one counts. Does
+// nothing with a nil receiver.
+func (e *Editor) BeginGroup() {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+
+	if e.group_depth == 0 {
+		e.pending = &edit_group{
+			before: e.cursor,
+		}
+	}
+
+	e.group_depth++
+
+	e.mu.Unlock()
+}
+
+// EndGroup ends the group of edits started by BeginGroup. Does nothing with a nil
+// receiver or if no group was started.
+func (e *Editor) EndGroup() {
+	if e == nil {
+		return
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.group_depth == 0 {
+		return
+	}
+
+	e.group_depth--
+
+	if e.group_depth > 0 {
+		return
+	}
+
+	group := e.pending
+	e.pending = nil
+	e.last_kind = kind_none
+
+	if len(group.edits) == 0 {
+		return
+	}
+
+	group.after = e.cursor
+	e.push_undo(*group)
+}
+
+// Undo undoes the last group of edits.
+//
+// Returns:
+//   - bool: True if something was undone.
+func (e *Editor) Undo() bool {
+	if e == nil {
+		return false
+	}
+
+	e.mu.Lock()
+
+	ok := e.undo_step()
+
+	var fn func()
+
+	if ok {
+		fn = e.changed()
+	}
+
+	e.mu.Unlock()
+
+	if fn != nil {
+		fn()
+	}
+
+	return ok
+}
+
+// Redo redoes the last group of edits that was undone.
+//
+// Returns:
+//   - bool: True if something was redone.
+func (e *Editor) Redo() bool {
+	if e == nil {
+		return false
+	}
+
+	e.mu.Lock()
+
+	ok := e.redo_step()
+
+	var fn func()
+
+	if ok {
+		fn = e.changed()
+	}
+
+	e.mu.Unlock()
+
+	if fn != nil {
+		fn()
+	}
+
+	return ok
+}
+
+// changed is a helper method that marks the text as changed and returns the
+// function that notifies the change listener, to be called once unlocked.
+//
+// Returns:
+//   - func(): The function. Never nil.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) changed() func() {
+	e.follow = true
+
+	fn := e.on_change
+	if fn == nil {
+		return func() {}
+	}
+
+	text := string(e.buf.Runes())
+
+	return func() {
+		fn(text)
+	}
+}
+
+// selection is a helper method that returns the selected range.
+//
+// Returns:
+//   - int: The start of the selection.
+//   - int: The end of the selection (exclusive).
+//   - bool: False if there is no selection.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) selection() (int, int, bool) {
+	if e.anchor == -1 || e.anchor == e.cursor {
+		return 0, 0, false
+	}
+
+	return min(e.anchor, e.cursor), max(e.anchor, e.cursor), true
+}
+
+// replace is a helper method that replaces part of the text, records the edit in
+// the undo history and places the cursor after the inserted characters.
+//
+// Parameters:
+//   - from: The start of the range to replace.
+//   - to: The end of the range to replace (exclusive).
+//   - chars: The characters to insert.
+//   - kind: The kind of the edit.
+//
+// Assertions:
+//   - e.mu is locked.
+//   - 0 <= from <= to <= e.buf.Len().
+func (e *Editor) replace(from, to int, chars []rune, kind edit_kind) {
+	before := e.cursor
+
+	ed := edit{
+		pos:      from,
+		deleted:  e.buf.Delete(from, to),
+		inserted: slices.Clone(chars),
+	}
+
+	e.buf.Insert(from, chars)
+
+	e.cursor = from + len(chars)
+	e.anchor = -1
+	e.goal = -1
+
+	e.record(ed, kind, before)
+}
+
+// record is a helper method that adds an edit to the undo history, either to the
+// current group or as a new one, and clears the redo history.
+//
+// Parameters:
+//   - ed: The edit.
+//   - kind: The kind of the edit.
+//   - before: The cursor before the edit.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) record(ed edit, kind edit_kind, before int) {
+	e.redo = nil
+
+	if e.pending != nil {
+		e.pending.edits = append(e.pending.edits, ed)
+		return
+	}
+
+	if kind != kind_none && kind == e.last_kind && len(e.undo) > 0 {
+		last := &e.undo[len(e.undo)-1]
+		prev := last.edits[len(last.edits)-1]
+
+		var follows bool
+
+		if kind == kind_insert {
+			follows = prev.pos+len(prev.inserted) == ed.pos
+		} else {
+			// Backspace deletes before the previous deletion; Delete at the same
+			// place.
+			follows = ed.pos+len(ed.deleted) == prev.pos || ed.pos == prev.pos
+		}
+
+		if follows {
+			last.edits = append(last.edits, ed)
+			last.after = e.cursor
+
+			return
+		}
+	}
+
+	e.last_kind = kind
+
+	e.push_undo(edit_group{
+		edits:  []edit{ed},
+		before: before,
+		after:  e.cursor,
+	})
+}
+
+// push_undo is a helper method that adds a group to the undo history.
+//
+// Parameters:
+//   - group: The group.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) push_undo(group edit_group) {
+	e.undo = append(e.undo, group)
+	e.trim_undo()
+}
+
+// trim_undo is a helper method that drops the oldest groups of the undo history
+// beyond the limit.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) trim_undo() {
+	if extra := len(e.undo) - e.undo_limit; extra > 0 {
+		e.undo = slices.Delete(e.undo, 0, extra)
+	}
+}
+
+// undo_step is a helper method that undoes the last group of edits.
+//
+// Returns:
+//   - bool: True if something was undone.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) undo_step() bool {
+	if len(e.undo) == 0 || e.pending != nil {
+		return false
+	}
+
+	group := e.undo[len(e.undo)-1]
+	e.undo = e.undo[:len(e.undo)-1]
+
+	for _, ed := range slices.Backward(group.edits) {
+		e.buf.Delete(ed.pos, ed.pos+len(ed.inserted))
+		e.buf.Insert(ed.pos, ed.deleted)
+	}
+
+	e.redo = append(e.redo, group)
+
+	e.cursor = group.before
+	e.anchor = -1
+	e.goal = -1
+	e.last_kind = kind_none
+
+	return true
+}
+
+// redo_step is a helper method that redoes the last group of edits that was
+// undone.
+//
+// Returns:
+//   - bool: True if something was redone.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) redo_step() bool {
+	if len(e.redo) == 0 || e.pending != nil {
+		return false
+	}
+
+	group := e.redo[len(e.redo)-1]
+	e.redo = e.redo[:len(e.redo)-1]
+
+	for _, ed := range group.edits {
+		e.buf.Delete(ed.pos, ed.pos+len(ed.deleted))
+		e.buf.Insert(ed.pos, ed.inserted)
+	}
+
+	e.undo = append(e.undo, group)
+
+	e.cursor = group.after
+	e.anchor = -1
+	e.goal = -1
+	e.last_kind = kind_none
+
+	return true
+}
+
+// move is a helper method that moves the cursor, extending the selection or
+// clearing it.
+//
+// Parameters:
+//   - idx: The new index of the cursor.
+//   - extend: True to extend the selection.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) move(idx int, extend bool) {
+	if extend {
+		if e.anchor == -1 {
+			e.anchor = e.cursor
+		}
+	} else {
+		e.anchor = -1
+	}
+
+	e.cursor = max(min(idx, e.buf.Len()), 0)
+	e.last_kind = kind_none
+	e.follow = true
+}
+
+// word_start is a helper method that returns the index of the start of the word
+// before the given index.
+//
+// Parameters:
+//   - idx: The index.
+//
+// Returns:
+//   - int: The start of the word.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) word_start(idx int) int {
+	for idx > 0 && unicode.IsSpace(e.buf.At(idx-1)) {
+		idx--
+	}
+
+	for idx > 0 && !unicode.IsSpace(e.buf.At(idx-1)) {
+		idx--
+	}
+
+	return idx
+}
+
+// word_end is a helper method that returns the index of the end of the word after
+// the given index.
+//
+// Parameters:
+//   - idx: The index.
+//
+// Returns:
+//   - int: The end of the word.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) word_end(idx int) int {
+	n := e.buf.Len()
+
+	for idx < n && unicode.IsSpace(e.buf.At(idx)) {
+		idx++
+	}
+
+	for idx < n && !unicode.IsSpace(e.buf.At(idx)) {
+		idx++
+	}
+
+	return idx
+}
+
+// cell_width is a helper method that returns the number of cells a character
+// takes at the given column.
+//
+// Parameters:
+//   - c: The character.
+//   - col: The column of the character.
+//
+// Returns:
+//   - int: The number of cells.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) cell_width(c rune, col int) int {
+	if c == '\t' {
+		return e.next_tab_stop(col) - col
+	}
+
+	return 1
+}
+
+// next_tab_stop is a helper method that returns the column of the tab stop after
+// the given column.
+//
+// Parameters:
+//   - col: The current column.
+//
+// Returns:
+//   - int: The column of the next tab stop.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) next_tab_stop(col int) int {
+	return (col/e.tab_width + 1) * e.tab_width
+}
+
+// layout is a helper method that splits the text into rows that fit the given
+// width, according to the wrap mode. The lines are wrapped as by screen.TextBox
+// (see screen.WrapRows).
+//
+// Parameters:
+//   - text: The text.
+//   - width: The width of the editor. Non-positive values do not wrap.
+//
+// Returns:
+//   - []visual_row: The rows. Never empty.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) layout(text []rune, width int) []visual_row {
+	var rows []visual_row
+
+	line := 0
+	line_start := 0
+
+	for line_start <= len(text) {
+		line_end := line_start
+
+		for line_end < len(text) && text[line_end] != '\n' {
+			line_end++
+		}
+
+		for _, row := range ds.WrapRows(text[line_start:line_end], width, e.wrap, e.next_tab_stop) {
+			rows = append(rows, visual_row{
+				start:      line_start + row.Start,
+				end:        line_start + row.End,
+				line:       line,
+				line_start: line_start,
+			})
+		}
+
+		line++
+		line_start = line_end + 1
+	}
+
+	return rows
+}
+
+// columns is a helper method that returns the number of cells the given characters
+// take at the start of a row.
+//
+// Parameters:
+//   - chars: The characters.
+//
+// Returns:
+//   - int: The number of cells.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) columns(chars []rune) int {
+	col := 0
+
+	for _, c := range chars {
+		col += e.cell_width(c, col)
+	}
+
+	return col
+}
+
+// row_of is a helper function that returns the index of the row that holds the
+// given index of the text.
+//
+// Parameters:
+//   - rows: The rows.
+//   - idx: The index of the text.
+//
+// Returns:
+//   - int: The index of the row.
+func row_of(rows []visual_row, idx int) int {
+	i := sort.Search(len(rows), func(i int) bool {
+		return rows[i].start > idx
+	})
+
+	return max(i-1, 0)
+}
+
+// index_at is a helper method that returns the index of the text closest to the
+// given column of a row.
+//
+// Parameters:
+//   - text: The text.
+//   - row: The row.
+//   - goal: The column.
+//
+// Returns:
+//   - int: The index of the text.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) index_at(text []rune, row visual_row, goal int) int {
+	col := 0
+
+	for i := row.start; i < row.end; i++ {
+		w := e.cell_width(text[i], col)
+		if col+w > goal {
+			return i
+		}
+
+		col += w
+	}
+
+	return row.end
+}
+
+// move_rows is a helper method that moves the cursor by a number of rows, keeping
+// its column.
+//
+// Parameters:
+//   - delta: The number of rows. Positive values move down.
+//   - extend: True to extend the selection.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) move_rows(delta int, extend bool) {
+	text := e.buf.Runes()
+	rows := e.layout(text, e.wrap_width())
+
+	cur := row_of(rows, e.cursor)
+	goal := e.goal
+
+	if goal == -1 {
+		goal = e.columns(text[rows[cur].start:e.cursor])
+	}
+
+	target := max(min(cur+delta, len(rows)-1), 0)
+
+	e.move(e.index_at(text, rows[target], goal), extend)
+	e.goal = goal
+}
+
+// wrap_width is a helper method that returns the width used to lay out the text:
+// the width of the last draw.
+//
+// Returns:
+//   - int: The width. 0 if the text is not wrapped.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) wrap_width() int {
+	if e.wrap == ds.WrapNone {
+		return 0
+	}
+
+	return e.width
+}
+
+// HandleEvent implements the screen.Handler interface.
+func (e *Editor) HandleEvent(ev tcell.Event) bool {
+	e.mu.Lock()
+
+	var changed, consumed bool
+
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		changed, consumed = e.handle_key(ev)
+	case *tcell.EventMouse:
+		consumed = e.handle_mouse(ev)
+	}
+
+	var fn func()
+
+	if changed {
+		fn = e.changed()
+	}
+
+	e.mu.Unlock()
+
+	if fn != nil {
+		fn()
+	}
+
+	return consumed
+}
+
+// handle_key is a helper method that edits the text according to a key.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the text changed.
+//   - bool: True if the key was consumed.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) handle_key(ev *tcell.EventKey) (bool, bool) {
+	mods := ev.Modifiers()
+	extend := mods&tcell.ModShift != 0
+	ctrl := mods&(tcell.ModCtrl|tcell.ModAlt) != 0
+
+	from, to, has_selection := e.selection()
+
+	switch ev.Key() {
+	case tcell.KeyRune:
+		return e.type_chars([]rune{ev.Rune()}, kind_insert), true
+	case tcell.KeyTab:
+		return e.type_chars([]rune{'\t'}, kind_insert), true
+	case tcell.KeyEnter:
+		return e.type_chars([]rune{'\n'}, kind_none), true
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		switch {
+		case has_selection:
+			e.replace(from, to, nil, kind_none)
+		case e.cursor > 0:
+			e.replace(e.cursor-1, e.cursor, nil, kind_delete)
+		default:
+			return false, true
+		}
+
+		return true, true
+	case tcell.KeyDelete:
+		switch {
+		case has_selection:
+			e.replace(from, to, nil, kind_none)
+		case e.cursor < e.buf.Len():
+			e.replace(e.cursor, e.cursor+1, nil, kind_delete)
+		default:
+			return false, true
+		}
+
+		return true, true
+	case tcell.KeyLeft:
+		switch {
+		case ctrl:
+			e.move(e.word_start(e.cursor), extend)
+		case has_selection && !extend:
+			e.move(from, false)
+		default:
+			e.move(e.cursor-1, extend)
+		}
+	case tcell.KeyRight:
+		switch {
+		case ctrl:
+			e.move(e.word_end(e.cursor), extend)
+		case has_selection && !extend:
+			e.move(to, false)
+		default:
+			e.move(e.cursor+1, extend)
+		}
+	case tcell.KeyUp:
+		e.move_rows(-1, extend)
+	case tcell.KeyDown:
+		e.move_rows(1, extend)
+	case tcell.KeyPgUp:
+		e.move_rows(-max(e.height-1, 1), extend)
+	case tcell.KeyPgDn:
+		e.move_rows(max(e.height-1, 1), extend)
+	case tcell.KeyHome, tcell.KeyEnd:
+		if ctrl {
+			if ev.Key() == tcell.KeyHome {
+				e.move(0, extend)
+			} else {
+				e.move(e.buf.Len(), extend)
+			}
+
+			break
+		}
+
+		text := e.buf.Runes()
+		rows := e.layout(text, e.wrap_width())
+		row := rows[row_of(rows, e.cursor)]
+
+		if ev.Key() == tcell.KeyHome {
+			e.move(row.start, extend)
+		} else {
+			e.move(row.end, extend)
+		}
+	case tcell.KeyCtrlA:
+		e.anchor = 0
+		e.cursor = e.buf.Len()
+		e.last_kind = kind_none
+	case tcell.KeyCtrlC, tcell.KeyCtrlX:
+		if !has_selection {
+			return false, true
+		}
+
+		e.clipboard = e.buf.Slice(from, to)
+
+		if ev.Key() == tcell.KeyCtrlC {
+			return false, true
+		}
+
+		e.replace(from, to, nil, kind_none)
+
+		return true, true
+	case tcell.KeyCtrlV:
+		if len(e.clipboard) == 0 {
+			return false, true
+		}
+
+		return e.type_chars(e.clipboard, kind_none), true
+	case tcell.KeyCtrlZ:
+		return e.undo_step(), true
+	case tcell.KeyCtrlY:
+		return e.redo_step(), true
+	default:
+		return false, false
+	}
+
+	return false, true
+}
+
+// type_chars is a helper method that replaces the selection, if any, with the
+// given characters at the cursor.
+//
+// Parameters:
+//   - chars: The characters.
+//   - kind: The kind of the edit.
+//
+// Returns:
+//   - bool: True if the text changed.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) type_chars(chars []rune, kind edit_kind) bool {
+	from, to, ok := e.selection()
+	if ok {
+		// Replacing a selection is never grouped with typing.
+		kind = kind_none
+	} else {
+		from, to = e.cursor, e.cursor
+	}
+
+	e.replace(from, to, chars, kind)
+
+	return true
+}
+
+// handle_mouse is a helper method that moves the cursor, selects or scrolls
+// according to a mouse event.
+//
+// Parameters:
+//   - ev: The mouse event.
+//
+// Returns:
+//   - bool: True if the event was consumed.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) handle_mouse(ev *tcell.EventMouse) bool {
+	buttons := ev.Buttons()
+
+	switch {
+	case buttons&tcell.WheelUp != 0:
+		e.top = max(e.top-3, 0)
+		e.follow = false
+	case buttons&tcell.WheelDown != 0:
+		e.top += 3
+		e.follow = false
+	case buttons&tcell.Button1 != 0:
+		x, y := ev.Position()
+
+		text := e.buf.Runes()
+		rows := e.layout(text, e.wrap_width())
+
+		row := max(min(e.top+y-e.area.Y, len(rows)-1), 0)
+		idx := e.index_at(text, rows[row], e.left+x-e.area.X)
+
+		e.move(idx, e.dragging)
+
+		if !e.dragging {
+			e.anchor = e.cursor
+			e.dragging = true
+		}
+	default:
+		if !e.dragging {
+			return false
+		}
+
+		e.dragging = false
+	}
+
+	return true
+}
+
+// SetFocused implements the screen.Focuser interface.
+func (e *Editor) SetFocused(focused bool) {
+	e.mu.Lock()
+	e.focused = focused
+	e.mu.Unlock()
+}
+
+// SetArea implements the screen.Arranger interface.
+func (e *Editor) SetArea(area ds.Rect) {
+	e.mu.Lock()
+	e.area = area
+	e.mu.Unlock()
+}
+
+// Cursor implements the screen.Cursorer interface.
+func (e *Editor) Cursor() (int, int, ds.CursorShape, bool) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.cursor_x, e.cursor_y, ds.CursorBar, e.focused && e.cursor_x >= 0
+}
+
+// Draw implements the screen.Drawer interface. The editor fills the table and
+// scrolls to keep the cursor visible after it moves.
+func (e *Editor) Draw(table *dtb.Table, x, y *int) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	width, height := table.Width(), table.Height()
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	e.width, e.height = width, height
+
+	text := e.buf.Runes()
+	rows := e.layout(text, e.wrap_width())
+
+	cur := row_of(rows, e.cursor)
+	cur_col := e.columns(text[rows[cur].start:e.cursor])
+
+	if e.follow {
+		if cur < e.top {
+			e.top = cur
+		} else if cur >= e.top+height {
+			e.top = cur - height + 1
+		}
+
+		if e.wrap == ds.WrapNone {
+			if cur_col < e.left {
+				e.left = cur_col
+			} else if cur_col >= e.left+width {
+				e.left = cur_col - width + 1
+			}
+		} else {
+			e.left = 0
+		}
+
+		e.follow = false
+	}
+
+	e.top = max(min(e.top, len(rows)-1), 0)
+
+	visible := rows[e.top:min(e.top+height, len(rows))]
+	styles, first := e.highlight(text, visible)
+
+	style := theme.RoleInput.Style()
+	selection_style := theme.RoleSelection.Style()
+	from, to, has_selection := e.selection()
+
+	for row := 0; row < height; row++ {
+		for col := 0; col < width; col++ {
+			table.WriteAt(col, row, dtb.NewCell(' ', style))
+		}
+	}
+
+	for i, row := range visible {
+		col := 0
+
+		for idx := row.start; idx < row.end; idx++ {
+			c := text[idx]
+			w := e.cell_width(c, col)
+
+			st := style
+
+			if line := row.line - first; line < len(styles) && idx-row.line_start < len(styles[line]) {
+				st = styles[line][idx-row.line_start]
+			}
+
+			if has_selection && idx >= from && idx < to {
+				st = selection_style
+			}
+
+			if c == '\t' {
+				c = ' '
+			}
+
+			for k := range w {
+				table.WriteAt(col+k-e.left, i, dtb.NewCell(c, st))
+			}
+
+			col += w
+		}
+
+		// A selected newline is shown as a selected cell after the row.
+		if has_selection && row.end >= from && row.end < to && row.end < len(text) && text[row.end] == '\n' {
+			table.WriteAt(col-e.left, i, dtb.NewCell(' ', selection_style))
+		}
+	}
+
+	e.cursor_x, e.cursor_y = -1, -1
+
+	if cur >= e.top && cur < e.top+height {
+		e.cursor_x = min(cur_col-e.left, width-1)
+		e.cursor_y = cur - e.top
+	}
+
+	return nil
+}
+
+// highlight is a helper method that asks the highlighter for the styles of the
+// lines of the given rows.
+//
+// Parameters:
+//   - text: The text.
+//   - rows: The visible rows.
+//
+// Returns:
+//   - [][]tcell.Style: The styles of the lines. Nil if there is no highlighter.
+//   - int: The index of the first line.
+//
+// Assertions:
+//   - e.mu is locked.
+func (e *Editor) highlight(text []rune, rows []visual_row) ([][]tcell.Style, int) {
+	if e.highlighter == nil || len(rows) == 0 {
+		return nil, 0
+	}
+
+	var lines [][]rune
+
+	for i, row := range rows {
+		if i > 0 && row.line == rows[i-1].line {
+			continue
+		}
+
+		end := row.line_start
+
+		for end < len(text) && text[end] != '\n' {
+			end++
+		}
+
+		lines = append(lines, text[row.line_start:end])
+	}
+
+	first := rows[0].line
+
+	return e.highlighter.Highlight(first, lines), first
+}
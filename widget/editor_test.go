@@ -0,0 +1,159 @@
+package widget
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+func TestGapBuffer(t *testing.T) {
+	var gb gap_buffer
+	var want []rune
+
+	rng := rand.New(rand.NewSource(1))
+
+	for range 500 {
+		if len(want) > 0 && rng.Intn(3) == 0 {
+			from := rng.Intn(len(want))
+			to := from + rng.Intn(len(want)-from+1)
+
+			deleted := gb.Delete(from, to)
+			if !slices.Equal(deleted, want[from:to]) {
+				t.Fatalf("Expected to delete %q, but got %q", string(want[from:to]), string(deleted))
+			}
+
+			want = slices.Delete(want, from, to)
+		} else {
+			pos := rng.Intn(len(want) + 1)
+			chars := []rune("abcdefghij"[:rng.Intn(10)+1])
+
+			gb.Insert(pos, chars)
+			want = slices.Insert(want, pos, chars...)
+		}
+
+		if got := gb.Runes(); !slices.Equal(got, want) {
+			t.Fatalf("Expected %q, but got %q", string(want), string(got))
+		}
+	}
+}
+
+func TestEditorUndoRedo(t *testing.T) {
+	ed := NewEditor()
+
+	for _, r := range "hello" {
+		ed.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+
+	ed.HandleEvent(tcell.NewEventKey(tcell.KeyEnter, 0, tcell.ModNone))
+
+	for _, r := range "world" {
+		ed.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+
+	ed.HandleEvent(tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone))
+	ed.HandleEvent(tcell.NewEventKey(tcell.KeyBackspace2, 0, tcell.ModNone))
+
+	if got := ed.Text(); got != "hello\nwor" {
+		t.Fatalf("Expected %q, but got %q", "hello\nwor", got)
+	}
+
+	steps := []string{"hello\nworld", "hello\n", "hello", ""}
+
+	for _, want := range steps {
+		if !ed.Undo() {
+			t.Fatalf("Expected an undo step")
+		}
+
+		if got := ed.Text(); got != want {
+			t.Fatalf("Expected %q after undo, but got %q", want, got)
+		}
+	}
+
+	if ed.Undo() {
+		t.Fatalf("Expected no more undo steps")
+	}
+
+	ed.Redo()
+	ed.Redo()
+
+	if got := ed.Text(); got != "hello\n" {
+		t.Fatalf("Expected %q after redo, but got %q", "hello\n", got)
+	}
+
+	ed.BeginGroup()
+	ed.InsertText("a")
+	ed.InsertText("b")
+	ed.EndGroup()
+
+	ed.Undo()
+
+	if got := ed.Text(); got != "hello\n" {
+		t.Fatalf("Expected the group to be undone at once, but got %q", got)
+	}
+}
+
+func TestEditorWrapAndHighlight(t *testing.T) {
+	ed := NewEditor()
+	ed.SetText("one two three\nx")
+	ed.SetFocused(true)
+
+	var first_line int
+	var lines []string
+
+	ed.SetHighlighter(HighlighterFunc(func(first int, chars [][]rune) [][]tcell.Style {
+		first_line = first
+		lines = lines[:0]
+
+		for _, line := range chars {
+			lines = append(lines, string(line))
+		}
+
+		return nil
+	}))
+
+	ed.HandleEvent(tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModCtrl))
+
+	table, err := dtb.NewTable(8, 2)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	x, y := 0, 0
+
+	err = ed.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	want := []string{"three   ", "x       "}
+
+	if got := table.GetLines(); !slices.Equal(got, want) {
+		t.Fatalf("Expected %q, but got %q", want, got)
+	}
+
+	if first_line != 0 || !slices.Equal(lines, []string{"one two three", "x"}) {
+		t.Fatalf("Expected the visible lines, but got %d %q", first_line, lines)
+	}
+
+	if cx, cy, _, ok := ed.Cursor(); !ok || cx != 1 || cy != 1 {
+		t.Fatalf("Expected the cursor at (1, 1), but got (%d, %d)", cx, cy)
+	}
+
+	ed.HandleEvent(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModShift))
+
+	if got := ed.Selection(); got != "hree\nx" {
+		t.Fatalf("Expected %q to be selected, but got %q", "hree\nx", got)
+	}
+
+	ed.SetArea(ds.Rect{X: 10, Y: 5, Width: 8, Height: 2})
+	ed.HandleEvent(tcell.NewEventMouse(10, 5, tcell.Button1, tcell.ModNone))
+	ed.HandleEvent(tcell.NewEventMouse(0, 0, tcell.ButtonNone, tcell.ModNone))
+
+	if line, col := ed.Position(); line != 0 || col != 8 {
+		t.Fatalf("Expected the cursor at line 0, column 8, but got %d, %d", line, col)
+	}
+}
@@ -0,0 +1,155 @@
+package widget
+
+// min_gap is the minimum size of the gap of a gap buffer after it grows.
+const min_gap int = 64
+
+// gap_buffer is a sequence of characters with a gap at the position of the last
+// edit, so that consecutive edits around the same position do not move the rest of
+// the text.
+type gap_buffer struct {
+	// data holds the characters before the gap, the gap and the characters after
+	// it.
+	data []rune
+
+	// gap_start is the index of the first cell of the gap.
+	gap_start int
+
+	// gap_end is the index of the first character after the gap.
+	gap_end int
+}
+
+// Len returns the number of characters in the buffer.
+//
+// Returns:
+//   - int: The number of characters.
+func (gb *gap_buffer) Len() int {
+	return len(gb.data) - (gb.gap_end - gb.gap_start)
+}
+
+// At returns the character at the given index.
+//
+// Parameters:
+//   - idx: The index. Assumed to be in [0, Len()).
+//
+// Returns:
+//   - rune: The character.
+func (gb *gap_buffer) At(idx int) rune {
+	if idx >= gb.gap_start {
+		idx += gb.gap_end - gb.gap_start
+	}
+
+	return gb.data[idx]
+}
+
+// Slice returns a copy of the characters in the given range.
+//
+// Parameters:
+//   - from: The start of the range.
+//   - to: The end of the range (exclusive).
+//
+// Returns:
+//   - []rune: The characters.
+//
+// Assertions:
+//   - 0 <= from <= to <= Len().
+func (gb *gap_buffer) Slice(from, to int) []rune {
+	chars := make([]rune, 0, to-from)
+
+	if from < gb.gap_start {
+		chars = append(chars, gb.data[from:min(to, gb.gap_start)]...)
+	}
+
+	if to > gb.gap_start {
+		gap := gb.gap_end - gb.gap_start
+		chars = append(chars, gb.data[max(from, gb.gap_start)+gap:to+gap]...)
+	}
+
+	return chars
+}
+
+// Runes returns a copy of all the characters of the buffer.
+//
+// Returns:
+//   - []rune: The characters.
+func (gb *gap_buffer) Runes() []rune {
+	return gb.Slice(0, gb.Len())
+}
+
+// Insert inserts characters at the given index.
+//
+// Parameters:
+//   - idx: The index. Assumed to be in [0, Len()].
+//   - chars: The characters to insert.
+func (gb *gap_buffer) Insert(idx int, chars []rune) {
+	if len(chars) == 0 {
+		return
+	}
+
+	gb.move_gap(idx)
+	gb.grow(len(chars))
+
+	copy(gb.data[gb.gap_start:], chars)
+	gb.gap_start += len(chars)
+}
+
+// Delete deletes the characters in the given range.
+//
+// Parameters:
+//   - from: The start of the range.
+//   - to: The end of the range (exclusive).
+//
+// Returns:
+//   - []rune: The deleted characters.
+//
+// Assertions:
+//   - 0 <= from <= to <= Len().
+func (gb *gap_buffer) Delete(from, to int) []rune {
+	deleted := gb.Slice(from, to)
+
+	gb.move_gap(from)
+	gb.gap_end += to - from
+
+	return deleted
+}
+
+// move_gap is a helper method that moves the gap to the given index.
+//
+// Parameters:
+//   - idx: The index. Assumed to be in [0, Len()].
+func (gb *gap_buffer) move_gap(idx int) {
+	switch {
+	case idx < gb.gap_start:
+		n := gb.gap_start - idx
+
+		copy(gb.data[gb.gap_end-n:gb.gap_end], gb.data[idx:gb.gap_start])
+		gb.gap_start -= n
+		gb.gap_end -= n
+	case idx > gb.gap_start:
+		n := idx - gb.gap_start
+
+		copy(gb.data[gb.gap_start:gb.gap_start+n], gb.data[gb.gap_end:gb.gap_end+n])
+		gb.gap_start += n
+		gb.gap_end += n
+	}
+}
+
+// grow is a helper method that makes sure the gap can hold the given number of
+// characters.
+//
+// Parameters:
+//   - n: The number of characters.
+func (gb *gap_buffer) grow(n int) {
+	if gb.gap_end-gb.gap_start >= n {
+		return
+	}
+
+	size := len(gb.data) + n + max(len(gb.data), min_gap)
+	after := len(gb.data) - gb.gap_end
+
+	data := make([]rune, size)
+	copy(data, gb.data[:gb.gap_start])
+	copy(data[size-after:], gb.data[gb.gap_end:])
+
+	gb.data = data
+	gb.gap_end = size - after
+}
@@ -0,0 +1,918 @@
+package widget
+
+import (
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// DefaultColumnWidth is the width of the columns of a grid that do not set one.
+const DefaultColumnWidth int = 10
+
+// RowSource is the data of a grid. The grid only asks for the cells it draws, so a
+// source can hold any number of rows; sorting, however, compares every row.
+type RowSource interface {
+	// RowCount returns the number of rows.
+	//
+	// Returns:
+	//   - int: The number of rows.
+	RowCount() int
+
+	// Cell returns the value of a cell.
+	//
+	// Parameters:
+	//   - row: The index of the row. Assumed to be in [0, RowCount()).
+	//   - col: The index of the column. Assumed to be a column of the grid.
+	//
+	// Returns:
+	//   - any: The value, formatted by the column.
+	Cell(row, col int) any
+}
+
+// Column is a column of a grid.
+type Column struct {
+	// Title is the text of the header of the column.
+	Title string
+
+	// Width is the initial width of the column. Non-positive values mean
+	// DefaultColumnWidth.
+	Width int
+
+	// MinWidth is the smallest width the column can be resized to. Values less than
+	// 1 mean 1.
+	MinWidth int
+
+	// Align is the alignment of the cells of the column.
+	Align ds.Alignment
+
+	// Format turns a value into the text of a cell. Nil uses fmt.Sprint.
+	Format func(value any) string
+
+	// Compare orders two values when sorting by the column. Nil compares numbers
+	// and strings by value and other values by their text.
+	Compare func(a, b any) int
+}
+
+// GridSelection is what the cursor of a grid selects.
+type GridSelection int
+
+const (
+	// SelectRow selects whole rows; Left and Right scroll the columns.
+	SelectRow GridSelection = iota
+
+	// SelectCell selects single cells.
+	SelectCell
+)
+
+// String implements the fmt.Stringer interface.
+func (gs GridSelection) String() string {
+	return [...]string{
+		"row",
+		"cell",
+	}[gs]
+}
+
+// as_float is a helper function that converts a number to a float64.
+//
+// Parameters:
+//   - v: The value.
+//
+// Returns:
+//   - float64: The number.
+//   - bool: False if the value is not a number.
+func as_float(v any) (float64, bool) {
+	switch v := v.(type) {
+	case int:
+		return float64(v), true
+	case int8:
+		return float64(v), true
+	case int16:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case uint:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint32:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	default:
+		return 0, false
+	}
+}
+
+// compare_values is the default comparison of the values of a column.
+//
+// Parameters:
+//   - a: The first value.
+//   - b: The second value.
+//
+// Returns:
+//   - int: -1, 0 or 1 if a is less than, equal to or greater than b.
+func compare_values(a, b any) int {
+	fa, ok_a := as_float(a)
+	fb, ok_b := as_float(b)
+
+	if ok_a && ok_b {
+		return cmp.Compare(fa, fb)
+	}
+
+	sa, ok_a := a.(string)
+	sb, ok_b := b.(string)
+
+	if ok_a && ok_b {
+		return strings.Compare(sa, sb)
+	}
+
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// Grid is an interactive table of records, meant to be the element of a node of a
+// screen.Screen. The header stays at the top while the rows scroll, and only the
+// visible cells are asked to the source and drawn.
+//
+// Keys:
+//   - Up/Down, PgUp/PgDn and Home/End move the cursor; Left/Right move it between
+//     cells or, when selecting rows, scroll the columns.
+//   - Ctrl+Left/Right shrink or widen the current column (when selecting rows, the
+//     first one shown).
+//   - 's' sorts by that column; again, it reverses the order.
+//   - Enter activates the current row.
+//
+// With the mouse, a click on a header sorts by its column, dragging the border
+// after a header resizes its column, a click on a cell moves the cursor and the
+// wheel scrolls.
+type Grid struct {
+	// columns are the columns.
+	columns []Column
+
+	// widths are the current widths of the columns.
+	widths []int
+
+	// source is the data of the grid.
+	source RowSource
+
+	// order is the order of the rows, when sorted. Nil if unsorted.
+	order []int
+
+	// sort_col is the column the rows are sorted by. -1 if unsorted.
+	sort_col int
+
+	// sort_desc is true if the rows are sorted in decreasing order.
+	sort_desc bool
+
+	// mode is what the cursor selects.
+	mode GridSelection
+
+	// row and col are the position of the cursor; row is among the sorted rows.
+	row, col int
+
+	// top is the index of the first row drawn.
+	top int
+
+	// left is the index of the first column drawn.
+	left int
+
+	// follow is true if the next draw must scroll to the cursor.
+	follow bool
+
+	// height is the number of rows drawn in the last draw, the header excluded.
+	height int
+
+	// area is the area of the node of the grid.
+	area ds.Rect
+
+	// resizing is the column whose border is dragged. -1 if none.
+	resizing int
+
+	// pressed is true while the first mouse button is held, so that the motion
+	// events reported while dragging do not click again.
+	pressed bool
+
+	// on_activate is called with the index of a row when it is activated.
+	on_activate func(row int)
+
+	// focused is true if the grid has the focus.
+	focused bool
+
+	// mu is the mutex of the grid.
+	mu sync.Mutex
+}
+
+// NewGrid creates a new grid.
+//
+// Parameters:
+//   - columns: The columns.
+//   - source: The data of the grid. Nil for an empty grid.
+//
+// Returns:
+//   - *Grid: The new grid. Never returns nil.
+func NewGrid(columns []Column, source RowSource) *Grid {
+	columns = slices.Clone(columns)
+	widths := make([]int, 0, len(columns))
+
+	for i, c := range columns {
+		columns[i].MinWidth = max(c.MinWidth, 1)
+
+		if c.Width <= 0 {
+			c.Width = DefaultColumnWidth
+		}
+
+		widths = append(widths, max(c.Width, columns[i].MinWidth))
+	}
+
+	return &Grid{
+		columns:  columns,
+		widths:   widths,
+		source:   source,
+		sort_col: -1,
+		resizing: -1,
+	}
+}
+
+// SetSource replaces the data of the grid and keeps the sort order. Does nothing
+// with a nil receiver.
+//
+// Parameters:
+//   - source: The data. Nil for an empty grid.
+func (g *Grid) SetSource(source RowSource) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+
+	g.source = source
+	g.row = 0
+	g.top = 0
+	g.sort()
+
+	g.mu.Unlock()
+}
+
+// Refresh sorts the rows again after the data of the source changed. Does nothing
+// with a nil receiver.
+func (g *Grid) Refresh() {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	g.sort()
+	g.mu.Unlock()
+}
+
+// SetSelection changes what the cursor selects. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - mode: The selection mode.
+func (g *Grid) SetSelection(mode GridSelection) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	g.mode = mode
+	g.mu.Unlock()
+}
+
+// SortBy sorts the rows by a column. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - col: The index of the column. Out of range values unsort the rows.
+//   - desc: True to sort in decreasing order.
+func (g *Grid) SortBy(col int, desc bool) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+
+	if col < 0 || col >= len(g.columns) {
+		col = -1
+	}
+
+	g.sort_col = col
+	g.sort_desc = desc
+	g.sort()
+
+	g.mu.Unlock()
+}
+
+// ColumnWidths returns the current widths of the columns; for instance, to save
+// them.
+//
+// Returns:
+//   - []int: The widths.
+func (g *Grid) ColumnWidths() []int {
+	if g == nil {
+		return nil
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return slices.Clone(g.widths)
+}
+
+// SetColumnWidth changes the width of a column. Does nothing with a nil receiver
+// or an out of range column.
+//
+// Parameters:
+//   - col: The index of the column.
+//   - width: The width. Clamped to the minimum width of the column.
+func (g *Grid) SetColumnWidth(col, width int) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if col >= 0 && col < len(g.columns) {
+		g.widths[col] = max(width, g.columns[col].MinWidth)
+	}
+}
+
+// Current returns the position of the cursor.
+//
+// Returns:
+//   - int: The index of the row in the source. -1 if the grid is empty.
+//   - int: The index of the column.
+func (g *Grid) Current() (int, int) {
+	if g == nil {
+		return -1, 0
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.current(), g.col
+}
+
+// OnActivate sets the function called when a row is activated with Enter or a
+// click. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - fn: The function, called with the index of the row in the source. Nil for
+//     none.
+func (g *Grid) OnActivate(fn func(row int)) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	g.on_activate = fn
+	g.mu.Unlock()
+}
+
+// count is a helper method that returns the number of rows.
+//
+// Returns:
+//   - int: The number of rows.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) count() int {
+	if g.source == nil {
+		return 0
+	}
+
+	return g.source.RowCount()
+}
+
+// index is a helper method that returns the index in the source of a sorted row.
+//
+// Parameters:
+//   - pos: The position of the row among the sorted rows.
+//
+// Returns:
+//   - int: The index in the source.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) index(pos int) int {
+	if g.order == nil {
+		return pos
+	}
+
+	return g.order[pos]
+}
+
+// current is a helper method that returns the index in the source of the row under
+// the cursor.
+//
+// Returns:
+//   - int: The index. -1 if the grid is empty.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) current() int {
+	if g.row >= g.count() {
+		return -1
+	}
+
+	return g.index(g.row)
+}
+
+// sort is a helper method that computes the order of the rows and keeps the cursor
+// on its row.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) sort() {
+	current := g.current()
+	n := g.count()
+
+	if g.sort_col == -1 || n == 0 {
+		g.order = nil
+	} else {
+		col := g.sort_col
+
+		compare := g.columns[col].Compare
+		if compare == nil {
+			compare = compare_values
+		}
+
+		values := make([]any, n)
+
+		for i := range n {
+			values[i] = g.source.Cell(i, col)
+		}
+
+		g.order = make([]int, n)
+
+		for i := range n {
+			g.order[i] = i
+		}
+
+		slices.SortStableFunc(g.order, func(a, b int) int {
+			c := compare(values[a], values[b])
+
+			if g.sort_desc {
+				return -c
+			}
+
+			return c
+		})
+	}
+
+	if current != -1 {
+		g.row = current
+
+		if g.order != nil {
+			g.row = slices.Index(g.order, current)
+		}
+	}
+
+	g.move(g.row, g.col)
+}
+
+// move is a helper method that moves the cursor, clamped to the grid.
+//
+// Parameters:
+//   - row: The row, among the sorted rows.
+//   - col: The column.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) move(row, col int) {
+	g.row = max(min(row, g.count()-1), 0)
+	g.col = max(min(col, len(g.columns)-1), 0)
+	g.follow = true
+}
+
+// toggle_sort is a helper method that sorts by a column or, if already sorted by
+// it, reverses the order.
+//
+// Parameters:
+//   - col: The column.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) toggle_sort(col int) {
+	if g.sort_col == col {
+		g.sort_desc = !g.sort_desc
+	} else {
+		g.sort_col = col
+		g.sort_desc = false
+	}
+
+	g.sort()
+}
+
+// activate is a helper method that returns the function that activates the row
+// under the cursor, to be called once unlocked.
+//
+// Returns:
+//   - func(): The function. Nil if there is nothing to call.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) activate() func() {
+	row := g.current()
+	fn := g.on_activate
+
+	if row == -1 || fn == nil {
+		return nil
+	}
+
+	return func() {
+		fn(row)
+	}
+}
+
+// HandleEvent implements the screen.Handler interface.
+func (g *Grid) HandleEvent(ev tcell.Event) bool {
+	g.mu.Lock()
+
+	var consumed bool
+	var after func()
+
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		consumed, after = g.handle_key(ev)
+	case *tcell.EventMouse:
+		consumed = g.handle_mouse(ev)
+	}
+
+	g.mu.Unlock()
+
+	if after != nil {
+		after()
+	}
+
+	return consumed
+}
+
+// handle_key is a helper method that handles a key.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the key was consumed.
+//   - func(): The function to call once unlocked. Nil for none.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) handle_key(ev *tcell.EventKey) (bool, func()) {
+	page := max(g.height-1, 1)
+	ctrl := ev.Modifiers()&tcell.ModCtrl != 0
+
+	// When selecting rows, the keys act on the first column shown.
+	col := g.col
+	if g.mode == SelectRow {
+		col = g.left
+	}
+
+	switch ev.Key() {
+	case tcell.KeyUp:
+		g.move(g.row-1, g.col)
+	case tcell.KeyDown:
+		g.move(g.row+1, g.col)
+	case tcell.KeyPgUp:
+		g.move(g.row-page, g.col)
+	case tcell.KeyPgDn:
+		g.move(g.row+page, g.col)
+	case tcell.KeyHome:
+		g.move(0, g.col)
+	case tcell.KeyEnd:
+		g.move(g.count()-1, g.col)
+	case tcell.KeyLeft, tcell.KeyRight:
+		step := 1
+		if ev.Key() == tcell.KeyLeft {
+			step = -1
+		}
+
+		switch {
+		case ctrl && len(g.columns) > 0:
+			g.widths[col] = max(g.widths[col]+step, g.columns[col].MinWidth)
+		case g.mode == SelectRow:
+			g.left = max(min(col+step, len(g.columns)-1), 0)
+		default:
+			g.move(g.row, col+step)
+		}
+	case tcell.KeyEnter:
+		return true, g.activate()
+	case tcell.KeyRune:
+		if ev.Rune() != 's' || len(g.columns) == 0 {
+			return false, nil
+		}
+
+		g.toggle_sort(col)
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// column_at is a helper method that finds the column drawn at the given x
+// coordinate of the grid.
+//
+// Parameters:
+//   - x: The x coordinate, relative to the grid.
+//
+// Returns:
+//   - int: The index of the column. -1 if none.
+//   - bool: True if x is on the border after the column.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) column_at(x int) (int, bool) {
+	start := 0
+
+	for col := g.left; col < len(g.columns); col++ {
+		end := start + g.widths[col]
+
+		if x < end {
+			return col, false
+		} else if x == end {
+			return col, true
+		}
+
+		start = end + 1
+	}
+
+	return -1, false
+}
+
+// column_start is a helper method that returns the x coordinate, relative to the
+// grid, where a drawn column starts.
+//
+// Parameters:
+//   - col: The index of the column. Assumed to be at least g.left.
+//
+// Returns:
+//   - int: The x coordinate.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) column_start(col int) int {
+	start := 0
+
+	for c := g.left; c < col; c++ {
+		start += g.widths[c] + 1
+	}
+
+	return start
+}
+
+// handle_mouse is a helper method that handles a mouse event.
+//
+// Parameters:
+//   - ev: The mouse event.
+//
+// Returns:
+//   - bool: True if the event was consumed.
+//
+// Assertions:
+//   - g.mu is locked.
+func (g *Grid) handle_mouse(ev *tcell.EventMouse) bool {
+	x, y := ev.Position()
+	x -= g.area.X
+	y -= g.area.Y
+
+	buttons := ev.Buttons()
+
+	if g.resizing != -1 {
+		if buttons&tcell.Button1 == 0 {
+			g.resizing = -1
+			g.pressed = false
+		} else {
+			col := g.resizing
+			g.widths[col] = max(x-g.column_start(col), g.columns[col].MinWidth)
+		}
+
+		return true
+	}
+
+	switch {
+	case buttons&tcell.WheelUp != 0:
+		g.top = max(g.top-3, 0)
+		g.follow = false
+	case buttons&tcell.WheelDown != 0:
+		g.top = max(min(g.top+3, g.count()-g.height), 0)
+		g.follow = false
+	case buttons&tcell.WheelLeft != 0:
+		g.left = max(g.left-1, 0)
+	case buttons&tcell.WheelRight != 0:
+		g.left = max(min(g.left+1, len(g.columns)-1), 0)
+	case buttons&tcell.Button1 != 0:
+		if g.pressed {
+			return true
+		}
+
+		col, on_border := g.column_at(x)
+		if col == -1 {
+			return false
+		}
+
+		if y == 0 {
+			g.pressed = true
+
+			if on_border {
+				g.resizing = col
+			} else {
+				g.toggle_sort(col)
+			}
+
+			return true
+		}
+
+		row := g.top + y - 1
+		if row >= g.count() || y > g.height {
+			return false
+		}
+
+		g.pressed = true
+
+		g.move(row, col)
+	default:
+		if !g.pressed {
+			return false
+		}
+
+		g.pressed = false
+	}
+
+	return true
+}
+
+// SetFocused implements the screen.Focuser interface.
+func (g *Grid) SetFocused(focused bool) {
+	g.mu.Lock()
+	g.focused = focused
+	g.mu.Unlock()
+}
+
+// SetArea implements the screen.Arranger interface.
+func (g *Grid) SetArea(area ds.Rect) {
+	g.mu.Lock()
+	g.area = area
+	g.mu.Unlock()
+}
+
+// fit is a helper function that aligns text within a width, cutting it with an
+// ellipsis if it is too long.
+//
+// Parameters:
+//   - text: The text.
+//   - width: The width. Assumed to be positive.
+//   - align: The alignment.
+//
+// Returns:
+//   - []rune: Exactly width characters.
+func fit(text string, width int, align ds.Alignment) []rune {
+	chars := []rune(text)
+
+	if len(chars) > width {
+		chars = append(chars[:width-1], ds.DefaultOverflow)
+	}
+
+	pad := width - len(chars)
+
+	var left int
+
+	switch align {
+	case ds.AlignCenter:
+		left = pad / 2
+	case ds.AlignRight:
+		left = pad
+	}
+
+	line := make([]rune, 0, width)
+	line = append(line, []rune(strings.Repeat(" ", left))...)
+	line = append(line, chars...)
+	line = append(line, []rune(strings.Repeat(" ", pad-left))...)
+
+	return line
+}
+
+// Draw implements the screen.Drawer interface. The first row of the table holds
+// the header and the others the rows.
+func (g *Grid) Draw(table *dtb.Table, x, y *int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	width, height := table.Width(), table.Height()
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	g.height = height - 1
+
+	if g.follow {
+		if g.row < g.top {
+			g.top = g.row
+		} else if g.row >= g.top+g.height {
+			g.top = g.row - g.height + 1
+		}
+
+		if g.mode == SelectCell {
+			if g.col < g.left {
+				g.left = g.col
+			}
+
+			for g.left < g.col && g.column_start(g.col)+g.widths[g.col] > width {
+				g.left++
+			}
+		}
+
+		g.follow = false
+	}
+
+	count := g.count()
+	g.top = max(min(g.top, count-g.height), 0)
+	g.left = max(min(g.left, len(g.columns)-1), 0)
+
+	text_style := theme.RoleText.Style()
+	title_style := theme.RoleTitle.Style()
+	border_style := theme.RoleBorder.Style()
+
+	selected_style := theme.RoleSelection.Style()
+	if g.focused {
+		selected_style = theme.RoleMenuSelected.Style()
+	}
+
+	for row := range height {
+		style := text_style
+		if row == 0 {
+			style = title_style
+		}
+
+		for col := range width {
+			table.WriteAt(col, row, dtb.NewCell(' ', style))
+		}
+	}
+
+	write := func(col, row int, chars []rune, style tcell.Style) {
+		for i, r := range chars {
+			table.WriteAt(col+i, row, dtb.NewCell(r, style))
+		}
+	}
+
+	start := 0
+
+	for col := g.left; col < len(g.columns) && start < width; col++ {
+		c := g.columns[col]
+		w := g.widths[col]
+
+		title := c.Title
+
+		if col == g.sort_col {
+			if g.sort_desc {
+				title += " ▼"
+			} else {
+				title += " ▲"
+			}
+		}
+
+		write(start, 0, fit(title, w, c.Align), title_style)
+
+		format := c.Format
+		if format == nil {
+			format = func(value any) string {
+				return fmt.Sprint(value)
+			}
+		}
+
+		for row := range min(g.height, count-g.top) {
+			pos := g.top + row
+
+			style := text_style
+			if pos == g.row && (g.mode == SelectRow || col == g.col) {
+				style = selected_style
+			}
+
+			value := g.source.Cell(g.index(pos), col)
+			write(start, row+1, fit(format(value), w, c.Align), style)
+		}
+
+		for row := range height {
+			table.WriteAt(start+w, row, dtb.NewCell('│', border_style))
+		}
+
+		start += w + 1
+	}
+
+	return nil
+}
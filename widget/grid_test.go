@@ -0,0 +1,106 @@
+package widget
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+// records is a row source of name and size records.
+type records [][2]any
+
+func (r records) RowCount() int {
+	return len(r)
+}
+
+func (r records) Cell(row, col int) any {
+	return r[row][col]
+}
+
+// draw_lines is a helper function that draws an element on a new table of the
+// given size and returns the lines of the table.
+func draw_lines(t *testing.T, elem dtb.Displayer, width, height int) []string {
+	t.Helper()
+
+	table, err := dtb.NewTable(width, height)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	x, y := 0, 0
+
+	err = elem.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	return table.GetLines()
+}
+
+func TestGrid(t *testing.T) {
+	source := records{
+		{"beta", 30},
+		{"alpha", 4},
+		{"gamma", 200},
+	}
+
+	g := NewGrid([]Column{
+		{Title: "Name", Width: 6},
+		{Title: "Size", Width: 5, Align: ds.AlignRight},
+	}, source)
+
+	g.SetSelection(SelectCell)
+	g.HandleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+	g.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone))
+	g.HandleEvent(tcell.NewEventKey(tcell.KeyRune, 's', tcell.ModNone))
+
+	want := []string{
+		"Name  │Size…│",
+		"gamma │  200│",
+		"beta  │   30│",
+	}
+
+	if got := draw_lines(t, g, 13, 3); !slices.Equal(got, want) {
+		t.Fatalf("Expected:\n%s\nbut got:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	// The cursor stays on its row while sorting.
+	if row, col := g.Current(); row != 0 || col != 1 {
+		t.Fatalf("Expected the cursor on (0, 1), but got (%d, %d)", row, col)
+	}
+
+	g.SetArea(ds.Rect{X: 1, Y: 1, Width: 13, Height: 3})
+
+	// A press right of the columns is not consumed and must not swallow the next
+	// press.
+	if g.HandleEvent(tcell.NewEventMouse(14, 2, tcell.Button1, tcell.ModNone)) {
+		t.Fatalf("Expected a press right of the columns not to be consumed")
+	}
+
+	// Drag the border after "Name" to make it 3 cells wide, then sort by it.
+	g.HandleEvent(tcell.NewEventMouse(7, 1, tcell.Button1, tcell.ModNone))
+	g.HandleEvent(tcell.NewEventMouse(4, 1, tcell.Button1, tcell.ModNone))
+	g.HandleEvent(tcell.NewEventMouse(4, 1, tcell.ButtonNone, tcell.ModNone))
+	g.HandleEvent(tcell.NewEventMouse(2, 1, tcell.Button1, tcell.ModNone))
+
+	// Motion while the button is held does not sort again.
+	g.HandleEvent(tcell.NewEventMouse(3, 1, tcell.Button1, tcell.ModNone))
+
+	if got := g.ColumnWidths(); !slices.Equal(got, []int{3, 5}) {
+		t.Fatalf("Expected widths [3 5], but got %v", got)
+	}
+
+	want = []string{
+		"Na…│ Size│   ",
+		"al…│    4│   ",
+		"be…│   30│   ",
+	}
+
+	if got := draw_lines(t, g, 13, 3); !slices.Equal(got, want) {
+		t.Fatalf("Expected:\n%s\nbut got:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+}
@@ -0,0 +1,62 @@
+package widget
+
+import (
+	"strings"
+
+	"github.com/PlayerR9/display/highlight"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// HighlightWith creates a highlighter that lexes the visible lines of an editor and
+// colours them with the token rules of the given highlight. Since only the visible
+// lines are lexed, tokens that span more lines than the editor shows may be
+// coloured differently once scrolled.
+//
+// Parameters:
+//   - h: The highlight whose token rules are used.
+//   - lex: The function that splits the lines, joined with newlines, into tokens.
+//     If it fails, the lines keep the style of the editor.
+//
+// Returns:
+//   - Highlighter: The highlighter. Nil if h or lex is nil.
+func HighlightWith[E interface {
+	~int
+}, T interface {
+	GetPos() int
+	GetData() string
+	GetType() E
+}](h *highlight.Highlight[E, T], lex func(data []byte) ([]T, error)) Highlighter {
+	if h == nil || lex == nil {
+		return nil
+	}
+
+	fn := func(first int, lines [][]rune) [][]tcell.Style {
+		parts := make([]string, 0, len(lines))
+
+		for _, line := range lines {
+			parts = append(parts, string(line))
+		}
+
+		data := []byte(strings.Join(parts, "\n"))
+
+		tokens, err := lex(data)
+		if err != nil {
+			return nil
+		}
+
+		styles := h.SetTokens(data, tokens).Styles(theme.RoleInput.Style())
+		result := make([][]tcell.Style, 0, len(lines))
+
+		for _, line := range lines {
+			n := min(len(line), len(styles))
+
+			result = append(result, styles[:n])
+			styles = styles[min(n+1, len(styles)):]
+		}
+
+		return result
+	}
+
+	return HighlighterFunc(fn)
+}
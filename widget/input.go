@@ -0,0 +1,732 @@
+package widget
+
+import (
+	"slices"
+	"sync"
+	"unicode"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// DefaultHistorySize is the number of submitted values an input remembers.
+const DefaultHistorySize int = 100
+
+// Input is an editable single-line text input. It is meant to be the element of a
+// node of a screen.Screen: it handles keys while it has the focus and places the
+// terminal cursor, without blocking the event loop. The submitted values are
+// delivered through OnSubmit.
+//
+// Keys:
+//   - Left/Right, Home/End (or Ctrl+A/Ctrl+E) move the cursor; with Ctrl, Left and
+//     Right jump by words; with Shift, they extend the selection.
+//   - Backspace/Delete delete the selection or a character; Ctrl+W deletes the word
+//     before the cursor, Ctrl+U everything before it and Ctrl+K everything after it.
+//   - Ctrl+C, Ctrl+X and Ctrl+V copy, cut and paste within the input.
+//   - Up/Down browse the history of submitted values.
+//   - Enter submits the value if it is valid.
+type Input struct {
+	// value is the text of the input.
+	value []rune
+
+	// cursor is the index of the cursor in the value.
+	cursor int
+
+	// anchor is the index where the selection starts. -1 if there is no selection.
+	anchor int
+
+	// offset is the index of the first character drawn.
+	offset int
+
+	// placeholder is the text drawn when the value is empty.
+	placeholder string
+
+	// mask is the character drawn instead of each character of the value. 0 for
+	// none.
+	mask rune
+
+	// clipboard is the text of the last copy or cut.
+	clipboard []rune
+
+	// history holds the submitted values, from the oldest to the newest.
+	history []string
+
+	// history_size is the maximum number of values in the history.
+	history_size int
+
+	// history_idx is the index of the value of the history being shown. Equal to
+	// len(history) when editing a new value.
+	history_idx int
+
+	// draft is the value that was being edited before browsing the history.
+	draft []rune
+
+	// accept tells whether a typed character is accepted. Nil accepts all.
+	accept func(r rune) bool
+
+	// validate checks the value. Nil accepts all.
+	validate func(value string) error
+
+	// err is the result of the last validation.
+	err error
+
+	// on_change is called whenever the value changes.
+	on_change func(value string)
+
+	// on_submit is called with the value when Enter is pressed and the value is
+	// valid.
+	on_submit func(value string)
+
+	// focused is true if the input has the focus.
+	focused bool
+
+	// mu is the mutex of the input.
+	mu sync.Mutex
+}
+
+// NewInput creates a new, empty input.
+//
+// Parameters:
+//   - placeholder: The text drawn when the value is empty.
+//
+// Returns:
+//   - *Input: The new input. Never returns nil.
+func NewInput(placeholder string) *Input {
+	return &Input{
+		anchor:       -1,
+		placeholder:  placeholder,
+		history_size: DefaultHistorySize,
+	}
+}
+
+// Value returns the text of the input.
+//
+// Returns:
+//   - string: The text.
+func (in *Input) Value() string {
+	if in == nil {
+		return ""
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return string(in.value)
+}
+
+// SetValue replaces the text of the input and moves the cursor to its end. Does
+// nothing with a nil receiver.
+//
+// Parameters:
+//   - value: The new text.
+func (in *Input) SetValue(value string) {
+	if in == nil {
+		return
+	}
+
+	in.mu.Lock()
+
+	in.value = []rune(value)
+	in.cursor = len(in.value)
+	in.anchor = -1
+	fn := in.changed()
+
+	in.mu.Unlock()
+
+	fn()
+}
+
+// Err returns the result of the last validation of the value.
+//
+// Returns:
+//   - error: The validation error. Nil if the value is valid.
+func (in *Input) Err() error {
+	if in == nil {
+		return nil
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return in.err
+}
+
+// SetMask makes the input draw the given character instead of each character of
+// the value (e.g., '*' for passwords). Copying and cutting are disabled while a
+// mask is set. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - mask: The character. 0 to show the value.
+func (in *Input) SetMask(mask rune) {
+	if in == nil {
+		return
+	}
+
+	in.mu.Lock()
+	in.mask = mask
+	in.mu.Unlock()
+}
+
+// SetAccept sets the function that tells whether a typed or pasted character is
+// accepted (e.g., unicode.IsDigit). Does nothing with a nil receiver.
+//
+// Parameters:
+//   - fn: The function. Nil accepts every character.
+func (in *Input) SetAccept(fn func(r rune) bool) {
+	if in == nil {
+		return
+	}
+
+	in.mu.Lock()
+	in.accept = fn
+	in.mu.Unlock()
+}
+
+// SetValidator sets the function that checks the value after each change and
+// before it is submitted. Invalid values are drawn with the error style and cannot
+// be submitted. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - fn: The function. Nil accepts every value.
+func (in *Input) SetValidator(fn func(value string) error) {
+	if in == nil {
+		return
+	}
+
+	in.mu.Lock()
+
+	in.validate = fn
+	in.err = nil
+
+	if fn != nil {
+		in.err = fn(string(in.value))
+	}
+
+	in.mu.Unlock()
+}
+
+// OnChange sets the function called whenever the value changes. Does nothing with
+// a nil receiver.
+//
+// Parameters:
+//   - fn: The function. Can be nil.
+func (in *Input) OnChange(fn func(value string)) {
+	if in == nil {
+		return
+	}
+
+	in.mu.Lock()
+	in.on_change = fn
+	in.mu.Unlock()
+}
+
+// OnSubmit sets the function called with the value when Enter is pressed and the
+// value is valid. The value is then added to the history and the input is cleared.
+// Does nothing with a nil receiver.
+//
+// Parameters:
+//   - fn: The function. Can be nil.
+func (in *Input) OnSubmit(fn func(value string)) {
+	if in == nil {
+		return
+	}
+
+	in.mu.Lock()
+	in.on_submit = fn
+	in.mu.Unlock()
+}
+
+// SetHistorySize changes the number of submitted values the input remembers. Does
+// nothing with a nil receiver.
+//
+// Parameters:
+//   - size: The number of values. 0 disables the history.
+func (in *Input) SetHistorySize(size int) {
+	if in == nil {
+		return
+	}
+
+	in.mu.Lock()
+
+	in.history_size = max(size, 0)
+
+	if len(in.history) > in.history_size {
+		in.history = in.history[len(in.history)-in.history_size:]
+	}
+
+	in.history_idx = len(in.history)
+
+	in.mu.Unlock()
+}
+
+// History returns the submitted values, from the oldest to the newest.
+//
+// Returns:
+//   - []string: The history.
+func (in *Input) History() []string {
+	if in == nil {
+		return nil
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return slices.Clone(in.history)
+}
+
+// changed is a helper method that validates the value after a change and returns
+// the function that notifies the change listener, to be called once unlocked.
+//
+// Returns:
+//   - func(): The function. Never nil.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) changed() func() {
+	value := string(in.value)
+
+	if in.validate != nil {
+		in.err = in.validate(value)
+	}
+
+	fn := in.on_change
+	if fn == nil {
+		return func() {}
+	}
+
+	return func() {
+		fn(value)
+	}
+}
+
+// selection is a helper method that returns the selected range.
+//
+// Returns:
+//   - int: The start of the selection.
+//   - int: The end of the selection (exclusive).
+//   - bool: False if there is no selection.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) selection() (int, int, bool) {
+	if in.anchor == -1 || in.anchor == in.cursor {
+		return 0, 0, false
+	}
+
+	return min(in.anchor, in.cursor), max(in.anchor, in.cursor), true
+}
+
+// delete_range is a helper method that deletes part of the value and places the
+// cursor at its start.
+//
+// Parameters:
+//   - from: The start of the range.
+//   - to: The end of the range (exclusive).
+//
+// Assertions:
+//   - in.mu is locked.
+//   - 0 <= from <= to <= len(in.value).
+func (in *Input) delete_range(from, to int) {
+	in.value = slices.Delete(in.value, from, to)
+	in.cursor = from
+	in.anchor = -1
+}
+
+// insert is a helper method that replaces the selection, if any, with the given
+// characters. Characters that are not accepted are dropped.
+//
+// Parameters:
+//   - chars: The characters to insert.
+//
+// Returns:
+//   - bool: True if the value changed.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) insert(chars []rune) bool {
+	changed := false
+
+	if from, to, ok := in.selection(); ok {
+		in.delete_range(from, to)
+		changed = true
+	}
+
+	in.anchor = -1
+
+	for _, r := range chars {
+		if !unicode.IsPrint(r) || (in.accept != nil && !in.accept(r)) {
+			continue
+		}
+
+		in.value = slices.Insert(in.value, in.cursor, r)
+		in.cursor++
+		changed = true
+	}
+
+	return changed
+}
+
+// word_start is a helper method that returns the index of the start of the word
+// before the given index.
+//
+// Parameters:
+//   - idx: The index.
+//
+// Returns:
+//   - int: The start of the word.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) word_start(idx int) int {
+	for idx > 0 && unicode.IsSpace(in.value[idx-1]) {
+		idx--
+	}
+
+	for idx > 0 && !unicode.IsSpace(in.value[idx-1]) {
+		idx--
+	}
+
+	return idx
+}
+
+// word_end is a helper method that returns the index of the end of the word after
+// the given index.
+//
+// Parameters:
+//   - idx: The index.
+//
+// Returns:
+//   - int: The end of the word.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) word_end(idx int) int {
+	for idx < len(in.value) && unicode.IsSpace(in.value[idx]) {
+		idx++
+	}
+
+	for idx < len(in.value) && !unicode.IsSpace(in.value[idx]) {
+		idx++
+	}
+
+	return idx
+}
+
+// move is a helper method that moves the cursor, extending the selection or
+// clearing it.
+//
+// Parameters:
+//   - idx: The new index of the cursor.
+//   - extend: True to extend the selection.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) move(idx int, extend bool) {
+	if extend {
+		if in.anchor == -1 {
+			in.anchor = in.cursor
+		}
+	} else {
+		in.anchor = -1
+	}
+
+	in.cursor = max(min(idx, len(in.value)), 0)
+}
+
+// browse is a helper method that shows another value of the history.
+//
+// Parameters:
+//   - step: -1 for an older value and 1 for a newer one.
+//
+// Returns:
+//   - bool: True if the value changed.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) browse(step int) bool {
+	idx := in.history_idx + step
+	if idx < 0 || idx > len(in.history) {
+		return false
+	}
+
+	if in.history_idx == len(in.history) {
+		in.draft = slices.Clone(in.value)
+	}
+
+	in.history_idx = idx
+
+	if idx == len(in.history) {
+		in.value = in.draft
+	} else {
+		in.value = []rune(in.history[idx])
+	}
+
+	in.cursor = len(in.value)
+	in.anchor = -1
+
+	return true
+}
+
+// submit is a helper method that submits the value, if valid.
+//
+// Returns:
+//   - func(): The function that calls the submit and change listeners, to be called
+//     once unlocked. Never nil.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) submit() func() {
+	value := string(in.value)
+
+	if in.validate != nil {
+		in.err = in.validate(value)
+		if in.err != nil {
+			return func() {}
+		}
+	}
+
+	if in.history_size > 0 && value != "" && in.mask == 0 {
+		if len(in.history) == 0 || in.history[len(in.history)-1] != value {
+			in.history = append(in.history, value)
+		}
+
+		if len(in.history) > in.history_size {
+			in.history = in.history[1:]
+		}
+	}
+
+	in.history_idx = len(in.history)
+	in.draft = nil
+
+	in.value = nil
+	in.cursor = 0
+	in.anchor = -1
+	in.offset = 0
+
+	fn := in.on_submit
+	notify := in.changed()
+
+	return func() {
+		if fn != nil {
+			fn(value)
+		}
+
+		notify()
+	}
+}
+
+// HandleEvent implements the screen.Handler interface.
+func (in *Input) HandleEvent(ev tcell.Event) bool {
+	key, ok := ev.(*tcell.EventKey)
+	if !ok {
+		return false
+	}
+
+	in.mu.Lock()
+
+	changed, consumed := in.handle_key(key)
+
+	var after func()
+
+	if key.Key() == tcell.KeyEnter {
+		after = in.submit()
+	} else if changed {
+		after = in.changed()
+	}
+
+	in.mu.Unlock()
+
+	if after != nil {
+		after()
+	}
+
+	return consumed
+}
+
+// handle_key is a helper method that edits the value according to a key.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the value changed.
+//   - bool: True if the key was consumed.
+//
+// Assertions:
+//   - in.mu is locked.
+func (in *Input) handle_key(ev *tcell.EventKey) (bool, bool) {
+	mods := ev.Modifiers()
+	extend := mods&tcell.ModShift != 0
+	by_word := mods&(tcell.ModCtrl|tcell.ModAlt) != 0
+
+	switch ev.Key() {
+	case tcell.KeyRune:
+		return in.insert([]rune{ev.Rune()}), true
+	case tcell.KeyLeft:
+		if by_word {
+			in.move(in.word_start(in.cursor), extend)
+		} else if from, _, ok := in.selection(); ok && !extend {
+			in.move(from, false)
+		} else {
+			in.move(in.cursor-1, extend)
+		}
+	case tcell.KeyRight:
+		if by_word {
+			in.move(in.word_end(in.cursor), extend)
+		} else if _, to, ok := in.selection(); ok && !extend {
+			in.move(to, false)
+		} else {
+			in.move(in.cursor+1, extend)
+		}
+	case tcell.KeyHome, tcell.KeyCtrlA:
+		in.move(0, extend)
+	case tcell.KeyEnd, tcell.KeyCtrlE:
+		in.move(len(in.value), extend)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if from, to, ok := in.selection(); ok {
+			in.delete_range(from, to)
+		} else if in.cursor > 0 {
+			in.delete_range(in.cursor-1, in.cursor)
+		} else {
+			return false, true
+		}
+
+		return true, true
+	case tcell.KeyDelete:
+		if from, to, ok := in.selection(); ok {
+			in.delete_range(from, to)
+		} else if in.cursor < len(in.value) {
+			in.delete_range(in.cursor, in.cursor+1)
+		} else {
+			return false, true
+		}
+
+		return true, true
+	case tcell.KeyCtrlW:
+		start := in.word_start(in.cursor)
+		in.delete_range(start, in.cursor)
+
+		return true, true
+	case tcell.KeyCtrlU:
+		in.delete_range(0, in.cursor)
+
+		return true, true
+	case tcell.KeyCtrlK:
+		in.delete_range(in.cursor, len(in.value))
+
+		return true, true
+	case tcell.KeyCtrlC, tcell.KeyCtrlX:
+		from, to, ok := in.selection()
+		if !ok || in.mask != 0 {
+			return false, true
+		}
+
+		in.clipboard = slices.Clone(in.value[from:to])
+
+		if ev.Key() == tcell.KeyCtrlC {
+			return false, true
+		}
+
+		in.delete_range(from, to)
+
+		return true, true
+	case tcell.KeyCtrlV:
+		return in.insert(in.clipboard), true
+	case tcell.KeyUp:
+		return in.browse(-1), true
+	case tcell.KeyDown:
+		return in.browse(1), true
+	case tcell.KeyEnter:
+		// Submitted by HandleEvent.
+	default:
+		return false, false
+	}
+
+	return false, true
+}
+
+// SetFocused implements the screen.Focuser interface.
+func (in *Input) SetFocused(focused bool) {
+	in.mu.Lock()
+	in.focused = focused
+	in.mu.Unlock()
+}
+
+// Cursor implements the screen.Cursorer interface.
+func (in *Input) Cursor() (int, int, ds.CursorShape, bool) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	return in.cursor - in.offset, 0, ds.CursorBar, in.focused
+}
+
+// Draw implements the screen.Drawer interface. The input uses the first row of
+// the table and scrolls horizontally to keep the cursor visible.
+func (in *Input) Draw(table *dtb.Table, x, y *int) error {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+
+	width := table.Width()
+	if width <= 0 || table.Height() <= 0 {
+		return nil
+	}
+
+	role := theme.RoleInput
+	if in.err != nil {
+		role = theme.RoleError
+	}
+
+	style := role.Style()
+	selection_style := theme.RoleSelection.Style()
+
+	// One cell is kept for the cursor at the end of the value.
+	if in.cursor < in.offset {
+		in.offset = in.cursor
+	} else if in.cursor >= in.offset+width {
+		in.offset = in.cursor - width + 1
+	}
+
+	in.offset = max(min(in.offset, len(in.value)), 0)
+
+	for col := 0; col < width; col++ {
+		table.WriteAt(col, 0, dtb.NewCell(' ', style))
+	}
+
+	if len(in.value) == 0 {
+		muted := theme.RoleMuted.Style()
+		col := 0
+
+		for _, r := range in.placeholder {
+			if col >= width {
+				break
+			}
+
+			table.WriteAt(col, 0, dtb.NewCell(r, muted))
+			col++
+		}
+
+		return nil
+	}
+
+	from, to, has_selection := in.selection()
+
+	for col := 0; col < width && in.offset+col < len(in.value); col++ {
+		idx := in.offset + col
+
+		r := in.value[idx]
+		if in.mask != 0 {
+			r = in.mask
+		}
+
+		st := style
+		if has_selection && idx >= from && idx < to {
+			st = selection_style
+		}
+
+		table.WriteAt(col, 0, dtb.NewCell(r, st))
+	}
+
+	return nil
+}
@@ -0,0 +1,113 @@
+package widget
+
+import (
+	"errors"
+	"testing"
+	"unicode"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+func type_keys(in *Input, keys ...any) {
+	for _, k := range keys {
+		switch k := k.(type) {
+		case string:
+			for _, r := range k {
+				in.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+			}
+		case tcell.Key:
+			in.HandleEvent(tcell.NewEventKey(k, 0, tcell.ModNone))
+		case *tcell.EventKey:
+			in.HandleEvent(k)
+		}
+	}
+}
+
+func TestInputEditing(t *testing.T) {
+	in := NewInput("name")
+
+	type_keys(in,
+		"hello world",
+		tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModCtrl),
+		tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModShift),
+		tcell.KeyCtrlX,
+		tcell.KeyHome,
+		tcell.KeyCtrlV,
+		" ",
+	)
+
+	if got := in.Value(); got != "world hello " {
+		t.Fatalf("Expected %q, but got %q", "world hello ", got)
+	}
+
+	var submitted []string
+
+	in.OnSubmit(func(value string) {
+		submitted = append(submitted, value)
+	})
+
+	in.SetValidator(func(value string) error {
+		if value == "" {
+			return errors.New("empty")
+		}
+
+		return nil
+	})
+
+	type_keys(in, tcell.KeyEnter, tcell.KeyEnter, "second", tcell.KeyEnter)
+
+	if len(submitted) != 2 || submitted[1] != "second" {
+		t.Fatalf("Expected two submitted values, but got %q", submitted)
+	}
+
+	if in.Err() == nil {
+		t.Fatalf("Expected the empty value to be invalid")
+	}
+
+	type_keys(in, "draft", tcell.KeyUp, tcell.KeyUp)
+
+	if got := in.Value(); got != "world hello " {
+		t.Fatalf("Expected the oldest history entry, but got %q", got)
+	}
+
+	type_keys(in, tcell.KeyDown, tcell.KeyDown)
+
+	if got := in.Value(); got != "draft" {
+		t.Fatalf("Expected the draft back, but got %q", got)
+	}
+}
+
+func TestInputMaskAndScroll(t *testing.T) {
+	in := NewInput("")
+	in.SetMask('*')
+	in.SetAccept(unicode.IsDigit)
+
+	type_keys(in, "12a345678")
+
+	if got := in.Value(); got != "12345678" {
+		t.Fatalf("Expected only digits, but got %q", got)
+	}
+
+	table, err := dtb.NewTable(5, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	x, y := 0, 0
+
+	err = in.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if got := table.GetLines()[0]; got != "**** " {
+		t.Fatalf("Expected the end of the masked value, but got %q", got)
+	}
+
+	in.SetFocused(true)
+
+	if cx, _, _, ok := in.Cursor(); !ok || cx != 4 {
+		t.Fatalf("Expected the cursor in the last cell, but got %d (%t)", cx, ok)
+	}
+}
@@ -0,0 +1,791 @@
+package widget
+
+import (
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"unicode"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// ListSource is the data of a list. The list only asks for the items it draws, so
+// a source can hold any number of items.
+type ListSource interface {
+	// Count returns the number of items.
+	//
+	// Returns:
+	//   - int: The number of items.
+	Count() int
+
+	// Item returns the displayer that draws an item. It is drawn on a table of the
+	// width of the list and of one row; nil cells show the style of the row.
+	//
+	// Parameters:
+	//   - idx: The index of the item. Assumed to be in [0, Count()).
+	//
+	// Returns:
+	//   - dtb.Displayer: The displayer. Nil draws an empty row.
+	Item(idx int) dtb.Displayer
+
+	// Text returns the text of an item, which type-ahead filtering matches against.
+	//
+	// Parameters:
+	//   - idx: The index of the item. Assumed to be in [0, Count()).
+	//
+	// Returns:
+	//   - string: The text.
+	Text(idx int) string
+}
+
+// StringSource is a list source of plain strings.
+type StringSource []string
+
+// Count implements the ListSource interface.
+func (ss StringSource) Count() int {
+	return len(ss)
+}
+
+// Item implements the ListSource interface.
+func (ss StringSource) Item(idx int) dtb.Displayer {
+	return TextRow(ss[idx], theme.RoleText)
+}
+
+// Text implements the ListSource interface.
+func (ss StringSource) Text(idx int) string {
+	return ss[idx]
+}
+
+// text_row is a displayer that draws a line of text.
+type text_row struct {
+	// text is the text.
+	text string
+
+	// role is the role of the style of the text.
+	role theme.Role
+}
+
+// Draw implements the table.Displayer interface.
+func (tr text_row) Draw(table *dtb.Table, x, y *int) error {
+	style := tr.role.Style()
+	col := *x
+
+	for _, r := range tr.text {
+		table.WriteAt(col, *y, dtb.NewCell(r, style))
+		col++
+	}
+
+	*x = col
+
+	return nil
+}
+
+// TextRow creates a displayer that draws a line of text from the given
+// coordinates; for instance, for the items of a list source.
+//
+// Parameters:
+//   - text: The text. Assumed to not contain newlines.
+//   - role: The role of the style of the text.
+//
+// Returns:
+//   - dtb.Displayer: The displayer. Never returns nil.
+func TextRow(text string, role theme.Role) dtb.Displayer {
+	return text_row{
+		text: text,
+		role: role,
+	}
+}
+
+// fuzzy_score is a helper function that checks whether all the characters of the
+// query appear, in order, in the text, ignoring case.
+//
+// Parameters:
+//   - text: The text.
+//   - query: The query. Assumed to be lower case.
+//
+// Returns:
+//   - int: The score of the match; higher is better. Consecutive characters and
+//     matches at the start of words score more, and late matches score less.
+//   - bool: False if the text does not match.
+func fuzzy_score(text string, query []rune) (int, bool) {
+	if len(query) == 0 {
+		return 0, true
+	}
+
+	score := 0
+	qi := 0
+	prev_match := -2
+	prev := ' '
+	pos := 0
+
+	for _, r := range text {
+		if unicode.ToLower(r) == query[qi] {
+			switch {
+			case prev_match == pos-1:
+				score += 5
+			case !unicode.IsLetter(prev) && !unicode.IsDigit(prev):
+				score += 3
+			}
+
+			score -= min(pos, 10)
+			prev_match = pos
+			qi++
+
+			if qi == len(query) {
+				return score, true
+			}
+		}
+
+		prev = r
+		pos++
+	}
+
+	return 0, false
+}
+
+// List is a vertical list of selectable items, meant to be the element of a node
+// of a screen.Screen. Only the visible items are asked to the source and drawn.
+//
+// Keys:
+//   - Up/Down, PgUp/PgDn and Home/End move the cursor; Enter activates the item
+//     under it.
+//   - In multi-select mode, Space toggles the item under the cursor and Ctrl+A
+//     toggles all the shown items.
+//   - Other characters filter the items with a fuzzy match on their text, best
+//     matches first; Backspace edits the filter and Esc clears it.
+//
+// With the mouse, a click moves the cursor (Ctrl+click toggles the item in
+// multi-select mode), a click on the item under the cursor activates it and the
+// wheel scrolls.
+type List struct {
+	// source is the data of the list.
+	source ListSource
+
+	// shown are the indices of the items shown, when filtered. Nil if all the
+	// items are shown in order.
+	shown []int
+
+	// query is the filter.
+	query []rune
+
+	// cursor is the index of the cursor among the shown items.
+	cursor int
+
+	// top is the index of the first shown item drawn.
+	top int
+
+	// follow is true if the next draw must scroll to the cursor.
+	follow bool
+
+	// height is the number of items drawn in the last draw.
+	height int
+
+	// area is the area of the node of the list.
+	area ds.Rect
+
+	// multi is true if several items can be selected.
+	multi bool
+
+	// selected are the indices of the selected items in multi-select mode.
+	selected map[int]struct{}
+
+	// on_activate is called with the index of an item when it is activated.
+	on_activate func(idx int)
+
+	// focused is true if the list has the focus.
+	focused bool
+
+	// pressed is true while the first mouse button is held, so that the motion
+	// events reported while dragging do not click again.
+	pressed bool
+
+	// mu is the mutex of the list.
+	mu sync.Mutex
+}
+
+// NewList creates a new list.
+//
+// Parameters:
+//   - source: The data of the list. Nil for an empty list.
+//
+// Returns:
+//   - *List: The new list. Never returns nil.
+func NewList(source ListSource) *List {
+	if source == nil {
+		source = StringSource(nil)
+	}
+
+	return &List{
+		source:   source,
+		selected: make(map[int]struct{}),
+	}
+}
+
+// SetSource replaces the data of the list, keeps the filter and clears the
+// selection. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - source: The data of the list. Nil for an empty list.
+func (l *List) SetSource(source ListSource) {
+	if l == nil {
+		return
+	}
+
+	if source == nil {
+		source = StringSource(nil)
+	}
+
+	l.mu.Lock()
+
+	l.source = source
+	clear(l.selected)
+	l.cursor = 0
+	l.top = 0
+	l.filter()
+
+	l.mu.Unlock()
+}
+
+// Refresh re-applies the filter after the items of the source changed; the cursor
+// is clamped to the items. Does nothing with a nil receiver.
+func (l *List) Refresh() {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	l.filter()
+	l.mu.Unlock()
+}
+
+// SetMulti enables or disables multi-select mode. Disabling it clears the
+// selection. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - multi: True to enable multi-select mode.
+func (l *List) SetMulti(multi bool) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+
+	l.multi = multi
+
+	if !multi {
+		clear(l.selected)
+	}
+
+	l.mu.Unlock()
+}
+
+// OnActivate sets the function called when an item is activated with Enter or a
+// click. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - fn: The function, called with the index of the item in the source. Nil for
+//     none.
+func (l *List) OnActivate(fn func(idx int)) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	l.on_activate = fn
+	l.mu.Unlock()
+}
+
+// Current returns the item under the cursor.
+//
+// Returns:
+//   - int: The index of the item in the source. -1 if no item is shown.
+func (l *List) Current() int {
+	if l == nil {
+		return -1
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.current()
+}
+
+// SetCurrent moves the cursor to the given item, if it is shown. Does nothing with
+// a nil receiver.
+//
+// Parameters:
+//   - idx: The index of the item in the source.
+func (l *List) SetCurrent(idx int) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.shown == nil {
+		if idx >= 0 && idx < l.source.Count() {
+			l.move(idx)
+		}
+
+		return
+	}
+
+	if pos := slices.Index(l.shown, idx); pos != -1 {
+		l.move(pos)
+	}
+}
+
+// Selected returns the selected items in multi-select mode.
+//
+// Returns:
+//   - []int: The indices of the items in the source, in increasing order.
+func (l *List) Selected() []int {
+	if l == nil {
+		return nil
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	indices := make([]int, 0, len(l.selected))
+
+	for idx := range l.selected {
+		indices = append(indices, idx)
+	}
+
+	slices.Sort(indices)
+
+	return indices
+}
+
+// Filter returns the type-ahead filter.
+//
+// Returns:
+//   - string: The filter. Empty if the items are not filtered.
+func (l *List) Filter() string {
+	if l == nil {
+		return ""
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return string(l.query)
+}
+
+// SetFilter changes the type-ahead filter. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - query: The filter. Empty to show all the items.
+func (l *List) SetFilter(query string) {
+	if l == nil {
+		return
+	}
+
+	l.mu.Lock()
+
+	l.query = []rune(query)
+	l.filter()
+
+	l.mu.Unlock()
+}
+
+// count is a helper method that returns the number of shown items.
+//
+// Returns:
+//   - int: The number of shown items.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) count() int {
+	if l.shown == nil {
+		return l.source.Count()
+	}
+
+	return len(l.shown)
+}
+
+// index is a helper method that returns the index in the source of a shown item.
+//
+// Parameters:
+//   - pos: The position of the item among the shown items.
+//
+// Returns:
+//   - int: The index in the source.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) index(pos int) int {
+	if l.shown == nil {
+		return pos
+	}
+
+	return l.shown[pos]
+}
+
+// current is a helper method that returns the index in the source of the item
+// under the cursor.
+//
+// Returns:
+//   - int: The index. -1 if no item is shown.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) current() int {
+	if l.cursor >= l.count() {
+		return -1
+	}
+
+	return l.index(l.cursor)
+}
+
+// filter is a helper method that computes the shown items from the query. The
+// cursor goes to the best match or, once the filter is cleared, stays on its item.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) filter() {
+	if len(l.query) == 0 {
+		if l.shown != nil {
+			l.cursor = max(l.current(), 0)
+		}
+
+		l.shown = nil
+	} else {
+		query := []rune(strings.ToLower(string(l.query)))
+
+		type match struct {
+			idx, score int
+		}
+
+		var matches []match
+
+		for idx := range l.source.Count() {
+			score, ok := fuzzy_score(l.source.Text(idx), query)
+			if ok {
+				matches = append(matches, match{idx, score})
+			}
+		}
+
+		sort.SliceStable(matches, func(i, j int) bool {
+			return matches[i].score > matches[j].score
+		})
+
+		l.shown = make([]int, 0, len(matches))
+
+		for _, m := range matches {
+			l.shown = append(l.shown, m.idx)
+		}
+
+		l.cursor = 0
+		l.top = 0
+	}
+
+	l.move(l.cursor)
+}
+
+// move is a helper method that moves the cursor, clamped to the shown items.
+//
+// Parameters:
+//   - pos: The position of the cursor among the shown items.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) move(pos int) {
+	l.cursor = max(min(pos, l.count()-1), 0)
+	l.follow = true
+}
+
+// toggle is a helper method that selects or unselects an item.
+//
+// Parameters:
+//   - idx: The index of the item in the source.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) toggle(idx int) {
+	if _, ok := l.selected[idx]; ok {
+		delete(l.selected, idx)
+	} else {
+		l.selected[idx] = struct{}{}
+	}
+}
+
+// activate is a helper method that returns the function that activates the item
+// under the cursor, to be called once unlocked.
+//
+// Returns:
+//   - func(): The function. Nil if there is nothing to call.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) activate() func() {
+	idx := l.current()
+	fn := l.on_activate
+
+	if idx == -1 || fn == nil {
+		return nil
+	}
+
+	return func() {
+		fn(idx)
+	}
+}
+
+// HandleEvent implements the screen.Handler interface.
+func (l *List) HandleEvent(ev tcell.Event) bool {
+	l.mu.Lock()
+
+	var consumed bool
+	var after func()
+
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		consumed, after = l.handle_key(ev)
+	case *tcell.EventMouse:
+		consumed, after = l.handle_mouse(ev)
+	}
+
+	l.mu.Unlock()
+
+	if after != nil {
+		after()
+	}
+
+	return consumed
+}
+
+// handle_key is a helper method that handles a key.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the key was consumed.
+//   - func(): The function to call once unlocked. Nil for none.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) handle_key(ev *tcell.EventKey) (bool, func()) {
+	page := max(l.height-1, 1)
+
+	switch ev.Key() {
+	case tcell.KeyUp:
+		l.move(l.cursor - 1)
+	case tcell.KeyDown:
+		l.move(l.cursor + 1)
+	case tcell.KeyPgUp:
+		l.move(l.cursor - page)
+	case tcell.KeyPgDn:
+		l.move(l.cursor + page)
+	case tcell.KeyHome:
+		l.move(0)
+	case tcell.KeyEnd:
+		l.move(l.count() - 1)
+	case tcell.KeyEnter:
+		return true, l.activate()
+	case tcell.KeyCtrlA:
+		if !l.multi {
+			return false, nil
+		}
+
+		for pos := range l.count() {
+			l.toggle(l.index(pos))
+		}
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(l.query) == 0 {
+			return false, nil
+		}
+
+		l.query = l.query[:len(l.query)-1]
+		l.filter()
+	case tcell.KeyEscape:
+		if len(l.query) == 0 {
+			return false, nil
+		}
+
+		l.query = nil
+		l.filter()
+	case tcell.KeyRune:
+		if ev.Rune() == ' ' && l.multi {
+			if idx := l.current(); idx != -1 {
+				l.toggle(idx)
+			}
+
+			break
+		}
+
+		l.query = append(l.query, ev.Rune())
+		l.filter()
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// handle_mouse is a helper method that handles a mouse event.
+//
+// Parameters:
+//   - ev: The mouse event.
+//
+// Returns:
+//   - bool: True if the event was consumed.
+//   - func(): The function to call once unlocked. Nil for none.
+//
+// Assertions:
+//   - l.mu is locked.
+func (l *List) handle_mouse(ev *tcell.EventMouse) (bool, func()) {
+	switch buttons := ev.Buttons(); {
+	case buttons&tcell.WheelUp != 0:
+		l.top = max(l.top-3, 0)
+		l.follow = false
+	case buttons&tcell.WheelDown != 0:
+		l.top = max(min(l.top+3, l.count()-l.height), 0)
+		l.follow = false
+	case buttons&tcell.Button1 != 0:
+		if l.pressed {
+			return true, nil
+		}
+
+		_, y := ev.Position()
+
+		pos := l.top + y - l.area.Y
+		if pos < 0 || pos >= l.count() || y-l.area.Y >= l.height {
+			return false, nil
+		}
+
+		l.pressed = true
+
+		if l.multi && ev.Modifiers()&tcell.ModCtrl != 0 {
+			l.toggle(l.index(pos))
+		} else if pos == l.cursor {
+			return true, l.activate()
+		}
+
+		l.move(pos)
+	default:
+		if !l.pressed {
+			return false, nil
+		}
+
+		l.pressed = false
+	}
+
+	return true, nil
+}
+
+// SetFocused implements the screen.Focuser interface.
+func (l *List) SetFocused(focused bool) {
+	l.mu.Lock()
+	l.focused = focused
+	l.mu.Unlock()
+}
+
+// SetArea implements the screen.Arranger interface.
+func (l *List) SetArea(area ds.Rect) {
+	l.mu.Lock()
+	l.area = area
+	l.mu.Unlock()
+}
+
+// Draw implements the screen.Drawer interface. The list fills the table; while the
+// items are filtered, the last row shows the filter.
+func (l *List) Draw(table *dtb.Table, x, y *int) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	width, height := table.Width(), table.Height()
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	if len(l.query) > 0 && height > 1 {
+		height--
+
+		muted := theme.RoleMuted.Style()
+		line := []rune("/" + string(l.query))
+
+		for col := range width {
+			r := ' '
+			if col < len(line) {
+				r = line[col]
+			}
+
+			table.WriteAt(col, height, dtb.NewCell(r, muted))
+		}
+	}
+
+	l.height = height
+
+	if l.follow {
+		if l.cursor < l.top {
+			l.top = l.cursor
+		} else if l.cursor >= l.top+height {
+			l.top = l.cursor - height + 1
+		}
+
+		l.follow = false
+	}
+
+	count := l.count()
+	l.top = max(min(l.top, count-height), 0)
+
+	gutter := 0
+	if l.multi {
+		gutter = 2
+	}
+
+	for row := range height {
+		pos := l.top + row
+
+		role := theme.RoleText
+
+		switch {
+		case pos >= count:
+		case pos == l.cursor && l.focused:
+			role = theme.RoleMenuSelected
+		case pos == l.cursor:
+			role = theme.RoleSelection
+		}
+
+		style := role.Style()
+
+		for col := range width {
+			table.WriteAt(col, row, dtb.NewCell(' ', style))
+		}
+
+		if pos >= count {
+			continue
+		}
+
+		idx := l.index(pos)
+
+		if _, ok := l.selected[idx]; ok && l.multi {
+			table.WriteAt(0, row, dtb.NewCell('*', style))
+		}
+
+		err := dtb.DrawClipped(table, l.source.Item(idx), gutter, row, width-gutter, 1)
+		if err != nil {
+			return err
+		}
+
+		if role != theme.RoleText {
+			// The background of the row wins over the one of the item.
+			_, bg, _ := style.Decompose()
+
+			for col := gutter; col < width; col++ {
+				if cell := table.CellAt(col, row); cell != nil {
+					table.WriteAt(col, row, dtb.NewCell(cell.Char, cell.Style.Background(bg)))
+				}
+			}
+		}
+	}
+
+	return nil
+}
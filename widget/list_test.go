@@ -0,0 +1,134 @@
+package widget
+
+import (
+	"slices"
+	"strconv"
+	"testing"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// counting_source is a source of numbered items that counts the items drawn.
+type counting_source struct {
+	count int
+	drawn int
+}
+
+func (cs *counting_source) Count() int {
+	return cs.count
+}
+
+func (cs *counting_source) Item(idx int) dtb.Displayer {
+	cs.drawn++
+
+	return TextRow(cs.Text(idx), theme.RoleText)
+}
+
+func (cs *counting_source) Text(idx int) string {
+	return "item " + strconv.Itoa(idx)
+}
+
+func TestListVirtualised(t *testing.T) {
+	source := &counting_source{count: 200000}
+
+	l := NewList(source)
+
+	l.HandleEvent(tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone))
+
+	table, err := dtb.NewTable(12, 3)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	x, y := 0, 0
+
+	err = l.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if source.drawn != 3 {
+		t.Fatalf("Expected 3 items to be drawn, but got %d", source.drawn)
+	}
+
+	want := []string{"item 199997 ", "item 199998 ", "item 199999 "}
+
+	if got := table.GetLines(); !slices.Equal(got, want) {
+		t.Fatalf("Expected %q, but got %q", want, got)
+	}
+}
+
+func TestListFilterAndSelect(t *testing.T) {
+	l := NewList(StringSource{"alpha", "beta", "gamma", "delta", "epsilon"})
+	l.SetMulti(true)
+
+	var activated []int
+
+	l.OnActivate(func(idx int) {
+		activated = append(activated, idx)
+	})
+
+	for _, r := range "ta" {
+		l.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+
+	// "beta" matches "ta" contiguously, earlier than "delta".
+	if got := l.Current(); got != 1 {
+		t.Fatalf("Expected the best match to be current, but got %d", got)
+	}
+
+	l.HandleEvent(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone))
+	l.HandleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	l.HandleEvent(tcell.NewEventKey(tcell.KeyRune, ' ', tcell.ModNone))
+	l.HandleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+
+	if got := l.Selected(); !slices.Equal(got, []int{1, 3}) {
+		t.Fatalf("Expected items 1 and 3 to be selected, but got %v", got)
+	}
+
+	if got := l.Current(); got != 3 {
+		t.Fatalf("Expected the cursor to stay on item 3, but got %d", got)
+	}
+
+	if got := l.Filter(); got != "" {
+		t.Fatalf("Expected the filter to be cleared, but got %q", got)
+	}
+
+	table, err := dtb.NewTable(8, 5)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	x, y := 0, 0
+
+	err = l.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if got := table.GetLines()[3]; got != "* delta " {
+		t.Fatalf("Expected a selected row, but got %q", got)
+	}
+
+	l.SetArea(ds.Rect{X: 2, Y: 4, Width: 8, Height: 5})
+
+	// A press below the list is not consumed, so its release may go elsewhere; it
+	// must not swallow the next click.
+	if l.HandleEvent(tcell.NewEventMouse(3, 9, tcell.Button1, tcell.ModNone)) {
+		t.Fatalf("Expected a press below the list not to be consumed")
+	}
+
+	l.HandleEvent(tcell.NewEventMouse(3, 6, tcell.Button1, tcell.ModNone))
+	l.HandleEvent(tcell.NewEventMouse(3, 6, tcell.ButtonNone, tcell.ModNone))
+	l.HandleEvent(tcell.NewEventMouse(3, 6, tcell.Button1, tcell.ModNone))
+
+	// Motion while the button is held is not another click.
+	l.HandleEvent(tcell.NewEventMouse(3, 6, tcell.Button1, tcell.ModNone))
+
+	if !slices.Equal(activated, []int{2}) {
+		t.Fatalf("Expected item 2 to be activated, but got %v", activated)
+	}
+}
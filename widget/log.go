@@ -0,0 +1,788 @@
+package widget
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+
+	"github.com/PlayerR9/display/ansi"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	gcers "github.com/PlayerR9/go-commons/errors"
+	"github.com/gdamore/tcell"
+)
+
+const (
+	// DefaultLogCapacity is the default number of lines kept by a log view.
+	DefaultLogCapacity int = 10000
+)
+
+// log_entry is a line of a log view.
+type log_entry struct {
+	// level is the level of the line.
+	level slog.Level
+
+	// text is the text of the line, without escape sequences, which the filter
+	// matches against.
+	text string
+
+	// cells are the cells of the line.
+	cells []*dtb.Cell
+
+	// spans are the runs of text of the line, whose styles are taken from the
+	// current theme when drawn. Nil for lines of cells.
+	spans []log_span
+}
+
+// log_span is a run of text of a line drawn with the style of a role.
+type log_span struct {
+	// text is the text of the run, without control characters.
+	text string
+
+	// role is the role of the style of the text.
+	role theme.Role
+}
+
+// span_cells is a helper function that converts the runs of text of a line to
+// cells styled from the current theme.
+//
+// Parameters:
+//   - spans: The runs of text.
+//
+// Returns:
+//   - []*dtb.Cell: The cells.
+func span_cells(spans []log_span) []*dtb.Cell {
+	var cells []*dtb.Cell
+
+	for _, span := range spans {
+		style := span.role.Style()
+
+		for _, r := range span.text {
+			cells = append(cells, dtb.NewCell(r, style))
+		}
+	}
+
+	return cells
+}
+
+// level_role is a helper function that returns the role of the lines of a level
+// that do not have a style of their own.
+//
+// Parameters:
+//   - level: The level.
+//
+// Returns:
+//   - theme.Role: The role.
+func level_role(level slog.Level) theme.Role {
+	switch {
+	case level >= slog.LevelError:
+		return theme.RoleError
+	case level < slog.LevelInfo:
+		return theme.RoleMuted
+	default:
+		return theme.RoleText
+	}
+}
+
+// LogView is a pane that shows the newest lines of a log, meant to be the element
+// of a node of a screen.Screen. Lines can be appended from any goroutine; they are
+// kept in a ring buffer and parsed once, so only the visible lines are drawn.
+//
+// The view follows the newest lines until the user scrolls up, and follows them
+// again once scrolled back to the bottom.
+//
+// Keys:
+//   - Up/Down and PgUp/PgDn scroll; Home goes to the oldest line and End follows
+//     the newest ones.
+//   - Other characters filter the lines that contain them, ignoring case;
+//     Backspace edits the filter and Esc clears it.
+//
+// With the mouse, the wheel scrolls.
+type LogView struct {
+	// entries is the ring buffer of lines.
+	entries []log_entry
+
+	// start is the index in entries of the oldest line.
+	start int
+
+	// count is the number of lines in entries.
+	count int
+
+	// next is the sequence number of the next line appended. The sequence number
+	// of a line is the number of lines appended before it.
+	next uint64
+
+	// partial is the last, unterminated line written through Write.
+	partial []byte
+
+	// follow is true if the view shows the newest lines.
+	follow bool
+
+	// top is the sequence number of the first line drawn, when not following.
+	top uint64
+
+	// paused_at is the value of next when the view stopped following.
+	paused_at uint64
+
+	// height is the number of lines drawn in the last draw.
+	height int
+
+	// query is the filter, in lower case.
+	query []rune
+
+	// min_level is the level below which lines are hidden.
+	min_level slog.Level
+
+	// on_append is called after lines are appended.
+	on_append func()
+
+	// mu is the mutex of the view.
+	mu sync.Mutex
+}
+
+// NewLogView creates a new, empty log view.
+//
+// Parameters:
+//   - capacity: The number of lines kept; the oldest lines are dropped beyond it.
+//     If not positive, DefaultLogCapacity is used.
+//
+// Returns:
+//   - *LogView: The new log view. Never returns nil.
+func NewLogView(capacity int) *LogView {
+	if capacity <= 0 {
+		capacity = DefaultLogCapacity
+	}
+
+	return &LogView{
+		entries:   make([]log_entry, capacity),
+		follow:    true,
+		min_level: slog.LevelDebug,
+	}
+}
+
+// push is a helper method that adds a line to the ring buffer, dropping the oldest
+// one if it is full.
+//
+// Parameters:
+//   - entry: The line.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) push(entry log_entry) {
+	capacity := len(lv.entries)
+
+	if lv.count < capacity {
+		lv.entries[(lv.start+lv.count)%capacity] = entry
+		lv.count++
+	} else {
+		lv.entries[lv.start] = entry
+		lv.start = (lv.start + 1) % capacity
+	}
+
+	lv.next++
+}
+
+// append_lines is a helper method that adds lines of text to the ring buffer.
+//
+// Parameters:
+//   - level: The level of the lines.
+//   - text: The text. Each line of it is a line of the view.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) append_lines(level slog.Level, text string) {
+	base := level_role(level).Style()
+
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		line = strings.TrimSuffix(line, "\r")
+
+		lv.push(log_entry{
+			level: level,
+			text:  ansi.Strip(line),
+			cells: ansi.Parse(line, base),
+		})
+	}
+}
+
+// Append appends lines to the view. Escape sequences that select colours and
+// attributes are applied; the other ones are dropped. Safe to call from any
+// goroutine. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - level: The level of the lines. Lines without colours of their own are drawn
+//     with the error role from slog.LevelError and the muted role below
+//     slog.LevelInfo.
+//   - text: The text. Each line of it is a line of the view.
+func (lv *LogView) Append(level slog.Level, text string) {
+	if lv == nil {
+		return
+	}
+
+	lv.mu.Lock()
+
+	lv.append_lines(level, text)
+	after := lv.on_append
+
+	lv.mu.Unlock()
+
+	if after != nil {
+		after()
+	}
+}
+
+// AppendCells appends a line of styled cells to the view; for instance, a record
+// formatted by a log handler. Safe to call from any goroutine. Does nothing with a
+// nil receiver.
+//
+// Parameters:
+//   - level: The level of the line.
+//   - cells: The cells of the line. Nil cells are drawn as spaces. The view keeps
+//     the slice, so it must not be modified afterwards.
+func (lv *LogView) AppendCells(level slog.Level, cells []*dtb.Cell) {
+	if lv == nil {
+		return
+	}
+
+	var builder strings.Builder
+
+	for _, cell := range cells {
+		if cell == nil {
+			builder.WriteRune(' ')
+		} else {
+			builder.WriteRune(cell.Char)
+		}
+	}
+
+	lv.add(log_entry{
+		level: level,
+		text:  builder.String(),
+		cells: cells,
+	})
+}
+
+// append_spans is a helper method that appends a line made of runs of text, whose
+// styles follow the theme; for instance, a record formatted by a log handler.
+//
+// Parameters:
+//   - level: The level of the line.
+//   - spans: The runs of text of the line. The view keeps the slice, so it must
+//     not be modified afterwards.
+func (lv *LogView) append_spans(level slog.Level, spans []log_span) {
+	var builder strings.Builder
+
+	for _, span := range spans {
+		builder.WriteString(span.text)
+	}
+
+	lv.add(log_entry{
+		level: level,
+		text:  builder.String(),
+		spans: spans,
+	})
+}
+
+// add is a helper method that adds a line to the view and calls on_append.
+//
+// Parameters:
+//   - entry: The line.
+func (lv *LogView) add(entry log_entry) {
+	lv.mu.Lock()
+
+	lv.push(entry)
+	after := lv.on_append
+
+	lv.mu.Unlock()
+
+	if after != nil {
+		after()
+	}
+}
+
+// Write implements the io.Writer interface: complete lines are appended with
+// slog.LevelInfo, and an unterminated last line waits for the next write. Safe to
+// call from any goroutine.
+//
+// Errors:
+//   - gcers.NilReceiver: If the receiver is nil.
+func (lv *LogView) Write(p []byte) (int, error) {
+	if lv == nil {
+		return 0, gcers.NilReceiver
+	}
+
+	lv.mu.Lock()
+
+	data := append(lv.partial, p...)
+
+	idx := bytes.LastIndexByte(data, '\n')
+	if idx == -1 {
+		lv.partial = data
+
+		lv.mu.Unlock()
+
+		return len(p), nil
+	}
+
+	lv.partial = append([]byte(nil), data[idx+1:]...)
+	lv.append_lines(slog.LevelInfo, string(data[:idx]))
+
+	after := lv.on_append
+
+	lv.mu.Unlock()
+
+	if after != nil {
+		after()
+	}
+
+	return len(p), nil
+}
+
+// Clear removes all the lines and follows the newest ones again. Does nothing with
+// a nil receiver.
+func (lv *LogView) Clear() {
+	if lv == nil {
+		return
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	clear(lv.entries)
+	lv.start = 0
+	lv.count = 0
+	lv.partial = nil
+	lv.follow = true
+}
+
+// Len returns the number of lines kept.
+//
+// Returns:
+//   - int: The number of lines. 0 with a nil receiver.
+func (lv *LogView) Len() int {
+	if lv == nil {
+		return 0
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	return lv.count
+}
+
+// OnAppend sets the function called after lines are appended; for instance, the
+// Invalidate method of the screen. It is called from the goroutine that appended
+// the lines. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - fn: The function. Nil for none.
+func (lv *LogView) OnAppend(fn func()) {
+	if lv == nil {
+		return
+	}
+
+	lv.mu.Lock()
+	lv.on_append = fn
+	lv.mu.Unlock()
+}
+
+// Following tells whether the view follows the newest lines.
+//
+// Returns:
+//   - bool: True if the view follows the newest lines. False with a nil receiver.
+func (lv *LogView) Following() bool {
+	if lv == nil {
+		return false
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	return lv.follow
+}
+
+// SetFollow makes the view follow the newest lines, or stop following them at the
+// lines currently shown. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - follow: True to follow the newest lines.
+func (lv *LogView) SetFollow(follow bool) {
+	if lv == nil {
+		return
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	if follow {
+		lv.follow = true
+	} else if lv.follow {
+		lv.pause(lv.tail_top())
+	}
+}
+
+// Filter returns the substring filter.
+//
+// Returns:
+//   - string: The filter. Empty if the lines are not filtered by text.
+func (lv *LogView) Filter() string {
+	if lv == nil {
+		return ""
+	}
+
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	return string(lv.query)
+}
+
+// SetFilter changes the substring filter. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - query: The text the shown lines contain, ignoring case. Empty to show all
+//     the lines.
+func (lv *LogView) SetFilter(query string) {
+	if lv == nil {
+		return
+	}
+
+	lv.mu.Lock()
+	lv.query = []rune(strings.ToLower(query))
+	lv.mu.Unlock()
+}
+
+// SetLevel hides the lines below the given level. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - level: The lowest level shown. slog.LevelDebug, the default, shows the
+//     lines of all the standard levels.
+func (lv *LogView) SetLevel(level slog.Level) {
+	if lv == nil {
+		return
+	}
+
+	lv.mu.Lock()
+	lv.min_level = level
+	lv.mu.Unlock()
+}
+
+// at is a helper method that returns the line with the given sequence number.
+//
+// Parameters:
+//   - seq: The sequence number. Assumed to be kept.
+//
+// Returns:
+//   - *log_entry: The line. Never returns nil.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) at(seq uint64) *log_entry {
+	offset := int(seq - lv.oldest())
+
+	return &lv.entries[(lv.start+offset)%len(lv.entries)]
+}
+
+// oldest is a helper method that returns the sequence number of the oldest line.
+//
+// Returns:
+//   - uint64: The sequence number. Equal to lv.next if there are no lines.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) oldest() uint64 {
+	return lv.next - uint64(lv.count)
+}
+
+// matches is a helper method that tells whether a line passes the filters.
+//
+// Parameters:
+//   - entry: The line.
+//
+// Returns:
+//   - bool: True if the line is shown.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) matches(entry *log_entry) bool {
+	if entry.level < lv.min_level {
+		return false
+	}
+
+	if len(lv.query) == 0 {
+		return true
+	}
+
+	return strings.Contains(strings.ToLower(entry.text), string(lv.query))
+}
+
+// walk is a helper method that moves over shown lines.
+//
+// Parameters:
+//   - seq: The sequence number to start from.
+//   - delta: The number of shown lines to move over; negative to move towards the
+//     oldest lines.
+//
+// Returns:
+//   - uint64: The sequence number of the shown line reached, or of the last shown
+//     line met if there are not enough. seq itself if there is none.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) walk(seq uint64, delta int) uint64 {
+	result := seq
+
+	if delta < 0 {
+		for s := seq; s > lv.oldest() && delta < 0; {
+			s--
+
+			if lv.matches(lv.at(s)) {
+				result = s
+				delta++
+			}
+		}
+	} else {
+		for s := seq + 1; s < lv.next && delta > 0; s++ {
+			if lv.matches(lv.at(s)) {
+				result = s
+				delta--
+			}
+		}
+	}
+
+	return result
+}
+
+// tail_top is a helper method that returns the first line drawn when following the
+// newest lines.
+//
+// Returns:
+//   - uint64: The sequence number of the line.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) tail_top() uint64 {
+	return lv.walk(lv.next, -max(lv.height, 1))
+}
+
+// pause is a helper method that stops following the newest lines.
+//
+// Parameters:
+//   - top: The sequence number of the first line drawn.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) pause(top uint64) {
+	if lv.follow {
+		lv.paused_at = lv.next
+	}
+
+	lv.follow = false
+	lv.top = top
+}
+
+// scroll is a helper method that scrolls the view. The view follows the newest
+// lines once they are all drawn.
+//
+// Parameters:
+//   - delta: The number of shown lines to scroll by; negative to scroll up.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) scroll(delta int) {
+	top := lv.top
+	if lv.follow {
+		top = lv.tail_top()
+	}
+
+	top = max(top, lv.oldest())
+
+	if delta != 0 {
+		top = lv.walk(top, delta)
+	}
+
+	if top >= lv.tail_top() {
+		lv.follow = true
+	} else {
+		lv.pause(top)
+	}
+}
+
+// HandleEvent implements the screen.Handler interface.
+func (lv *LogView) HandleEvent(ev tcell.Event) bool {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		return lv.handle_key(ev)
+	case *tcell.EventMouse:
+		switch buttons := ev.Buttons(); {
+		case buttons&tcell.WheelUp != 0:
+			lv.scroll(-3)
+		case buttons&tcell.WheelDown != 0:
+			lv.scroll(3)
+		default:
+			return false
+		}
+
+		return true
+	}
+
+	return false
+}
+
+// handle_key is a helper method that handles a key.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the key was consumed.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) handle_key(ev *tcell.EventKey) bool {
+	page := max(lv.height-1, 1)
+
+	switch ev.Key() {
+	case tcell.KeyUp:
+		lv.scroll(-1)
+	case tcell.KeyDown:
+		lv.scroll(1)
+	case tcell.KeyPgUp:
+		lv.scroll(-page)
+	case tcell.KeyPgDn:
+		lv.scroll(page)
+	case tcell.KeyHome:
+		top := lv.oldest()
+
+		if lv.count > 0 && !lv.matches(lv.at(top)) {
+			top = lv.walk(top, 1)
+		}
+
+		lv.pause(top)
+		lv.scroll(0)
+	case tcell.KeyEnd:
+		lv.follow = true
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(lv.query) == 0 {
+			return false
+		}
+
+		lv.query = lv.query[:len(lv.query)-1]
+	case tcell.KeyEscape:
+		if len(lv.query) == 0 {
+			return false
+		}
+
+		lv.query = nil
+	case tcell.KeyRune:
+		lv.query = append(lv.query, []rune(strings.ToLower(string(ev.Rune())))...)
+	default:
+		return false
+	}
+
+	return true
+}
+
+// status is a helper method that returns the text of the status row.
+//
+// Returns:
+//   - string: The text. Empty if the status row is not drawn.
+//
+// Assertions:
+//   - lv.mu is locked.
+func (lv *LogView) status() string {
+	var parts []string
+
+	if len(lv.query) > 0 {
+		parts = append(parts, "/"+string(lv.query))
+	}
+
+	if !lv.follow {
+		parts = append(parts, fmt.Sprintf("paused, %d new (End to follow)", lv.next-lv.paused_at))
+	}
+
+	return strings.Join(parts, "  ")
+}
+
+// Draw implements the screen.Drawer interface. The view fills the table; while the
+// lines are filtered or the view does not follow the newest lines, the last row
+// shows its status.
+func (lv *LogView) Draw(table *dtb.Table, x, y *int) error {
+	lv.mu.Lock()
+	defer lv.mu.Unlock()
+
+	width, height := table.Width(), table.Height()
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	text := lv.status()
+
+	if text != "" && height > 1 {
+		height--
+
+		muted := theme.RoleMuted.Style()
+		line := []rune(text)
+
+		for col := range width {
+			r := ' '
+			if col < len(line) {
+				r = line[col]
+			}
+
+			table.WriteAt(col, height, dtb.NewCell(r, muted))
+		}
+	}
+
+	lv.height = height
+
+	top := lv.top
+	if lv.follow {
+		top = lv.tail_top()
+	} else if top < lv.oldest() {
+		// The lines drawn were dropped from the buffer.
+		top = lv.oldest()
+		lv.top = top
+	}
+
+	if lv.count > 0 && top < lv.next && !lv.matches(lv.at(top)) {
+		top = lv.walk(top, 1)
+	}
+
+	style := theme.RoleText.Style()
+	row := 0
+
+	for seq := top; seq < lv.next && row < height; seq++ {
+		entry := lv.at(seq)
+		if !lv.matches(entry) {
+			continue
+		}
+
+		cells := entry.cells
+		if entry.spans != nil {
+			cells = span_cells(entry.spans)
+		}
+
+		for col := range width {
+			cell := dtb.NewCell(' ', style)
+
+			if col < len(cells) && cells[col] != nil {
+				cell = cells[col]
+			}
+
+			table.WriteAt(col, row, cell)
+		}
+
+		row++
+	}
+
+	for ; row < height; row++ {
+		for col := range width {
+			table.WriteAt(col, row, dtb.NewCell(' ', style))
+		}
+	}
+
+	return nil
+}
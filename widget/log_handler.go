@@ -0,0 +1,285 @@
+package widget
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"strconv"
+	"strings"
+	"unicode"
+
+	"github.com/PlayerR9/display/theme"
+)
+
+const (
+	// DefaultLogTimeFormat is the default layout of the times of the records of a
+	// log handler.
+	DefaultLogTimeFormat string = "15:04:05.000"
+)
+
+// LogHandlerOptions are the options of a log handler.
+type LogHandlerOptions struct {
+	// Level is the lowest level of the records handled. Nil for slog.LevelInfo.
+	Level slog.Leveler
+
+	// TimeFormat is the layout of the times of the records, as in time.Format.
+	// Empty for DefaultLogTimeFormat.
+	TimeFormat string
+
+	// Tee is where the records are also written, in the format of
+	// slog.TextHandler; for instance, a log file. Nil for none.
+	Tee io.Writer
+}
+
+// LogHandler is a slog.Handler that formats records into styled text and appends
+// it to a log view; the styles follow the current theme. Like the log view, it is
+// safe to use from any goroutine, so the logs of background workers show in the
+// view instead of being written over the screen.
+//
+// Records are formatted as the time, the level, the message and the attributes as
+// key=value pairs; the keys of the attributes in groups are prefixed with the
+// groups, separated by dots.
+type LogHandler struct {
+	// view is the log view the records are appended to.
+	view *LogView
+
+	// level is the lowest level of the records handled.
+	level slog.Leveler
+
+	// time_format is the layout of the times of the records.
+	time_format string
+
+	// prefix is the prefix of the keys of the attributes, made of the open groups.
+	prefix string
+
+	// attrs are the runs of text of the attributes added with WithAttrs.
+	attrs []log_span
+
+	// tee is the handler that writes to the tee. Nil for none.
+	tee slog.Handler
+}
+
+// NewLogHandler creates a new log handler.
+//
+// Parameters:
+//   - view: The log view the records are appended to.
+//   - opts: The options. Nil for the defaults.
+//
+// Returns:
+//   - *LogHandler: The new log handler. Nil if view is nil.
+func NewLogHandler(view *LogView, opts *LogHandlerOptions) *LogHandler {
+	if view == nil {
+		return nil
+	}
+
+	if opts == nil {
+		opts = &LogHandlerOptions{}
+	}
+
+	h := &LogHandler{
+		view:        view,
+		level:       opts.Level,
+		time_format: opts.TimeFormat,
+	}
+
+	if h.level == nil {
+		h.level = slog.LevelInfo
+	}
+
+	if h.time_format == "" {
+		h.time_format = DefaultLogTimeFormat
+	}
+
+	if opts.Tee != nil {
+		h.tee = slog.NewTextHandler(opts.Tee, &slog.HandlerOptions{
+			Level: h.level,
+		})
+	}
+
+	return h
+}
+
+// level_label_role is a helper function that returns the role of the label of a
+// level.
+//
+// Parameters:
+//   - level: The level.
+//
+// Returns:
+//   - theme.Role: The role.
+func level_label_role(level slog.Level) theme.Role {
+	switch {
+	case level >= slog.LevelError:
+		return theme.RoleError
+	case level >= slog.LevelWarn:
+		return theme.RoleWarning
+	case level >= slog.LevelInfo:
+		return theme.RoleSuccess
+	default:
+		return theme.RoleMuted
+	}
+}
+
+// quote is a helper function that quotes a value if it would be ambiguous in a
+// key=value pair.
+//
+// Parameters:
+//   - s: The value.
+//
+// Returns:
+//   - string: The value, quoted if needed.
+func quote(s string) string {
+	if s == "" {
+		return `""`
+	}
+
+	for _, r := range s {
+		if r == '=' || r == '"' || unicode.IsSpace(r) || !unicode.IsPrint(r) {
+			return strconv.Quote(s)
+		}
+	}
+
+	return s
+}
+
+// append_text is a helper function that appends a run of text to spans.
+//
+// Parameters:
+//   - spans: The runs of text.
+//   - text: The text. Newlines and other control characters are written as
+//     spaces.
+//   - role: The role of the style of the text.
+//
+// Returns:
+//   - []log_span: The runs of text.
+func append_text(spans []log_span, text string, role theme.Role) []log_span {
+	text = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) {
+			return ' '
+		}
+
+		return r
+	}, text)
+
+	return append(spans, log_span{
+		text: text,
+		role: role,
+	})
+}
+
+// append_attr is a helper method that appends an attribute, preceded by a space,
+// to spans. Empty attributes and groups are skipped, and the attributes of groups
+// without a key are inlined.
+//
+// Parameters:
+//   - spans: The runs of text.
+//   - prefix: The prefix of the key.
+//   - attr: The attribute.
+//
+// Returns:
+//   - []log_span: The runs of text.
+func (h *LogHandler) append_attr(spans []log_span, prefix string, attr slog.Attr) []log_span {
+	attr.Value = attr.Value.Resolve()
+
+	if attr.Equal(slog.Attr{}) {
+		return spans
+	}
+
+	if attr.Value.Kind() == slog.KindGroup {
+		if attr.Key != "" {
+			prefix += attr.Key + "."
+		}
+
+		for _, a := range attr.Value.Group() {
+			spans = h.append_attr(spans, prefix, a)
+		}
+
+		return spans
+	}
+
+	var value string
+
+	if attr.Value.Kind() == slog.KindTime {
+		value = attr.Value.Time().Format(h.time_format)
+	} else {
+		value = attr.Value.String()
+	}
+
+	spans = append_text(spans, " "+prefix+attr.Key+"=", theme.RoleMuted)
+	spans = append_text(spans, quote(value), theme.RoleText)
+
+	return spans
+}
+
+// Enabled implements the slog.Handler interface.
+func (h *LogHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+// Handle implements the slog.Handler interface. The record is appended to the log
+// view as one line and, if any, written to the tee.
+func (h *LogHandler) Handle(ctx context.Context, r slog.Record) error {
+	var spans []log_span
+
+	if !r.Time.IsZero() {
+		spans = append_text(spans, r.Time.Format(h.time_format)+" ", theme.RoleMuted)
+	}
+
+	label := r.Level.String()
+	if n := len(label); n < 5 {
+		label += strings.Repeat(" ", 5-n)
+	}
+
+	spans = append_text(spans, label, level_label_role(r.Level))
+	spans = append_text(spans, " "+r.Message, theme.RoleText)
+	spans = append(spans, h.attrs...)
+
+	r.Attrs(func(attr slog.Attr) bool {
+		spans = h.append_attr(spans, h.prefix, attr)
+		return true
+	})
+
+	h.view.append_spans(r.Level, spans)
+
+	if h.tee == nil {
+		return nil
+	}
+
+	return h.tee.Handle(ctx, r)
+}
+
+// WithAttrs implements the slog.Handler interface.
+func (h *LogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+
+	h2 := *h
+	h2.attrs = append([]log_span(nil), h.attrs...)
+
+	for _, attr := range attrs {
+		h2.attrs = h.append_attr(h2.attrs, h.prefix, attr)
+	}
+
+	if h.tee != nil {
+		h2.tee = h.tee.WithAttrs(attrs)
+	}
+
+	return &h2
+}
+
+// WithGroup implements the slog.Handler interface.
+func (h *LogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+
+	h2 := *h
+	h2.prefix = h.prefix + name + "."
+
+	if h.tee != nil {
+		h2.tee = h.tee.WithGroup(name)
+	}
+
+	return &h2
+}
@@ -0,0 +1,181 @@
+package widget
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+func TestLogViewFollow(t *testing.T) {
+	lv := NewLogView(5)
+
+	var wg sync.WaitGroup
+
+	for i := range 4 {
+		wg.Add(1)
+
+		go func() {
+			defer wg.Done()
+
+			fmt.Fprintf(lv, "w%d\n", i)
+		}()
+	}
+
+	wg.Wait()
+
+	if lv.Len() != 4 {
+		t.Fatalf("Expected 4 lines, but got %d", lv.Len())
+	}
+
+	lv.Clear()
+
+	for i := range 8 {
+		lv.Append(slog.LevelInfo, fmt.Sprintf("line %d", i))
+	}
+
+	// Only the last 5 lines are kept and the tail is shown.
+	check := func(want ...string) {
+		t.Helper()
+
+		if got := draw_lines(t, lv, 16, 3); !slices.Equal(got, want) {
+			t.Fatalf("Expected %q, but got %q", want, got)
+		}
+	}
+
+	check("line 5          ", "line 6          ", "line 7          ")
+
+	lv.HandleEvent(tcell.NewEventKey(tcell.KeyUp, 0, tcell.ModNone))
+
+	if lv.Following() {
+		t.Fatalf("Expected scrolling up to pause following")
+	}
+
+	lv.Append(slog.LevelInfo, "line 8")
+
+	check("line 4          ", "line 5          ", "paused, 1 new (E")
+
+	for range 3 {
+		lv.HandleEvent(tcell.NewEventKey(tcell.KeyDown, 0, tcell.ModNone))
+	}
+
+	if !lv.Following() {
+		t.Fatalf("Expected scrolling to the bottom to follow again")
+	}
+
+	check("line 6          ", "line 7          ", "line 8          ")
+}
+
+func TestLogViewFilter(t *testing.T) {
+	lv := NewLogView(0)
+
+	lv.Append(slog.LevelDebug, "debug: cache miss")
+	lv.Append(slog.LevelInfo, "\x1b[1;31mERROR\x1b[0m disk full")
+	lv.Append(slog.LevelWarn, "disk almost full")
+	lv.SetFilter("DISK")
+
+	got := draw_lines(t, lv, 16, 3)
+	want := []string{"ERROR disk full ", "disk almost full", "/disk           "}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("Expected %q, but got %q", want, got)
+	}
+
+	lv.SetFilter("")
+	lv.SetLevel(slog.LevelWarn)
+
+	got = draw_lines(t, lv, 16, 2)
+	want = []string{"disk almost full", "                "}
+
+	if !slices.Equal(got, want) {
+		t.Fatalf("Expected %q, but got %q", want, got)
+	}
+
+	table, _ := dtb.NewTable(16, 1)
+	x, y := 0, 0
+
+	lv.SetLevel(slog.LevelInfo)
+	lv.SetFilter("error")
+	_ = lv.Draw(table, &x, &y)
+
+	fg, _, attrs := table.CellAt(0, 0).Style.Decompose()
+	if fg != tcell.ColorMaroon || attrs&tcell.AttrBold == 0 {
+		t.Errorf("Expected a bold red cell, but got %v %v", fg, attrs)
+	}
+}
+
+func TestLogHandler(t *testing.T) {
+	lv := NewLogView(0)
+
+	var tee strings.Builder
+
+	h := NewLogHandler(lv, &LogHandlerOptions{Tee: &tee})
+	logger := slog.New(h).With("worker", 3).WithGroup("req")
+
+	logger.Debug("hidden")
+
+	r := slog.NewRecord(time.Date(2024, 1, 2, 12, 30, 0, 0, time.UTC), slog.LevelWarn, "slow\nrequest", 0)
+	r.AddAttrs(slog.Int("id", 7), slog.String("path", "a b"), slog.Group("", slog.Bool("ok", false)))
+
+	err := logger.Handler().Handle(context.Background(), r)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	want := `12:30:00.000 WARN  slow request worker=3 req.id=7 req.path="a b" req.ok=false`
+
+	got := draw_lines(t, lv, len(want), 1)
+	if lv.Len() != 1 || got[0] != want {
+		t.Fatalf("Expected %q, but got %q", want, got)
+	}
+
+	if !strings.Contains(tee.String(), `level=WARN msg="slow\nrequest" worker=3 req.id=7`) {
+		t.Errorf("Expected the record to be teed, but got %q", tee.String())
+	}
+}
+
+func TestLogHandlerTheme(t *testing.T) {
+	lv := NewLogView(0)
+
+	logger := slog.New(NewLogHandler(lv, nil)).With("worker", 3)
+	logger.Warn("slow")
+
+	table, err := dtb.NewTable(40, 1)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	err = theme.Default.Use(theme.LightName)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+	defer theme.Default.Use(theme.DarkName)
+
+	// The styles are those of the theme used when drawn, not when logged.
+	x, y := 0, 0
+
+	err = lv.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	line := table.GetLines()[0]
+	label := strings.Index(line, "WARN")
+	key := strings.Index(line, "worker=")
+
+	if cell := table.CellAt(label, 0); cell == nil || cell.Style != theme.RoleWarning.Style() {
+		t.Errorf("Expected the label to have the warning style of the light theme")
+	}
+
+	if cell := table.CellAt(key, 0); cell == nil || cell.Style != theme.RoleMuted.Style() {
+		t.Errorf("Expected the key to have the muted style of the light theme")
+	}
+}
@@ -0,0 +1,668 @@
+package widget
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/PlayerR9/display/anim"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// partial_blocks are the characters that fill one to seven eighths of a cell, from
+// the left.
+var partial_blocks = [...]rune{'▏', '▎', '▍', '▌', '▋', '▊', '▉'}
+
+const (
+	// rate_window is the minimum time between two samples of the throughput of a
+	// progress bar.
+	rate_window time.Duration = 250 * time.Millisecond
+
+	// rate_smoothing is the weight of the newest sample in the throughput of a
+	// progress bar.
+	rate_smoothing float64 = 0.3
+
+	// bounce_step is the time it takes for the segment of an indeterminate
+	// progress bar to move by one cell.
+	bounce_step time.Duration = 60 * time.Millisecond
+)
+
+// fill_bar is a helper function that draws a bar filled from the left with
+// eighths of cells.
+//
+// Parameters:
+//   - table: The table to draw on.
+//   - x: The x coordinate of the bar.
+//   - y: The y coordinate of the bar.
+//   - width: The width of the bar.
+//   - fraction: The filled fraction, in [0, 1].
+//   - style: The style of the bar: the foreground is the filled part.
+func fill_bar(table *dtb.Table, x, y, width int, fraction float64, style tcell.Style) {
+	eighths := int(fraction * float64(width*8))
+
+	for i := range width {
+		r := ' '
+
+		switch {
+		case i < eighths/8:
+			r = '█'
+		case i == eighths/8 && eighths%8 > 0:
+			r = partial_blocks[eighths%8-1]
+		}
+
+		table.WriteAt(x+i, y, dtb.NewCell(r, style))
+	}
+}
+
+// format_quantity is a helper function that formats a quantity with an SI prefix
+// (e.g., 1.5 kB).
+//
+// Parameters:
+//   - value: The quantity.
+//   - unit: The unit. May be empty.
+//
+// Returns:
+//   - string: The formatted quantity.
+func format_quantity(value float64, unit string) string {
+	prefixes := [...]string{"", "k", "M", "G", "T", "P"}
+
+	i := 0
+
+	for math.Abs(value) >= 1000 && i < len(prefixes)-1 {
+		value /= 1000
+		i++
+	}
+
+	text := fmt.Sprintf("%.1f", value)
+
+	if suffix := prefixes[i] + unit; suffix != "" {
+		text += " " + suffix
+	}
+
+	return text
+}
+
+// format_duration is a helper function that formats a duration as m:ss or h:mm:ss.
+//
+// Parameters:
+//   - d: The duration.
+//
+// Returns:
+//   - string: The formatted duration.
+func format_duration(d time.Duration) string {
+	secs := int(d.Round(time.Second).Seconds())
+
+	if secs >= 3600 {
+		return fmt.Sprintf("%d:%02d:%02d", secs/3600, secs/60%60, secs%60)
+	}
+
+	return fmt.Sprintf("%d:%02d", secs/60, secs%60)
+}
+
+// Progress is a progress bar. It is determinate when its total is positive and
+// indeterminate otherwise, in which case a segment bounces along the bar. It is a
+// table.Displayer that draws one row, so it can be used in screen layouts,
+// Display/format sections and inline regions alike.
+//
+// The row holds the label, the bar and, for determinate bars, the percentage, the
+// throughput and the estimated time left, as enabled.
+type Progress struct {
+	// total is the value at which the work is done. Non-positive for an
+	// indeterminate bar.
+	total float64
+
+	// current is the current value.
+	current float64
+
+	// label is the text before the bar.
+	label string
+
+	// width is the width of the row. Non-positive to fill the table.
+	width int
+
+	// show_percent, show_rate and show_eta enable the parts after the bar.
+	show_percent, show_rate, show_eta bool
+
+	// unit is the unit of the values, used by the throughput.
+	unit string
+
+	// clock is the clock used to measure the throughput and to animate
+	// indeterminate bars.
+	clock anim.Clock
+
+	// start is when the bar was created.
+	start time.Time
+
+	// sample_time and sample_value are the last sample of the throughput.
+	sample_time  time.Time
+	sample_value float64
+
+	// rate is the smoothed throughput, per second.
+	rate float64
+
+	// done is true once Finish is called.
+	done bool
+
+	// mu is the mutex of the progress bar.
+	mu sync.Mutex
+}
+
+// NewProgress creates a new progress bar that shows the percentage and the
+// estimated time left.
+//
+// Parameters:
+//   - total: The value at which the work is done. Non-positive for an
+//     indeterminate bar.
+//
+// Returns:
+//   - *Progress: The new progress bar. Never returns nil.
+func NewProgress(total float64) *Progress {
+	p := &Progress{
+		total:        total,
+		show_percent: true,
+		show_eta:     true,
+	}
+
+	p.SetClock(anim.RealClock{})
+
+	return p
+}
+
+// SetClock changes the clock of the progress bar and restarts its measures; for
+// instance, to use the clock of the animator of a screen or a virtual clock in
+// tests. Does nothing with a nil receiver or a nil clock.
+//
+// Parameters:
+//   - clock: The clock.
+func (p *Progress) SetClock(clock anim.Clock) {
+	if p == nil || clock == nil {
+		return
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := clock.Now()
+
+	p.clock = clock
+	p.start = now
+	p.sample_time = now
+	p.sample_value = p.current
+	p.rate = 0
+}
+
+// SetTotal changes the value at which the work is done. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - total: The total. Non-positive for an indeterminate bar.
+func (p *Progress) SetTotal(total float64) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.total = total
+	p.mu.Unlock()
+}
+
+// SetLabel changes the text before the bar. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - label: The text. Empty for none.
+func (p *Progress) SetLabel(label string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.label = label
+	p.mu.Unlock()
+}
+
+// SetWidth changes the width of the row. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - width: The width. Non-positive to fill the table from the x coordinate.
+func (p *Progress) SetWidth(width int) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.width = width
+	p.mu.Unlock()
+}
+
+// Show enables or disables the parts drawn after the bar. Does nothing with a nil
+// receiver.
+//
+// Parameters:
+//   - percent: True to show the percentage.
+//   - rate: True to show the throughput.
+//   - eta: True to show the estimated time left.
+func (p *Progress) Show(percent, rate, eta bool) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+
+	p.show_percent = percent
+	p.show_rate = rate
+	p.show_eta = eta
+
+	p.mu.Unlock()
+}
+
+// SetUnit changes the unit of the values, shown by the throughput (e.g., "B" for
+// bytes). Does nothing with a nil receiver.
+//
+// Parameters:
+//   - unit: The unit. Empty for none.
+func (p *Progress) SetUnit(unit string) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.unit = unit
+	p.mu.Unlock()
+}
+
+// Set changes the current value. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - value: The value.
+func (p *Progress) Set(value float64) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.set(value)
+	p.mu.Unlock()
+}
+
+// Add adds to the current value. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - delta: The amount to add.
+func (p *Progress) Add(delta float64) {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+	p.set(p.current + delta)
+	p.mu.Unlock()
+}
+
+// Finish marks the work as done: a determinate bar is filled and an indeterminate
+// one stops moving. Does nothing with a nil receiver.
+func (p *Progress) Finish() {
+	if p == nil {
+		return
+	}
+
+	p.mu.Lock()
+
+	if p.total > 0 {
+		p.set(p.total)
+	}
+
+	p.done = true
+
+	p.mu.Unlock()
+}
+
+// set is a helper method that changes the current value and samples the
+// throughput.
+//
+// Parameters:
+//   - value: The value.
+//
+// Assertions:
+//   - p.mu is locked.
+func (p *Progress) set(value float64) {
+	p.current = value
+
+	now := p.clock.Now()
+
+	elapsed := now.Sub(p.sample_time)
+	if elapsed < rate_window {
+		return
+	}
+
+	sample := (value - p.sample_value) / elapsed.Seconds()
+
+	if p.rate == 0 {
+		p.rate = sample
+	} else {
+		p.rate = rate_smoothing*sample + (1-rate_smoothing)*p.rate
+	}
+
+	p.sample_time = now
+	p.sample_value = value
+}
+
+// Fraction returns the part of the work that is done.
+//
+// Returns:
+//   - float64: The fraction, in [0, 1]. 0 for an indeterminate bar.
+func (p *Progress) Fraction() float64 {
+	if p == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.fraction()
+}
+
+// fraction is a helper method that returns the part of the work that is done.
+//
+// Returns:
+//   - float64: The fraction, in [0, 1].
+//
+// Assertions:
+//   - p.mu is locked.
+func (p *Progress) fraction() float64 {
+	if p.total <= 0 {
+		return 0
+	}
+
+	return max(min(p.current/p.total, 1), 0)
+}
+
+// Rate returns the throughput.
+//
+// Returns:
+//   - float64: The smoothed throughput, per second.
+func (p *Progress) Rate() float64 {
+	if p == nil {
+		return 0
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.rate
+}
+
+// ETA returns the estimated time left, from the throughput.
+//
+// Returns:
+//   - time.Duration: The time left.
+//   - bool: False if it cannot be estimated yet or the bar is indeterminate.
+func (p *Progress) ETA() (time.Duration, bool) {
+	if p == nil {
+		return 0, false
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.eta()
+}
+
+// eta is a helper method that returns the estimated time left.
+//
+// Returns:
+//   - time.Duration: The time left.
+//   - bool: False if it cannot be estimated.
+//
+// Assertions:
+//   - p.mu is locked.
+func (p *Progress) eta() (time.Duration, bool) {
+	if p.total <= 0 || p.rate <= 0 {
+		return 0, false
+	}
+
+	left := max(p.total-p.current, 0) / p.rate
+
+	return time.Duration(left * float64(time.Second)), true
+}
+
+// Step implements the anim.Animation interface: an indeterminate bar keeps the
+// animator, and so the redraws, running until it is finished.
+func (p *Progress) Step(now time.Time) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return p.total <= 0 && !p.done
+}
+
+// suffix is a helper method that returns the text drawn after the bar.
+//
+// Returns:
+//   - string: The text.
+//
+// Assertions:
+//   - p.mu is locked.
+func (p *Progress) suffix() string {
+	if p.total <= 0 {
+		return ""
+	}
+
+	var parts []string
+
+	if p.show_percent {
+		parts = append(parts, fmt.Sprintf("%3.0f%%", p.fraction()*100))
+	}
+
+	if p.show_rate {
+		parts = append(parts, format_quantity(p.rate, p.unit)+"/s")
+	}
+
+	if p.show_eta {
+		if eta, ok := p.eta(); ok {
+			parts = append(parts, "ETA "+format_duration(eta))
+		} else if p.fraction() < 1 {
+			parts = append(parts, "ETA -:--")
+		}
+	}
+
+	if len(parts) == 0 {
+		return ""
+	}
+
+	return " " + strings.Join(parts, " ")
+}
+
+// Draw implements the table.Displayer interface. The row is drawn at the given
+// coordinates, which are left after its last cell.
+func (p *Progress) Draw(table *dtb.Table, x, y *int) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	width := p.width
+	if width <= 0 {
+		width = table.Width() - *x
+	}
+
+	if width <= 0 {
+		return nil
+	}
+
+	text_style := theme.RoleText.Style()
+	muted_style := theme.RoleMuted.Style()
+	bar_style := theme.RoleProgress.Style()
+
+	col := *x
+
+	write := func(text string, style tcell.Style) {
+		for _, r := range text {
+			if col >= *x+width {
+				return
+			}
+
+			table.WriteAt(col, *y, dtb.NewCell(r, style))
+			col++
+		}
+	}
+
+	if p.label != "" {
+		write(p.label+" ", text_style)
+	}
+
+	suffix := p.suffix()
+	bar := max(*x+width-col-len([]rune(suffix)), 0)
+
+	switch {
+	case p.total > 0:
+		fill_bar(table, col, *y, bar, p.fraction(), bar_style)
+	case bar > 0:
+		for i := range bar {
+			table.WriteAt(col+i, *y, dtb.NewCell(' ', bar_style))
+		}
+
+		if !p.done {
+			segment := max(bar/4, 1)
+			span := bar - segment
+
+			pos := 0
+
+			if span > 0 {
+				step := int(p.clock.Now().Sub(p.start)/bounce_step) % (2 * span)
+				pos = min(step, 2*span-step)
+			}
+
+			for i := range segment {
+				table.WriteAt(col+pos+i, *y, dtb.NewCell('█', bar_style))
+			}
+		}
+	}
+
+	col += bar
+
+	write(suffix, muted_style)
+
+	*x = col
+
+	return nil
+}
+
+// Gauge is a percentage gauge: a bar filled from the left with its label and
+// percentage centred over it. It is a table.Displayer that draws one row.
+type Gauge struct {
+	// value is the filled fraction, in [0, 1].
+	value float64
+
+	// label is the text drawn before the percentage.
+	label string
+
+	// width is the width of the gauge. Non-positive to fill the table.
+	width int
+
+	// mu is the mutex of the gauge.
+	mu sync.Mutex
+}
+
+// NewGauge creates a new, empty gauge.
+//
+// Parameters:
+//   - label: The text drawn before the percentage. Empty for none.
+//
+// Returns:
+//   - *Gauge: The new gauge. Never returns nil.
+func NewGauge(label string) *Gauge {
+	return &Gauge{
+		label: label,
+	}
+}
+
+// Set changes the filled fraction of the gauge. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - value: The fraction. Clamped to [0, 1].
+func (g *Gauge) Set(value float64) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	g.value = max(min(value, 1), 0)
+	g.mu.Unlock()
+}
+
+// Value returns the filled fraction of the gauge.
+//
+// Returns:
+//   - float64: The fraction, in [0, 1].
+func (g *Gauge) Value() float64 {
+	if g == nil {
+		return 0
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	return g.value
+}
+
+// SetWidth changes the width of the gauge. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - width: The width. Non-positive to fill the table from the x coordinate.
+func (g *Gauge) SetWidth(width int) {
+	if g == nil {
+		return
+	}
+
+	g.mu.Lock()
+	g.width = width
+	g.mu.Unlock()
+}
+
+// Draw implements the table.Displayer interface. The gauge is drawn at the given
+// coordinates, which are left after its last cell.
+func (g *Gauge) Draw(table *dtb.Table, x, y *int) error {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	width := g.width
+	if width <= 0 {
+		width = table.Width() - *x
+	}
+
+	if width <= 0 {
+		return nil
+	}
+
+	bar_style := theme.RoleProgress.Style()
+	filled_style := bar_style.Reverse(true)
+
+	fill_bar(table, *x, *y, width, g.value, bar_style)
+
+	text := fmt.Sprintf("%.0f%%", g.value*100)
+	if g.label != "" {
+		text = g.label + " " + text
+	}
+
+	chars := []rune(text)
+	if len(chars) > width {
+		chars = chars[len(chars)-width:]
+	}
+
+	start := (width - len(chars)) / 2
+	filled := int(g.value * float64(width))
+
+	for i, r := range chars {
+		style := bar_style
+		if start+i < filled {
+			style = filled_style
+		}
+
+		table.WriteAt(*x+start+i, *y, dtb.NewCell(r, style))
+	}
+
+	*x += width
+
+	return nil
+}
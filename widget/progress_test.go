@@ -0,0 +1,70 @@
+package widget
+
+import (
+	"testing"
+	"time"
+
+	"github.com/PlayerR9/display/anim"
+)
+
+func TestProgress(t *testing.T) {
+	clock := anim.NewVirtualClock(time.Unix(0, 0))
+
+	p := NewProgress(1000)
+	p.SetClock(clock)
+	p.SetLabel("copy")
+	p.SetUnit("B")
+	p.Show(true, true, true)
+
+	for range 4 {
+		clock.Advance(time.Second)
+		p.Add(100)
+	}
+
+	if rate := p.Rate(); rate != 100 {
+		t.Fatalf("Expected a rate of 100/s, but got %f", rate)
+	}
+
+	if eta, ok := p.ETA(); !ok || eta != 6*time.Second {
+		t.Fatalf("Expected an ETA of 6s, but got %s (%t)", eta, ok)
+	}
+
+	// 40% of a 7-cell bar is 2 cells and 6 eighths.
+	want := "copy ██▊      40% 100.0 B/s ETA 0:06"
+
+	if got := draw_lines(t, p, 36, 1)[0]; got != want {
+		t.Fatalf("Expected %q, but got %q", want, got)
+	}
+}
+
+func TestProgressIndeterminate(t *testing.T) {
+	clock := anim.NewVirtualClock(time.Unix(0, 0))
+
+	p := NewProgress(0)
+	p.SetClock(clock)
+
+	clock.Advance(3 * bounce_step)
+
+	if got := draw_lines(t, p, 8, 1)[0]; got != "   ██   " {
+		t.Fatalf("Expected the segment in the middle, but got %q", got)
+	}
+
+	if !p.Step(clock.Now()) {
+		t.Fatalf("Expected the animation to run")
+	}
+
+	p.Finish()
+
+	if p.Step(clock.Now()) {
+		t.Fatalf("Expected the animation to stop")
+	}
+}
+
+func TestGauge(t *testing.T) {
+	g := NewGauge("cpu")
+	g.Set(0.5)
+
+	if got := draw_lines(t, g, 12, 1)[0]; got != "██cpu 50%   " {
+		t.Fatalf("Expected %q, but got %q", "██cpu 50%   ", got)
+	}
+}
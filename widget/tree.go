@@ -0,0 +1,724 @@
+package widget
+
+import (
+	"slices"
+	"strings"
+	"sync"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/PlayerR9/display/theme"
+	"github.com/gdamore/tcell"
+)
+
+// TreeProvider provides the nodes of a tree view. The children of a node are only
+// asked for when the node is first expanded.
+type TreeProvider[T any] interface {
+	// Roots returns the top-level nodes.
+	//
+	// Returns:
+	//   - []T: The nodes.
+	//   - error: An error if the nodes could not be loaded.
+	Roots() ([]T, error)
+
+	// Children returns the children of a node.
+	//
+	// Parameters:
+	//   - node: The node.
+	//
+	// Returns:
+	//   - []T: The children.
+	//   - error: An error if the children could not be loaded. It is shown next to
+	//     the node.
+	Children(node T) ([]T, error)
+
+	// HasChildren tells whether a node can be expanded, without loading its
+	// children.
+	//
+	// Parameters:
+	//   - node: The node.
+	//
+	// Returns:
+	//   - bool: True if the node can be expanded.
+	HasChildren(node T) bool
+
+	// Label returns the text of a node.
+	//
+	// Parameters:
+	//   - node: The node.
+	//
+	// Returns:
+	//   - string: The text. Assumed to not contain newlines.
+	Label(node T) string
+}
+
+// tree_item is a node of a tree view.
+type tree_item[T any] struct {
+	// value is the node of the provider.
+	value T
+
+	// parent is the parent item. Nil for the roots.
+	parent *tree_item[T]
+
+	// children are the child items, once loaded.
+	children []*tree_item[T]
+
+	// depth is the number of ancestors of the item.
+	depth int
+
+	// last is true if the item is the last child of its parent.
+	last bool
+
+	// loaded is true once the children were asked to the provider.
+	loaded bool
+
+	// expanded is true if the children are shown.
+	expanded bool
+
+	// err is the error of the last loading of the children.
+	err error
+}
+
+// TreeView is an expandable tree of nodes, meant to be the element of a node of a
+// screen.Screen. Only the visible nodes are drawn.
+//
+// Keys:
+//   - Up/Down, PgUp/PgDn and Home/End move the cursor.
+//   - Right expands the node under the cursor or moves to its first child; Left
+//     collapses it or moves to its parent. Space toggles it and Enter activates it.
+//   - Other characters search the shown nodes for a label that contains them,
+//     ignoring case; Ctrl+N goes to the next match, Backspace edits the search and
+//     Esc clears it.
+//
+// With the mouse, a click on the marker of a node toggles it, a click elsewhere
+// moves the cursor, a click on the node under the cursor toggles or activates it
+// and the wheel scrolls.
+type TreeView[T any] struct {
+	// provider provides the nodes.
+	provider TreeProvider[T]
+
+	// roots are the top-level items.
+	roots []*tree_item[T]
+
+	// err is the error of the last loading of the roots.
+	err error
+
+	// rows are the shown items, in order.
+	rows []*tree_item[T]
+
+	// cursor is the index of the cursor in rows.
+	cursor int
+
+	// top is the index of the first row drawn.
+	top int
+
+	// follow is true if the next draw must scroll to the cursor.
+	follow bool
+
+	// height is the number of rows drawn in the last draw.
+	height int
+
+	// area is the area of the node of the tree view.
+	area ds.Rect
+
+	// query is the search.
+	query []rune
+
+	// on_activate is called with a node when it is activated.
+	on_activate func(node T)
+
+	// focused is true if the tree view has the focus.
+	focused bool
+
+	// pressed is true while the first mouse button is held, so that the motion
+	// events reported while dragging do not click again.
+	pressed bool
+
+	// mu is the mutex of the tree view.
+	mu sync.Mutex
+}
+
+// NewTreeView creates a new tree view and loads its roots.
+//
+// Parameters:
+//   - provider: The provider of the nodes.
+//
+// Returns:
+//   - *TreeView[T]: The new tree view. Nil if provider is nil.
+func NewTreeView[T any](provider TreeProvider[T]) *TreeView[T] {
+	if provider == nil {
+		return nil
+	}
+
+	tv := &TreeView[T]{
+		provider: provider,
+	}
+
+	tv.load_roots()
+
+	return tv
+}
+
+// Reload asks the provider for the roots again; every node is collapsed and its
+// children are loaded again when expanded. Does nothing with a nil receiver.
+func (tv *TreeView[T]) Reload() {
+	if tv == nil {
+		return
+	}
+
+	tv.mu.Lock()
+	tv.load_roots()
+	tv.mu.Unlock()
+}
+
+// Err returns the error of the last loading of the roots.
+//
+// Returns:
+//   - error: The error. Nil if the roots were loaded.
+func (tv *TreeView[T]) Err() error {
+	if tv == nil {
+		return nil
+	}
+
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
+	return tv.err
+}
+
+// OnActivate sets the function called when a node is activated with Enter or a
+// click. Does nothing with a nil receiver.
+//
+// Parameters:
+//   - fn: The function. Nil for none.
+func (tv *TreeView[T]) OnActivate(fn func(node T)) {
+	if tv == nil {
+		return
+	}
+
+	tv.mu.Lock()
+	tv.on_activate = fn
+	tv.mu.Unlock()
+}
+
+// Current returns the node under the cursor.
+//
+// Returns:
+//   - T: The node.
+//   - bool: False if the tree is empty.
+func (tv *TreeView[T]) Current() (T, bool) {
+	if tv == nil {
+		return *new(T), false
+	}
+
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
+	if tv.cursor >= len(tv.rows) {
+		return *new(T), false
+	}
+
+	return tv.rows[tv.cursor].value, true
+}
+
+// Expand expands or collapses the node under the cursor. Does nothing with a nil
+// receiver or if the node cannot be expanded.
+//
+// Parameters:
+//   - expanded: True to expand the node, false to collapse it.
+func (tv *TreeView[T]) Expand(expanded bool) {
+	if tv == nil {
+		return
+	}
+
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
+	if tv.cursor < len(tv.rows) {
+		tv.set_expanded(tv.rows[tv.cursor], expanded)
+	}
+}
+
+// load_roots is a helper method that loads the roots and resets the rows.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) load_roots() {
+	roots, err := tv.provider.Roots()
+
+	tv.err = err
+	tv.roots = tv.make_items(nil, roots)
+	tv.cursor = 0
+	tv.top = 0
+	tv.flatten()
+}
+
+// make_items is a helper method that wraps nodes into items.
+//
+// Parameters:
+//   - parent: The parent of the items. Nil for the roots.
+//   - values: The nodes.
+//
+// Returns:
+//   - []*tree_item[T]: The items.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) make_items(parent *tree_item[T], values []T) []*tree_item[T] {
+	depth := 0
+	if parent != nil {
+		depth = parent.depth + 1
+	}
+
+	items := make([]*tree_item[T], 0, len(values))
+
+	for i, value := range values {
+		items = append(items, &tree_item[T]{
+			value:  value,
+			parent: parent,
+			depth:  depth,
+			last:   i == len(values)-1,
+		})
+	}
+
+	return items
+}
+
+// flatten is a helper method that computes the shown items from the expanded ones
+// and keeps the cursor on its item or, if it is no longer shown, on its closest
+// shown ancestor.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) flatten() {
+	var current *tree_item[T]
+
+	if tv.cursor < len(tv.rows) {
+		current = tv.rows[tv.cursor]
+	}
+
+	tv.rows = tv.rows[:0]
+
+	var walk func(items []*tree_item[T])
+
+	walk = func(items []*tree_item[T]) {
+		for _, item := range items {
+			tv.rows = append(tv.rows, item)
+
+			if item.expanded {
+				walk(item.children)
+			}
+		}
+	}
+
+	walk(tv.roots)
+
+	for it := current; it != nil; it = it.parent {
+		if pos := slices.Index(tv.rows, it); pos != -1 {
+			tv.cursor = pos
+			break
+		}
+	}
+
+	tv.move(tv.cursor)
+}
+
+// set_expanded is a helper method that expands or collapses an item, loading its
+// children the first time it is expanded.
+//
+// Parameters:
+//   - item: The item.
+//   - expanded: True to expand the item, false to collapse it.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) set_expanded(item *tree_item[T], expanded bool) {
+	if expanded == item.expanded || !tv.provider.HasChildren(item.value) {
+		return
+	}
+
+	if expanded && !item.loaded {
+		children, err := tv.provider.Children(item.value)
+
+		item.err = err
+		item.children = tv.make_items(item, children)
+		item.loaded = err == nil
+	}
+
+	item.expanded = expanded
+	tv.flatten()
+}
+
+// move is a helper method that moves the cursor, clamped to the rows.
+//
+// Parameters:
+//   - pos: The index of the row.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) move(pos int) {
+	tv.cursor = max(min(pos, len(tv.rows)-1), 0)
+	tv.follow = true
+}
+
+// search is a helper method that moves the cursor to the first row, from the
+// given one, whose label contains the query.
+//
+// Parameters:
+//   - from: The index of the first row to check.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) search(from int) {
+	if len(tv.query) == 0 || len(tv.rows) == 0 {
+		return
+	}
+
+	query := strings.ToLower(string(tv.query))
+
+	for i := range len(tv.rows) {
+		pos := (from + i) % len(tv.rows)
+
+		if strings.Contains(strings.ToLower(tv.provider.Label(tv.rows[pos].value)), query) {
+			tv.move(pos)
+			return
+		}
+	}
+}
+
+// activate is a helper method that returns the function that activates the item
+// under the cursor, to be called once unlocked.
+//
+// Returns:
+//   - func(): The function. Nil if there is nothing to call.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) activate() func() {
+	fn := tv.on_activate
+
+	if tv.cursor >= len(tv.rows) || fn == nil {
+		return nil
+	}
+
+	value := tv.rows[tv.cursor].value
+
+	return func() {
+		fn(value)
+	}
+}
+
+// HandleEvent implements the screen.Handler interface.
+func (tv *TreeView[T]) HandleEvent(ev tcell.Event) bool {
+	tv.mu.Lock()
+
+	var consumed bool
+	var after func()
+
+	switch ev := ev.(type) {
+	case *tcell.EventKey:
+		consumed, after = tv.handle_key(ev)
+	case *tcell.EventMouse:
+		consumed, after = tv.handle_mouse(ev)
+	}
+
+	tv.mu.Unlock()
+
+	if after != nil {
+		after()
+	}
+
+	return consumed
+}
+
+// handle_key is a helper method that handles a key.
+//
+// Parameters:
+//   - ev: The key event.
+//
+// Returns:
+//   - bool: True if the key was consumed.
+//   - func(): The function to call once unlocked. Nil for none.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) handle_key(ev *tcell.EventKey) (bool, func()) {
+	page := max(tv.height-1, 1)
+
+	var item *tree_item[T]
+
+	if tv.cursor < len(tv.rows) {
+		item = tv.rows[tv.cursor]
+	}
+
+	switch ev.Key() {
+	case tcell.KeyUp:
+		tv.move(tv.cursor - 1)
+	case tcell.KeyDown:
+		tv.move(tv.cursor + 1)
+	case tcell.KeyPgUp:
+		tv.move(tv.cursor - page)
+	case tcell.KeyPgDn:
+		tv.move(tv.cursor + page)
+	case tcell.KeyHome:
+		tv.move(0)
+	case tcell.KeyEnd:
+		tv.move(len(tv.rows) - 1)
+	case tcell.KeyRight:
+		switch {
+		case item == nil:
+		case !item.expanded:
+			tv.set_expanded(item, true)
+		case len(item.children) > 0:
+			tv.move(tv.cursor + 1)
+		}
+	case tcell.KeyLeft:
+		switch {
+		case item == nil:
+		case item.expanded:
+			tv.set_expanded(item, false)
+		case item.parent != nil:
+			for pos, row := range tv.rows {
+				if row == item.parent {
+					tv.move(pos)
+					break
+				}
+			}
+		}
+	case tcell.KeyEnter:
+		return true, tv.activate()
+	case tcell.KeyCtrlN:
+		tv.search(tv.cursor + 1)
+	case tcell.KeyBackspace, tcell.KeyBackspace2:
+		if len(tv.query) == 0 {
+			return false, nil
+		}
+
+		tv.query = tv.query[:len(tv.query)-1]
+	case tcell.KeyEscape:
+		if len(tv.query) == 0 {
+			return false, nil
+		}
+
+		tv.query = nil
+	case tcell.KeyRune:
+		if ev.Rune() == ' ' && len(tv.query) == 0 {
+			if item != nil {
+				tv.set_expanded(item, !item.expanded)
+			}
+
+			break
+		}
+
+		tv.query = append(tv.query, ev.Rune())
+		tv.search(tv.cursor)
+	default:
+		return false, nil
+	}
+
+	return true, nil
+}
+
+// handle_mouse is a helper method that handles a mouse event.
+//
+// Parameters:
+//   - ev: The mouse event.
+//
+// Returns:
+//   - bool: True if the event was consumed.
+//   - func(): The function to call once unlocked. Nil for none.
+//
+// Assertions:
+//   - tv.mu is locked.
+func (tv *TreeView[T]) handle_mouse(ev *tcell.EventMouse) (bool, func()) {
+	switch buttons := ev.Buttons(); {
+	case buttons&tcell.WheelUp != 0:
+		tv.top = max(tv.top-3, 0)
+		tv.follow = false
+	case buttons&tcell.WheelDown != 0:
+		tv.top = max(min(tv.top+3, len(tv.rows)-tv.height), 0)
+		tv.follow = false
+	case buttons&tcell.Button1 != 0:
+		if tv.pressed {
+			return true, nil
+		}
+
+		x, y := ev.Position()
+
+		pos := tv.top + y - tv.area.Y
+		if pos < 0 || pos >= len(tv.rows) || y-tv.area.Y >= tv.height {
+			return false, nil
+		}
+
+		tv.pressed = true
+
+		item := tv.rows[pos]
+		marker := 2 * item.depth
+
+		on_marker := x-tv.area.X >= marker && x-tv.area.X < marker+2
+
+		switch {
+		case on_marker || (pos == tv.cursor && tv.provider.HasChildren(item.value)):
+			tv.move(pos)
+			tv.set_expanded(item, !item.expanded)
+		case pos == tv.cursor:
+			return true, tv.activate()
+		default:
+			tv.move(pos)
+		}
+	default:
+		if !tv.pressed {
+			return false, nil
+		}
+
+		tv.pressed = false
+	}
+
+	return true, nil
+}
+
+// SetFocused implements the screen.Focuser interface.
+func (tv *TreeView[T]) SetFocused(focused bool) {
+	tv.mu.Lock()
+	tv.focused = focused
+	tv.mu.Unlock()
+}
+
+// SetArea implements the screen.Arranger interface.
+func (tv *TreeView[T]) SetArea(area ds.Rect) {
+	tv.mu.Lock()
+	tv.area = area
+	tv.mu.Unlock()
+}
+
+// guides is a helper function that returns the indentation guides of an item: one
+// pair of cells per ancestor, the last pair joining the item to its parent.
+//
+// Parameters:
+//   - item: The item.
+//
+// Returns:
+//   - []rune: The guides.
+func guides[T any](item *tree_item[T]) []rune {
+	chars := make([]rune, 2*item.depth)
+
+	pos := len(chars) - 2
+
+	for it := item; it.parent != nil; it = it.parent {
+		switch {
+		case it == item && it.last:
+			chars[pos], chars[pos+1] = '└', '─'
+		case it == item:
+			chars[pos], chars[pos+1] = '├', '─'
+		case it.last:
+			chars[pos], chars[pos+1] = ' ', ' '
+		default:
+			chars[pos], chars[pos+1] = '│', ' '
+		}
+
+		pos -= 2
+	}
+
+	return chars
+}
+
+// Draw implements the screen.Drawer interface. The tree view fills the table;
+// while searching, the last row shows the search.
+func (tv *TreeView[T]) Draw(table *dtb.Table, x, y *int) error {
+	tv.mu.Lock()
+	defer tv.mu.Unlock()
+
+	width, height := table.Width(), table.Height()
+	if width <= 0 || height <= 0 {
+		return nil
+	}
+
+	text_style := theme.RoleText.Style()
+	guide_style := theme.RoleMuted.Style()
+	error_style := theme.RoleError.Style()
+
+	write := func(col, row int, chars []rune, style tcell.Style) int {
+		for _, r := range chars {
+			table.WriteAt(col, row, dtb.NewCell(r, style))
+			col++
+		}
+
+		return col
+	}
+
+	if len(tv.query) > 0 && height > 1 {
+		height--
+
+		for col := range width {
+			table.WriteAt(col, height, dtb.NewCell(' ', guide_style))
+		}
+
+		write(0, height, []rune("/"+string(tv.query)), guide_style)
+	}
+
+	tv.height = height
+
+	if tv.follow {
+		if tv.cursor < tv.top {
+			tv.top = tv.cursor
+		} else if tv.cursor >= tv.top+height {
+			tv.top = tv.cursor - height + 1
+		}
+
+		tv.follow = false
+	}
+
+	tv.top = max(min(tv.top, len(tv.rows)-height), 0)
+
+	for row := range height {
+		for col := range width {
+			table.WriteAt(col, row, dtb.NewCell(' ', text_style))
+		}
+	}
+
+	if tv.err != nil {
+		write(0, 0, []rune(tv.err.Error()), error_style)
+
+		return nil
+	}
+
+	for row := range height {
+		pos := tv.top + row
+		if pos >= len(tv.rows) {
+			break
+		}
+
+		item := tv.rows[pos]
+
+		col := write(0, row, guides(item), guide_style)
+
+		marker := "  "
+
+		if tv.provider.HasChildren(item.value) {
+			if item.expanded {
+				marker = "▾ "
+			} else {
+				marker = "▸ "
+			}
+		}
+
+		col = write(col, row, []rune(marker), guide_style)
+
+		label_style := text_style
+
+		switch {
+		case pos == tv.cursor && tv.focused:
+			label_style = theme.RoleMenuSelected.Style()
+		case pos == tv.cursor:
+			label_style = theme.RoleSelection.Style()
+		}
+
+		col = write(col, row, []rune(tv.provider.Label(item.value)), label_style)
+
+		if item.err != nil {
+			write(col, row, []rune(" ("+item.err.Error()+")"), error_style)
+		}
+	}
+
+	return nil
+}
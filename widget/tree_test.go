@@ -0,0 +1,133 @@
+package widget
+
+import (
+	"errors"
+	"slices"
+	"strings"
+	"testing"
+
+	ds "github.com/PlayerR9/display/screen"
+	dtb "github.com/PlayerR9/display/table"
+	"github.com/gdamore/tcell"
+)
+
+// path_provider is a tree provider of slash-separated paths.
+type path_provider struct {
+	children map[string][]string
+	loaded   []string
+}
+
+func (pp *path_provider) Roots() ([]string, error) {
+	return pp.children[""], nil
+}
+
+func (pp *path_provider) Children(node string) ([]string, error) {
+	pp.loaded = append(pp.loaded, node)
+
+	if node == "/broken" {
+		return nil, errors.New("denied")
+	}
+
+	return pp.children[node], nil
+}
+
+func (pp *path_provider) HasChildren(node string) bool {
+	_, ok := pp.children[node]
+	return ok || node == "/broken"
+}
+
+func (pp *path_provider) Label(node string) string {
+	return node[strings.LastIndexByte(node, '/')+1:]
+}
+
+func TestTreeView(t *testing.T) {
+	pp := &path_provider{
+		children: map[string][]string{
+			"":         {"/etc", "/usr", "/broken"},
+			"/etc":     {"/etc/hosts", "/etc/ssh"},
+			"/etc/ssh": {"/etc/ssh/config"},
+			"/usr":     {"/usr/bin"},
+		},
+	}
+
+	tv := NewTreeView[string](pp)
+
+	if len(pp.loaded) != 0 {
+		t.Fatalf("Expected no children to be loaded, but got %q", pp.loaded)
+	}
+
+	keys := []tcell.Key{tcell.KeyRight, tcell.KeyDown, tcell.KeyDown, tcell.KeyRight}
+
+	for _, key := range keys {
+		tv.HandleEvent(tcell.NewEventKey(key, 0, tcell.ModNone))
+	}
+
+	tv.HandleEvent(tcell.NewEventKey(tcell.KeyEnd, 0, tcell.ModNone))
+	tv.HandleEvent(tcell.NewEventKey(tcell.KeyRight, 0, tcell.ModNone))
+
+	table, err := dtb.NewTable(20, 7)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	x, y := 0, 0
+
+	err = tv.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	want := []string{
+		"▾ etc               ",
+		"├─  hosts           ",
+		"└─▾ ssh             ",
+		"  └─  config        ",
+		"▸ usr               ",
+		"▾ broken (denied)   ",
+		"                    ",
+	}
+
+	if got := table.GetLines(); !slices.Equal(got, want) {
+		t.Fatalf("Expected:\n%s\nbut got:\n%s", strings.Join(want, "\n"), strings.Join(got, "\n"))
+	}
+
+	for _, r := range "CON" {
+		tv.HandleEvent(tcell.NewEventKey(tcell.KeyRune, r, tcell.ModNone))
+	}
+
+	if node, _ := tv.Current(); node != "/etc/ssh/config" {
+		t.Fatalf("Expected the search to find %q, but got %q", "/etc/ssh/config", node)
+	}
+
+	tv.HandleEvent(tcell.NewEventKey(tcell.KeyEscape, 0, tcell.ModNone))
+	tv.HandleEvent(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone))
+	tv.HandleEvent(tcell.NewEventKey(tcell.KeyLeft, 0, tcell.ModNone))
+
+	if node, _ := tv.Current(); node != "/etc/ssh" {
+		t.Fatalf("Expected the cursor on the collapsed parent, but got %q", node)
+	}
+
+	// A click on the marker of usr expands it; motion while the button is held
+	// does not collapse it again.
+	tv.SetArea(ds.Rect{Width: 20, Height: 7})
+
+	// A press below the rows is not consumed and must not swallow the next press.
+	if tv.HandleEvent(tcell.NewEventMouse(0, 6, tcell.Button1, tcell.ModNone)) {
+		t.Fatalf("Expected a press below the rows not to be consumed")
+	}
+
+	tv.HandleEvent(tcell.NewEventMouse(0, 3, tcell.Button1, tcell.ModNone))
+	tv.HandleEvent(tcell.NewEventMouse(1, 3, tcell.Button1, tcell.ModNone))
+	tv.HandleEvent(tcell.NewEventMouse(1, 3, tcell.ButtonNone, tcell.ModNone))
+
+	x, y = 0, 0
+
+	err = tv.Draw(table, &x, &y)
+	if err != nil {
+		t.Fatalf("Expected no error, but got %s", err.Error())
+	}
+
+	if got := table.GetLines()[3]; got != "▾ usr               " {
+		t.Fatalf("Expected usr to be expanded, but got %q", got)
+	}
+}